@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/peterneutron/powerkit-go/pkg/powerkit"
+)
+
+// defaultCalibrationState is where calibrate persists its progress.
+const defaultCalibrationState = "/var/db/powerkit/calibration.json"
+
+// calibrateFlags are the options of the calibrate subcommands.
+type calibrateFlags struct {
+	state  string
+	config powerkit.CalibrationConfig
+}
+
+func handleCalibrateCommand(args []string) {
+	flags := calibrateFlags{state: defaultCalibrationState}
+	args, err := parseFlags(args, flags.set)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	if len(args) == 0 {
+		runCalibration(flags)
+		return
+	}
+
+	switch args[0] {
+	case calibrateStatus:
+		progress, err := powerkit.LoadCalibrationProgress(flags.state)
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		if progress == nil {
+			fmt.Println("No calibration has been started.")
+			return
+		}
+		jsonData, err := json.MarshalIndent(progress, "", "  ")
+		if err != nil {
+			log.Fatalf("Error formatting data to JSON: %v", err)
+		}
+		fmt.Println(string(jsonData))
+	case calibrateCancel:
+		checkRoot()
+		if err := newCalibration(flags).Cancel(); err != nil {
+			log.Fatalf("Error cancelling calibration: %v", err)
+		}
+		fmt.Println("Calibration cancelled; charging and adapter states restored.")
+	default:
+		log.Fatalf("Error: unknown subcommand '%s' for 'calibrate'.", args[0])
+	}
+}
+
+// runCalibration runs or resumes the calibration until it is done or
+// SIGINT or SIGTERM pauses it.
+func runCalibration(flags calibrateFlags) {
+	checkRoot()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	err := newCalibration(flags).Run(ctx, func(p powerkit.CalibrationProgress) {
+		line := fmt.Sprintf("%s  phase=%-9s charge=%d%%", p.UpdatedAt.Format(time.TimeOnly), p.Phase, p.Charge)
+		if p.Waiting != "" {
+			line += "  waiting: " + p.Waiting
+		}
+		fmt.Println(line)
+	})
+	switch {
+	case errors.Is(err, context.Canceled):
+		fmt.Println("Calibration paused; charging and adapter states restored.")
+		fmt.Println("Run 'powerkit-cli calibrate' to resume or 'powerkit-cli calibrate cancel' to discard it.")
+	case err != nil:
+		log.Fatalf("Error: %v", err)
+	default:
+		fmt.Println("Calibration complete.")
+	}
+}
+
+func newCalibration(flags calibrateFlags) *powerkit.Calibration {
+	cal, err := powerkit.NewCalibration(powerkit.Default(), flags.state, flags.config)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	return cal
+}
+
+func (f *calibrateFlags) set(name, value string) error {
+	switch name {
+	case flagState:
+		f.state = value
+	case flagHold:
+		hold, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid hold duration '%s'", value)
+		}
+		f.config.Hold = hold
+	case flagFloor:
+		floor, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid floor percentage '%s'", value)
+		}
+		f.config.Floor = floor
+	default:
+		return fmt.Errorf("unknown flag '%s'", name)
+	}
+	return nil
+}
@@ -25,4 +25,38 @@ const (
 	lpmGet      = "get"
 	lpmSet      = "set"
 	lpmToggle   = "toggle"
+	// smc
+	smcList     = "list"
+	flagPattern = "--pattern"
+	cmdSensors  = "sensors"
+	// fans
+	cmdFan  = "fan"
+	fanGet  = "get"
+	fanSet  = "set"
+	fanAuto = "auto"
+	// offline sources
+	cmdIOReg       = "ioreg"
+	cmdSnapshot    = "snapshot"
+	snapshotRecord = "record"
+	snapshotShow   = "show"
+	// firmware profiles
+	cmdProfile  = "profile"
+	profileShow = "show"
+	// daemon
+	cmdDaemon     = "daemon"
+	daemonStatus  = "status"
+	daemonLimit   = "limit"
+	daemonClear   = "clear"
+	daemonTopUp   = "topup"
+	daemonThermal = "thermal"
+	flagSocket    = "--socket"
+	flagState     = "--state"
+	flagMode      = "--mode"
+	flagGroup     = "--group"
+	// calibration
+	cmdCalibrate    = "calibrate"
+	calibrateStatus = "status"
+	calibrateCancel = "cancel"
+	flagHold        = "--hold"
+	flagFloor       = "--floor"
 )
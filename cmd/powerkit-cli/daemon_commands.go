@@ -0,0 +1,212 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"os/user"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/peterneutron/powerkit-go/pkg/daemon"
+	"github.com/peterneutron/powerkit-go/pkg/powerkit"
+)
+
+// defaultDaemonState is where the daemon persists its charge limit.
+const defaultDaemonState = "/var/db/powerkit/daemon.json"
+
+// daemonFlags are the options shared by the daemon subcommands.
+type daemonFlags struct {
+	socket string
+	state  string
+	mode   os.FileMode
+	group  string
+}
+
+func handleDaemonCommand(args []string) {
+	flags, args, err := parseDaemonFlags(args)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	if len(args) == 0 {
+		runDaemon(flags)
+		return
+	}
+
+	var status *daemon.Status
+	switch args[0] {
+	case daemonStatus:
+		status, err = callDaemon(flags, (*daemon.Client).Status)
+	case daemonLimit:
+		if len(args) != 3 {
+			log.Fatalf("Error: 'daemon limit' requires an upper and a lower charge percentage.")
+		}
+		limit := powerkit.ChargeLimit{Upper: parsePercent(args[1]), Lower: parsePercent(args[2])}
+		status, err = callDaemon(flags, func(c *daemon.Client) (*daemon.Status, error) { return c.SetLimit(limit) })
+	case daemonClear:
+		status, err = callDaemon(flags, (*daemon.Client).ClearLimit)
+	case daemonTopUp:
+		schedule := parseTopUp(args[1:])
+		status, err = callDaemon(flags, func(c *daemon.Client) (*daemon.Status, error) { return c.SetTopUp(schedule) })
+	case daemonThermal:
+		policy := parseThermal(args[1:])
+		status, err = callDaemon(flags, func(c *daemon.Client) (*daemon.Status, error) { return c.SetThermal(policy) })
+	default:
+		log.Fatalf("Error: unknown subcommand '%s' for 'daemon'.", args[0])
+	}
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	jsonData, err := json.MarshalIndent(status, "", "  ")
+	if err != nil {
+		log.Fatalf("Error formatting data to JSON: %v", err)
+	}
+	fmt.Println(string(jsonData))
+}
+
+// runDaemon serves the control socket until SIGINT or SIGTERM.
+func runDaemon(flags daemonFlags) {
+	checkRoot()
+	ln, err := daemon.Listen(flags.socket, flags.mode)
+	if err != nil {
+		log.Fatalf("Error listening on %s: %v", flags.socket, err)
+	}
+	defer func() { _ = os.Remove(flags.socket) }()
+	opts := []daemon.ServerOption{daemon.WithStatePath(flags.state)}
+	if flags.group != "" {
+		opts = append(opts, daemon.WithControlGroup(socketGroup(flags.socket, flags.group)))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	log.Printf("powerkit daemon listening on %s", flags.socket)
+	server := daemon.NewServer(powerkit.Default(), opts...)
+	if err := server.Serve(ctx, ln); err != nil {
+		log.Printf("Error serving daemon socket: %v", err)
+	}
+}
+
+// socketGroup hands the socket to the named group and returns its gid, so
+// members can connect when the mode grants group access.
+func socketGroup(socket, name string) string {
+	group, err := user.LookupGroup(name)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	gid, err := strconv.Atoi(group.Gid)
+	if err != nil {
+		log.Fatalf("Error: invalid gid '%s' for group '%s'", group.Gid, name)
+	}
+	if err := os.Chown(socket, -1, gid); err != nil {
+		log.Fatalf("Error setting the group of %s: %v", socket, err)
+	}
+	return group.Gid
+}
+
+func callDaemon(flags daemonFlags, call func(*daemon.Client) (*daemon.Status, error)) (*daemon.Status, error) {
+	client, err := daemon.Dial(flags.socket)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = client.Close() }()
+	return call(client)
+}
+
+// parseDaemonFlags extracts --socket, --state, --mode and --group and
+// returns the remaining arguments.
+func parseDaemonFlags(args []string) (daemonFlags, []string, error) {
+	flags := daemonFlags{socket: daemon.DefaultSocketPath, state: defaultDaemonState, mode: 0o660}
+	rest, err := parseFlags(args, flags.set)
+	return flags, rest, err
+}
+
+// parseFlags passes each --flag value or --flag=value in args to set and
+// returns the remaining arguments.
+func parseFlags(args []string, set func(name, value string) error) ([]string, error) {
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		name, value, ok := strings.Cut(args[i], "=")
+		if !strings.HasPrefix(name, "--") {
+			rest = append(rest, args[i])
+			continue
+		}
+		if !ok {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("flag '%s' requires a value", name)
+			}
+			i++
+			value = args[i]
+		}
+		if err := set(name, value); err != nil {
+			return nil, err
+		}
+	}
+	return rest, nil
+}
+
+func (f *daemonFlags) set(name, value string) error {
+	switch name {
+	case flagSocket:
+		f.socket = value
+	case flagState:
+		f.state = value
+	case flagMode:
+		mode, err := strconv.ParseUint(value, 8, 32)
+		if err != nil {
+			return fmt.Errorf("invalid socket mode '%s'", value)
+		}
+		f.mode = os.FileMode(mode)
+	case flagGroup:
+		f.group = value
+	default:
+		return fmt.Errorf("unknown flag '%s'", name)
+	}
+	return nil
+}
+
+// parseTopUp reads a top-up schedule such as "weekdays 08:00", or "off".
+func parseTopUp(args []string) *powerkit.TopUpSchedule {
+	if len(args) == 0 {
+		log.Fatalf("Error: 'daemon topup' requires a schedule such as 'weekdays 08:00', or 'off'.")
+	}
+	if len(args) == 1 && args[0] == actionOff {
+		return nil
+	}
+	schedule, err := powerkit.ParseTopUpSchedule(strings.Join(args, " "))
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	return &schedule
+}
+
+// parseThermal reads "<ceiling> <resume>" in degrees Celsius, with the
+// default dwell times, or "off".
+func parseThermal(args []string) *powerkit.ThermalPolicy {
+	if len(args) == 1 && args[0] == actionOff {
+		return nil
+	}
+	if len(args) != 2 {
+		log.Fatalf("Error: 'daemon thermal' requires a ceiling and a resume temperature in °C, or 'off'.")
+	}
+	policy := powerkit.DefaultThermalPolicy()
+	for i, target := range []*float64{&policy.Ceiling, &policy.Resume} {
+		v, err := strconv.ParseFloat(args[i], 64)
+		if err != nil {
+			log.Fatalf("Error: invalid temperature '%s'.", args[i])
+		}
+		*target = v
+	}
+	return &policy
+}
+
+func parsePercent(s string) int {
+	v, err := strconv.Atoi(strings.TrimSuffix(s, "%"))
+	if err != nil {
+		log.Fatalf("Error: invalid charge percentage '%s'.", s)
+	}
+	return v
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/peterneutron/powerkit-go/pkg/powerkit"
+)
+
+func handleFanCommand(args []string) {
+	if len(args) < 1 {
+		log.Fatalf("Error: 'fan' requires a subcommand ('get', 'set', 'auto').")
+	}
+	switch args[0] {
+	case fanGet:
+		doFanGet()
+	case fanSet:
+		if len(args) < 3 {
+			log.Fatalf("Error: 'fan set' requires a fan index and a speed in rpm.")
+		}
+		doFanSet(parseFanIndex(args[1]), args[2])
+	case fanAuto:
+		if len(args) < 2 {
+			log.Fatalf("Error: 'fan auto' requires a fan index.")
+		}
+		doFanAuto(parseFanIndex(args[1]))
+	default:
+		log.Fatalf("Error: unknown subcommand '%s' for 'fan'.", args[0])
+	}
+}
+
+func doFanGet() {
+	fans, err := powerkit.GetFans()
+	if err != nil {
+		log.Fatalf("Error reading fans: %v", err)
+	}
+	if len(fans) == 0 {
+		fmt.Println("No fans found on this machine.")
+		return
+	}
+	for _, f := range fans {
+		fmt.Printf("Fan %d: %.0f rpm (target %.0f, range %.0f-%.0f), mode %s\n",
+			f.Index, f.ActualRPM, f.TargetRPM, f.MinRPM, f.MaxRPM, f.Mode)
+	}
+}
+
+func doFanSet(index int, rpmArg string) {
+	checkRoot()
+	rpm, err := strconv.ParseFloat(rpmArg, 64)
+	if err != nil {
+		log.Fatalf("Error: invalid speed '%s'. Use a number of rpm.", rpmArg)
+	}
+	if err := powerkit.SetFanSpeed(index, rpm); err != nil {
+		log.Fatalf("Error setting fan speed: %v", err)
+	}
+	fmt.Printf("Fan %d forced to %.0f rpm. Run 'powerkit-cli fan auto %d' to restore automatic control.\n", index, rpm, index)
+}
+
+func doFanAuto(index int) {
+	checkRoot()
+	if err := powerkit.SetFanAuto(index); err != nil {
+		log.Fatalf("Error restoring automatic fan control: %v", err)
+	}
+	fmt.Printf("Fan %d returned to automatic control.\n", index)
+}
+
+func parseFanIndex(arg string) int {
+	index, err := strconv.Atoi(arg)
+	if err != nil || index < 0 {
+		log.Fatalf("Error: invalid fan index '%s'.", arg)
+	}
+	return index
+}
@@ -2,9 +2,62 @@ package main
 
 import (
 	"log"
+	"os"
 	"os/user"
+	"path/filepath"
+
+	"github.com/peterneutron/powerkit-go/pkg/powerkit"
+)
+
+// Environment variables configuring the default client: the audit journal
+// for mutating commands and extra firmware profile files.
+const (
+	envAuditJournal = "POWERKIT_AUDIT_JOURNAL"
+	envAuditActor   = "POWERKIT_AUDIT_ACTOR"
+	envProfiles     = "POWERKIT_PROFILES"
 )
 
+// clientOptions configure the default client; setupClient fills them in.
+var clientOptions []powerkit.Option
+
+// setupClient installs the default client. Profile files listed in
+// POWERKIT_PROFILES are added to the built-in profiles, and every SMC write
+// is checked against the write policy of the resulting registry. Mutating
+// commands are recorded to the journal named by POWERKIT_AUDIT_JOURNAL; the
+// actor is POWERKIT_AUDIT_ACTOR, else the user who invoked sudo, else the
+// current user.
+func setupClient() {
+	registry := powerkit.NewProfileRegistry()
+	for _, path := range filepath.SplitList(os.Getenv(envProfiles)) {
+		if err := registry.LoadFile(path); err != nil {
+			log.Fatalf("Error loading firmware profiles: %v", err)
+		}
+	}
+	clientOptions = append(clientOptions, powerkit.WithProfileRegistry(registry), powerkit.WithWritePolicy(registry.WritePolicy()))
+
+	if path := os.Getenv(envAuditJournal); path != "" {
+		journal, err := powerkit.NewAuditJournal(path, 0, 0)
+		if err != nil {
+			log.Fatalf("Error opening audit journal: %v", err)
+		}
+		clientOptions = append(clientOptions, powerkit.WithAuditSink(journal), powerkit.WithAuditActor(auditActor()))
+	}
+	powerkit.SetDefault(powerkit.New(clientOptions...))
+}
+
+func auditActor() string {
+	if actor := os.Getenv(envAuditActor); actor != "" {
+		return actor
+	}
+	if actor := os.Getenv("SUDO_USER"); actor != "" {
+		return actor
+	}
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return ""
+}
+
 func checkRoot() {
 	currentUser, err := user.Current()
 	if err != nil {
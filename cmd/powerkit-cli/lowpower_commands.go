@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"log"
+	"strings"
 
 	"github.com/peterneutron/powerkit-go/pkg/powerkit"
 )
@@ -18,7 +19,11 @@ func handleLowPowerCommand(args []string) {
 		if len(args) < 2 {
 			log.Fatalf("Error: 'lowpower set' requires 'on' or 'off'.")
 		}
-		doLowPowerSet(args[1])
+		source := powerkit.PowerSourceAll
+		if len(args) > 2 {
+			source = parsePowerSource(args[2])
+		}
+		doLowPowerSet(args[1], source)
 	case lpmToggle:
 		doLowPowerToggle()
 	default:
@@ -35,29 +40,55 @@ func doLowPowerGet() {
 		fmt.Println("Low Power Mode: Not available")
 		return
 	}
+	fmt.Printf("Low Power Mode: %s\n", enabledLabel(enabled))
+
+	settings, err := powerkit.GetPowerSettings()
+	if err != nil {
+		return
+	}
+	printLowPowerModeSource("Battery", settings.Battery)
+	printLowPowerModeSource("AC", settings.AC)
+}
+
+func printLowPowerModeSource(name string, p *powerkit.PowerSettings) {
+	if p == nil || !p.Has("lowpowermode") {
+		return
+	}
+	fmt.Printf("  %-8s %s\n", name+":", enabledLabel(p.LowPowerMode))
+}
+
+func enabledLabel(enabled bool) string {
 	if enabled {
-		fmt.Println("Low Power Mode: Enabled")
-	} else {
-		fmt.Println("Low Power Mode: Disabled")
+		return "Enabled"
 	}
+	return "Disabled"
 }
 
-func doLowPowerSet(val string) {
+func parsePowerSource(arg string) powerkit.PowerSource {
+	switch source := powerkit.PowerSource(arg); source {
+	case powerkit.PowerSourceAll, powerkit.PowerSourceBattery, powerkit.PowerSourceAC, powerkit.PowerSourceUPS:
+		return source
+	default:
+		log.Fatalf("Error: invalid power source '%s'. Use 'all', 'battery', 'ac' or 'ups'.", arg)
+		return ""
+	}
+}
+
+func doLowPowerSet(val string, source powerkit.PowerSource) {
 	checkRoot()
+	var enable bool
 	switch val {
 	case actionOn:
-		if err := powerkit.SetLowPowerMode(true); err != nil {
-			log.Fatalf("Error enabling Low Power Mode: %v", err)
-		}
-		fmt.Println("Low Power Mode enabled.")
+		enable = true
 	case actionOff:
-		if err := powerkit.SetLowPowerMode(false); err != nil {
-			log.Fatalf("Error disabling Low Power Mode: %v", err)
-		}
-		fmt.Println("Low Power Mode disabled.")
+		enable = false
 	default:
 		log.Fatalf("Error: invalid argument '%s'. Use 'on' or 'off'.", val)
 	}
+	if err := powerkit.SetLowPowerModeFor(source, enable); err != nil {
+		log.Fatalf("Error setting Low Power Mode: %v", err)
+	}
+	fmt.Printf("Low Power Mode %s (%s).\n", strings.ToLower(enabledLabel(enable)), source)
 }
 
 func doLowPowerToggle() {
@@ -19,6 +19,7 @@ func run(argv []string) {
 
 	commandGroup := argv[1]
 	args := argv[2:]
+	setupClient()
 	if handleReadCommands(commandGroup, args) {
 		return
 	}
@@ -31,15 +32,30 @@ func run(argv []string) {
 
 func handleReadCommands(commandGroup string, args []string) bool {
 	switch commandGroup {
-	case "all", "smc", "iokit":
+	case "all", "iokit":
 		handleDumpCommand(commandGroup, args)
 		return true
+	case "smc":
+		handleSMCCommand(args)
+		return true
 	case "raw":
 		handleRawCommand(args)
 		return true
 	case "watch":
 		handleWatchCommand()
 		return true
+	case cmdSensors:
+		handleSensorsCommand(args)
+		return true
+	case cmdIOReg:
+		handleIORegCommand(args)
+		return true
+	case cmdSnapshot:
+		handleSnapshotCommand(args)
+		return true
+	case cmdProfile:
+		handleProfileCommand(args)
+		return true
 	}
 	return false
 }
@@ -66,6 +82,12 @@ func handleControlCommands(commandGroup string, args []string) {
 		handleAssertionCommand(args)
 	case cmdLowPower:
 		handleLowPowerCommand(args)
+	case cmdFan:
+		handleFanCommand(args)
+	case cmdDaemon:
+		handleDaemonCommand(args)
+	case cmdCalibrate:
+		handleCalibrateCommand(args)
 	case "help":
 		printUsage()
 	default:
@@ -83,19 +105,45 @@ func printUsage() {
 	fmt.Println("  all [fallback] Dump curated SystemInfo; append 'fallback' to force SMC adapter telemetry")
 	fmt.Println("  iokit        Dump curated SystemInfo from IOKit only")
 	fmt.Println("  smc          Dump curated SystemInfo from SMC only")
-	fmt.Println("  raw [keys...] Query for custom SMC keys (e.g., 'powerkit-cli raw FNum')")
+	fmt.Println("  smc list [--pattern TC*]  List every SMC key with its type, size and attribute byte")
+	fmt.Println("  ioreg <file> [fallback]  Dump SystemInfo from a saved 'ioreg -rw0 -c AppleSmartBattery -a' plist")
+	fmt.Println("  raw [keys...] Query for custom SMC keys or glob patterns (e.g., 'powerkit-cli raw FNum \"TC*\"')")
+	fmt.Println("  sensors [temperature|fan|voltage|current|power...]  Read labelled sensors from the curated catalog")
 	fmt.Println("  watch        Stream real-time power events as they happen")
 	fmt.Println("  magsafe get-color               Get the current Magsafe LED state")
-	fmt.Println("  lowpower get                    Get macOS Low Power Mode state")
-	fmt.Println("  lowpower set <on|off>           Set Low Power Mode (requires sudo)")
+	fmt.Println("  fan get                         Show fan speeds, target, range and mode")
+	fmt.Println("  lowpower get                    Get macOS Low Power Mode state, overall and per power source")
+	fmt.Println("  lowpower set <on|off> [all|battery|ac|ups]  Set Low Power Mode for all or one power source (requires sudo)")
 	fmt.Println("  lowpower toggle                 Toggle Low Power Mode (requires sudo)")
 	fmt.Println("  assertion status <system|display>   Show whether the assertion is active and its ID")
+	fmt.Println("  snapshot record <file> [keys...]    Capture raw readings (plus extra SMC keys) to a snapshot file")
+	fmt.Println("  snapshot show <file> [fallback]     Replay a snapshot file and dump its SystemInfo")
+	fmt.Println("  profile show                        Show the firmware profile in use and why it was chosen")
 	fmt.Println("\nControl Commands:")
 	fmt.Println("  adapter <on|off>                Enable or disable the adapter connection (requires sudo)")
 	fmt.Println("  charging <on|off>               Enable or disable battery charging (requires sudo)")
 	fmt.Println("  magsafe set-color <state>       Set the Magsafe LED state (system, off, amber, green, error-once, error-perm-slow, error-perm-fast, error-perm-off) (requires sudo)")
+	fmt.Println("  fan set <index> <rpm>           Force a fan to a target speed (requires sudo)")
+	fmt.Println("  fan auto <index>                Return a fan to automatic control (requires sudo)")
 	fmt.Println("  assertion create <system|display> [reason...]   Create a sleep assertion with optional reason")
 	fmt.Println("  assertion release <system|display>              Release a sleep assertion of the given type")
+	fmt.Println("\nDaemon Commands (all take [--socket <path>], default /var/run/powerkit.sock):")
+	fmt.Println("  daemon [--state <file>] [--mode <octal>] [--group <name>]  Own charging and adapter control and serve JSON-RPC on the socket (requires sudo)")
+	fmt.Println("                                  Only root, the daemon's user and --group members may change settings; anyone who can connect may read status")
+	fmt.Println("  daemon status                   Show the daemon's battery, control and limit status")
+	fmt.Println("  daemon limit <upper> <lower>    Stop charging at upper percent and resume at lower percent")
+	fmt.Println("  daemon clear                    Remove the charge limit and re-enable charging")
+	fmt.Println("  daemon topup <days> <HH:MM>|off Charge to 100% by the time on daily, weekdays, weekends or mon,tue,...")
+	fmt.Println("  daemon thermal <ceiling> <resume>|off  Pause charging above ceiling °C until the battery cools below resume °C")
+	fmt.Println("\nCalibration Commands (all take [--state <file>], default /var/db/powerkit/calibration.json):")
+	fmt.Println("  calibrate [--hold <dur>] [--floor <pct>]  Charge to 100%, hold, discharge to the floor on the adapter, recharge; resumes an interrupted run (requires sudo)")
+	fmt.Println("  calibrate status                Show the persisted calibration progress")
+	fmt.Println("  calibrate cancel                Discard the calibration and restore charging and adapter states (requires sudo)")
 	fmt.Println("\nOther Commands:")
 	fmt.Println("  help         Show this help message")
+	fmt.Println("\nEnvironment:")
+	fmt.Println("  POWERKIT_AUDIT_JOURNAL=<file>   Append an audit record for every change to this JSONL journal")
+	fmt.Println("  POWERKIT_AUDIT_ACTOR=<name>     Actor stored in audit records (default: the sudo user)")
+	fmt.Println("  POWERKIT_PROFILES=<file:...>    Colon-separated firmware profile files loaded over the built-in ones")
+	fmt.Println("  POWERKIT_FIRMWARE_PROFILE=<id>  Use this firmware profile instead of resolving one from the firmware version")
 }
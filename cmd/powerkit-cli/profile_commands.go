@@ -0,0 +1,25 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/peterneutron/powerkit-go/pkg/powerkit"
+)
+
+func handleProfileCommand(args []string) {
+	if len(args) != 1 || args[0] != profileShow {
+		log.Fatalf("Error: 'profile' requires the subcommand 'show'.")
+	}
+
+	resolution, err := powerkit.ResolveProfile()
+	jsonData, jsonErr := json.MarshalIndent(resolution, "", "  ")
+	if jsonErr != nil {
+		log.Fatalf("Error formatting data to JSON: %v", jsonErr)
+	}
+	fmt.Println(string(jsonData))
+	if err != nil {
+		log.Fatalf("Error resolving firmware profile: %v", err)
+	}
+}
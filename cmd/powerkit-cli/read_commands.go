@@ -1,9 +1,11 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"log"
+	"os"
 	"strings"
 	"time"
 
@@ -104,7 +106,7 @@ func optionsForAll(args []string) (powerkit.FetchOptions, error) {
 
 func handleRawCommand(keys []string) {
 	if len(keys) == 0 {
-		log.Fatalf("Error: 'raw' command requires at least one SMC key to query.\nUsage: powerkit-cli raw FNum TC0P")
+		log.Fatalf("Error: 'raw' command requires at least one SMC key to query.\nUsage: powerkit-cli raw FNum TC0P 'F[0-9]Ac' 'TC*'")
 	}
 	rawValues, err := powerkit.GetRawSMCValues(keys)
 	if err != nil {
@@ -115,13 +117,15 @@ func handleRawCommand(keys []string) {
 	for key := range rawValues {
 		val := rawValues[key]
 		formattedOutput[key] = struct {
-			DataType string `json:"DataType"`
-			DataSize int    `json:"DataSize"`
-			Data     string `json:"Data"`
+			DataType string                    `json:"DataType"`
+			DataSize int                       `json:"DataSize"`
+			Data     string                    `json:"Data"`
+			Decoded  *powerkit.DecodedSMCValue `json:"Decoded,omitempty"`
 		}{
 			DataType: val.DataType,
 			DataSize: val.DataSize,
 			Data:     fmt.Sprintf("%x", val.Data),
+			Decoded:  val.Decoded,
 		}
 	}
 
@@ -131,3 +135,53 @@ func handleRawCommand(keys []string) {
 	}
 	fmt.Println(string(jsonData))
 }
+
+func handleIORegCommand(args []string) {
+	if len(args) < 1 {
+		log.Fatalf("Error: 'ioreg' requires a dump file.\nUsage: ioreg -rw0 -c AppleSmartBattery -a > battery.plist; powerkit-cli ioreg battery.plist")
+	}
+	options, err := optionsForAll(args[1:])
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	options.QuerySMC = false
+
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		log.Fatalf("Error reading ioreg dump: %v", err)
+	}
+	reader, err := powerkit.NewIORegBatteryReader(bytes.NewReader(data))
+	if err != nil {
+		log.Fatalf("Error parsing ioreg dump: %v", err)
+	}
+
+	client := powerkit.New(powerkit.WithBackend(&powerkit.CompositeBackend{Battery: reader}))
+	info, err := client.GetSystemInfo(options)
+	if err != nil {
+		log.Fatalf("Error getting hardware info: %v", err)
+	}
+
+	jsonData, err := json.MarshalIndent(info.ToJSON(), "", "  ")
+	if err != nil {
+		log.Fatalf("Error formatting data to JSON: %v", err)
+	}
+	fmt.Println(string(jsonData))
+}
+
+func handleSensorsCommand(args []string) {
+	categories := make([]powerkit.SensorCategory, len(args))
+	for i, arg := range args {
+		categories[i] = powerkit.SensorCategory(strings.ToLower(arg))
+	}
+	readings, err := powerkit.GetSensors(categories...)
+	if err != nil {
+		log.Fatalf("Error reading sensors: %v", err)
+	}
+	if len(readings) == 0 {
+		fmt.Println("No catalog sensors found on this machine.")
+		return
+	}
+	for _, r := range readings {
+		fmt.Printf("%-12s %-24s %-4s %10.2f %s\n", r.Category, r.Name, r.Key, r.Value, r.Unit)
+	}
+}
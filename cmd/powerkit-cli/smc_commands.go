@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"path"
+	"strings"
+
+	"github.com/peterneutron/powerkit-go/pkg/powerkit"
+)
+
+func handleSMCCommand(args []string) {
+	if len(args) > 0 && args[0] == smcList {
+		handleSMCListCommand(args[1:])
+		return
+	}
+	handleDumpCommand("smc", args)
+}
+
+func handleSMCListCommand(args []string) {
+	pattern, err := parsePatternFlag(args)
+	if err != nil {
+		log.Fatalf("%v\nUsage: powerkit-cli smc list [--pattern TC*]", err)
+	}
+
+	keys, err := powerkit.ListSMCKeys()
+	if err != nil {
+		log.Fatalf("Error enumerating SMC keys: %v", err)
+	}
+
+	matched := 0
+	fmt.Printf("%-4s  %-4s  %4s  %-4s  %s\n", "KEY", "TYPE", "SIZE", "ATTR", "FLAGS")
+	for _, k := range keys {
+		if ok, _ := path.Match(pattern, k.Key); !ok {
+			continue
+		}
+		matched++
+		fmt.Printf("%-4s  %-4s  %4d  0x%02x  %s\n", k.Key, k.DataType, k.DataSize, k.Attributes, flagString(k.Flags))
+	}
+	fmt.Printf("\n%d of %d keys\n", matched, len(keys))
+}
+
+// parsePatternFlag reads an optional --pattern (or --pattern=) argument.
+// The pattern uses shell glob syntax: *, ? and [...] classes.
+func parsePatternFlag(args []string) (string, error) {
+	pattern := "*"
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == flagPattern && i+1 < len(args):
+			pattern = args[i+1]
+			i++
+		case strings.HasPrefix(args[i], flagPattern+"="):
+			pattern = strings.TrimPrefix(args[i], flagPattern+"=")
+		default:
+			return "", fmt.Errorf("unexpected argument '%s'", args[i])
+		}
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return "", fmt.Errorf("invalid pattern '%s': %w", pattern, err)
+	}
+	return pattern, nil
+}
+
+// flagString renders key flags as a fixed-width mask: r(ead), w(rite),
+// f(unction), c(onst), a(tomic), with '-' for unset flags.
+func flagString(f powerkit.SMCKeyFlags) string {
+	mask := []byte("-----")
+	for i, set := range []bool{f.Readable, f.Writable, f.Function, f.Const, f.Atomic} {
+		if set {
+			mask[i] = "rwfca"[i]
+		}
+	}
+	return string(mask)
+}
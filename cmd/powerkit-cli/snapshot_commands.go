@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/peterneutron/powerkit-go/pkg/powerkit"
+)
+
+func handleSnapshotCommand(args []string) {
+	if len(args) < 2 {
+		log.Fatalf("Error: 'snapshot' requires a subcommand and a file ('record <file>' or 'show <file>').")
+	}
+	switch args[0] {
+	case snapshotRecord:
+		doSnapshotRecord(args[1], args[2:])
+	case snapshotShow:
+		doSnapshotShow(args[1], args[2:])
+	default:
+		log.Fatalf("Error: unknown subcommand '%s' for 'snapshot'.", args[0])
+	}
+}
+
+func doSnapshotRecord(path string, extraKeys []string) {
+	snapshot, err := powerkit.CaptureSnapshot(extraKeys...)
+	if err != nil {
+		log.Fatalf("Error capturing snapshot: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := powerkit.WriteSnapshot(&buf, snapshot); err != nil {
+		log.Fatalf("Error encoding snapshot: %v", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		log.Fatalf("Error writing snapshot file: %v", err)
+	}
+	fmt.Printf("Snapshot written to %s\n", path)
+}
+
+func doSnapshotShow(path string, args []string) {
+	options, err := optionsForAll(args)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Fatalf("Error reading snapshot file: %v", err)
+	}
+
+	snapshot, err := powerkit.ReadSnapshot(bytes.NewReader(data))
+	if err != nil {
+		log.Fatalf("Error reading snapshot: %v", err)
+	}
+
+	client := powerkit.New(powerkit.WithBackend(powerkit.NewReplayBackend(snapshot)))
+	info, err := client.GetSystemInfo(options)
+	if err != nil {
+		log.Fatalf("Error replaying snapshot: %v", err)
+	}
+
+	jsonData, err := json.MarshalIndent(info.ToJSON(), "", "  ")
+	if err != nil {
+		log.Fatalf("Error formatting data to JSON: %v", err)
+	}
+	fmt.Println(string(jsonData))
+}
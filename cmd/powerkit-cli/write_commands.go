@@ -9,6 +9,9 @@ import (
 
 func handleWriteCommand(group string, args []string) {
 	checkRoot()
+	// Read the keys back so a write the SMC ignored is reported, and a
+	// half-applied legacy BCLM+BCDS change is undone.
+	powerkit.SetDefault(powerkit.New(append(clientOptions, powerkit.WithWriteVerification(true))...))
 
 	var err error
 	var successMsg string
@@ -0,0 +1,126 @@
+// Package audit provides an append-only, size-rotated JSON Lines journal.
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// Journal appends one JSON document per line to a file. When the file would
+// grow past maxBytes it is rotated to path.1, path.1 to path.2 and so on,
+// keeping maxBackups old files. It is safe for concurrent use.
+type Journal struct {
+	mu         sync.Mutex
+	path       string
+	maxBytes   int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+// Open opens or creates the journal at path with owner-only permissions.
+func Open(path string, maxBytes int64, maxBackups int) (*Journal, error) {
+	j := &Journal{path: path, maxBytes: maxBytes, maxBackups: maxBackups}
+	if err := j.open(); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+func (j *Journal) open() error {
+	f, err := os.OpenFile(j.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit journal %s: %w", j.path, err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to stat audit journal %s: %w", j.path, err)
+	}
+	j.file = f
+	j.size = info.Size()
+	return nil
+}
+
+// Append writes v as one JSON line, rotating first if needed. A failed
+// rotation keeps appending to the live file and is reported after the line
+// is written.
+func (j *Journal) Append(v any) error {
+	line, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit record: %w", err)
+	}
+	line = append(line, '\n')
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.file == nil {
+		return fmt.Errorf("audit journal %s is closed", j.path)
+	}
+	var rotateErr error
+	if j.maxBytes > 0 && j.size > 0 && j.size+int64(len(line)) > j.maxBytes {
+		rotateErr = j.rotate()
+		if j.file == nil {
+			return rotateErr
+		}
+	}
+	n, err := j.file.Write(line)
+	j.size += int64(n)
+	if err != nil {
+		return errors.Join(rotateErr, fmt.Errorf("failed to append to audit journal %s: %w", j.path, err))
+	}
+	return rotateErr
+}
+
+// rotate shifts the backups up by one, moves the live file to path.1 and
+// starts a new live file. With no backups the live file is truncated. When
+// the backups cannot be shifted, the live file is reopened for appending so
+// the journal stays usable.
+func (j *Journal) rotate() error {
+	if err := j.file.Close(); err != nil {
+		return fmt.Errorf("failed to close audit journal %s: %w", j.path, err)
+	}
+	j.file = nil
+
+	if err := j.shiftBackups(); err != nil {
+		return errors.Join(err, j.open())
+	}
+	return j.open()
+}
+
+// shiftBackups renames the live file and its backups up by one.
+func (j *Journal) shiftBackups() error {
+	if j.maxBackups > 0 {
+		_ = os.Remove(backupPath(j.path, j.maxBackups))
+		for i := j.maxBackups - 1; i >= 1; i-- {
+			if err := os.Rename(backupPath(j.path, i), backupPath(j.path, i+1)); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to rotate audit journal: %w", err)
+			}
+		}
+		if err := os.Rename(j.path, backupPath(j.path, 1)); err != nil {
+			return fmt.Errorf("failed to rotate audit journal: %w", err)
+		}
+	} else if err := os.Remove(j.path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to rotate audit journal: %w", err)
+	}
+	return nil
+}
+
+// Close closes the journal file.
+func (j *Journal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.file == nil {
+		return nil
+	}
+	err := j.file.Close()
+	j.file = nil
+	return err
+}
+
+func backupPath(path string, n int) string {
+	return fmt.Sprintf("%s.%d", path, n)
+}
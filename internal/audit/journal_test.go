@@ -0,0 +1,106 @@
+package audit
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func countLines(t *testing.T, path string) int {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open %s: %v", path, err)
+	}
+	n := 0
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		n++
+	}
+	_ = f.Close()
+	return n
+}
+
+func TestJournalAppendsAndRotates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	// Each record encodes to 10 bytes including the newline: 3 per file.
+	j, err := Open(path, 30, 2)
+	if err != nil {
+		t.Fatalf("Open returned error: %v", err)
+	}
+	for i := 0; i < 7; i++ {
+		if err := j.Append(map[string]int{"seq": i}); err != nil {
+			t.Fatalf("Append %d returned error: %v", i, err)
+		}
+	}
+	if err := j.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+
+	if got := countLines(t, path); got != 1 {
+		t.Fatalf("expected 1 line in the live file, got %d", got)
+	}
+	for _, backup := range []string{path + ".1", path + ".2"} {
+		if got := countLines(t, backup); got != 3 {
+			t.Fatalf("expected 3 lines in %s, got %d", backup, got)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Fatalf("expected at most 2 backups, stat .3: %v", err)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0o600 {
+		t.Fatalf("expected owner-only permissions, got %v", info.Mode().Perm())
+	}
+
+	j, err = Open(path, 30, 2)
+	if err != nil {
+		t.Fatalf("reopen returned error: %v", err)
+	}
+	if err := j.Append(map[string]int{"seq": 7}); err != nil {
+		t.Fatalf("Append after reopen returned error: %v", err)
+	}
+	_ = j.Close()
+	if got := countLines(t, path); got != 2 {
+		t.Fatalf("expected reopen to append, got %d lines", got)
+	}
+}
+
+func TestJournalSurvivesFailedRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	// A non-empty directory in place of the first backup makes the rename
+	// of the live file fail.
+	if err := os.MkdirAll(filepath.Join(path+".1", "blocker"), 0o700); err != nil {
+		t.Fatalf("MkdirAll returned error: %v", err)
+	}
+	j, err := Open(path, 30, 1)
+	if err != nil {
+		t.Fatalf("Open returned error: %v", err)
+	}
+	defer func() { _ = j.Close() }()
+	for i := 0; i < 3; i++ {
+		if err := j.Append(map[string]int{"seq": i}); err != nil {
+			t.Fatalf("Append %d returned error: %v", i, err)
+		}
+	}
+
+	if err := j.Append(map[string]int{"seq": 3}); err == nil {
+		t.Fatalf("expected the failed rotation to be reported")
+	}
+	if err := j.Append(map[string]int{"seq": 4}); err == nil {
+		t.Fatalf("expected the rotation to keep failing while the backup is blocked")
+	}
+	if got := countLines(t, path); got != 5 {
+		t.Fatalf("expected every record in the live file, got %d lines", got)
+	}
+
+	if err := os.RemoveAll(path + ".1"); err != nil {
+		t.Fatalf("RemoveAll returned error: %v", err)
+	}
+	if err := j.Append(map[string]int{"seq": 5}); err != nil {
+		t.Fatalf("Append after unblocking returned error: %v", err)
+	}
+	if got := countLines(t, path+".1"); got != 5 {
+		t.Fatalf("expected the live file rotated once unblocked, got %d lines", got)
+	}
+}
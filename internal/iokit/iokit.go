@@ -1,5 +1,3 @@
-//go:build darwin
-
 // Package iokit provides internal access to IOKit for both polling
 // and streaming power source data.
 package iokit
@@ -85,6 +83,7 @@ type RawData struct {
 	SourceVoltage      int
 	SourceAmperage     int
 	CellVoltages       []int
+	HasPowerTelemetry  bool // PowerTelemetryData was present
 	TelemetryAvailable bool
 	TelemetrySource    AdapterTelemetrySource
 	TelemetryReason    AdapterTelemetryReason
@@ -0,0 +1,69 @@
+package iokit
+
+import (
+	"log"
+	"sync"
+)
+
+var (
+	streamHooksMu   sync.RWMutex
+	beforeSleepHook func()
+)
+
+func setBeforeSleepHook(fn func()) {
+	streamHooksMu.Lock()
+	defer streamHooksMu.Unlock()
+	beforeSleepHook = fn
+}
+
+func runBeforeSleepHook() {
+	streamHooksMu.RLock()
+	hook := beforeSleepHook
+	streamHooksMu.RUnlock()
+	if hook == nil {
+		return
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("powerkit-go: before-sleep hook panicked: %v", r)
+		}
+	}()
+	hook()
+}
+
+func emitLossy(eventType InternalEventType) {
+	select {
+	case Events <- InternalEvent{Type: eventType}:
+	default:
+	}
+}
+
+func emitReliable(eventType InternalEventType) {
+	Events <- InternalEvent{Type: eventType}
+}
+
+func processWillSleepNotification(ack func()) {
+	runBeforeSleepHook()
+	emitReliable(SystemWillSleep)
+	if ack != nil {
+		ack()
+	}
+}
+
+// SetBeforeSleepHook installs the synchronous pre-sleep hook used by the
+// system event stream. The hook runs on the IOKit sleep callback path.
+func SetBeforeSleepHook(fn func()) {
+	setBeforeSleepHook(fn)
+}
+
+func pushBatteryUpdate() {
+	emitLossy(BatteryUpdate)
+}
+
+func pushWillSleep() {
+	processWillSleepNotification(nil)
+}
+
+func pushDidWake() {
+	emitReliable(SystemDidWake)
+}
@@ -0,0 +1,25 @@
+//go:build !darwin
+
+package iokit
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+)
+
+// FetchData always fails on platforms without an AppleSmartBattery service.
+func FetchData(_ bool) (*RawData, error) {
+	return nil, fmt.Errorf("iokit is not available on %s: %w", runtime.GOOS, errors.ErrUnsupported)
+}
+
+// FetchReading always fails on platforms without an AppleSmartBattery service.
+func FetchReading() (*RawData, error) {
+	return FetchData(false)
+}
+
+// StartMonitor is a no-op on platforms without IOKit notifications. Events
+// may still be pushed onto the Events channel by other producers.
+func StartMonitor() {
+	startOnce.Do(func() {})
+}
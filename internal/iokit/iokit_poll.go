@@ -174,32 +174,28 @@ int get_all_battery_info(c_battery_info *info) {
 */
 import "C"
 
-import (
-	"fmt"
-	"math"
+import "fmt"
 
-	"github.com/peterneutron/powerkit-go/internal/smc"
-)
-
-const (
-	minPlausibleAdapterMilliVolts = 4500
-	maxPlausibleAdapterMilliVolts = 50000
-	minPlausibleAdapterMilliAmps  = 50
-	maxPlausibleAdapterMilliAmps  = 10000
-	maxPlausibleAdapterMilliWatts = 200000
-)
-
-var (
-	getAllBatteryInfoFn = func(info *C.c_battery_info) C.int {
-		return C.get_all_battery_info(info)
-	}
-	smcFetchDataFn = smc.FetchData
-)
+var getAllBatteryInfoFn = func(info *C.c_battery_info) C.int {
+	return C.get_all_battery_info(info)
+}
 
 // FetchData retrieves the raw battery and power data from IOKit.
 // When forceFallback is true, SMC data will be used for adapter telemetry even if
 // PowerTelemetryData is present.
 func FetchData(forceFallback bool) (*RawData, error) {
+	data, err := FetchReading()
+	if err != nil {
+		return nil, err
+	}
+	data.ForceFallback = forceFallback
+	evaluateAdapterTelemetry(data, data.HasPowerTelemetry, forceFallback)
+	return data, nil
+}
+
+// FetchReading retrieves the battery and power data from IOKit exactly as
+// reported, before adapter telemetry is evaluated.
+func FetchReading() (*RawData, error) {
 	var cInfo C.c_battery_info
 
 	ret := getAllBatteryInfoFn(&cInfo)
@@ -232,14 +228,12 @@ func FetchData(forceFallback bool) (*RawData, error) {
 		AdapterDesc:        C.GoString(&cInfo.adapter_description[0]),
 		SourceVoltage:      int(cInfo.source_voltage),
 		SourceAmperage:     int(cInfo.source_amperage),
+		HasPowerTelemetry:  cInfo.has_power_telemetry != 0,
 		TelemetryAvailable: false,
 		TelemetrySource:    AdapterTelemetrySourceUnavailable,
 		TelemetryReason:    AdapterTelemetryReasonMissingIOKit,
-		ForceFallback:      forceFallback,
 	}
 
-	evaluateAdapterTelemetry(data, cInfo.has_power_telemetry != 0, forceFallback)
-
 	if cInfo.cell_voltage_count > 0 {
 		data.CellVoltages = make([]int, cInfo.cell_voltage_count)
 		cVoltagesPtr := &cInfo.cell_voltages
@@ -249,77 +243,3 @@ func FetchData(forceFallback bool) (*RawData, error) {
 	}
 	return data, nil
 }
-
-func evaluateAdapterTelemetry(data *RawData, hasPowerTelemetry bool, forceFallback bool) {
-	if !data.IsConnected {
-		data.TelemetryAvailable = false
-		data.TelemetrySource = AdapterTelemetrySourceUnavailable
-		data.TelemetryReason = AdapterTelemetryReasonNoAdapter
-		return
-	}
-
-	if forceFallback {
-		applyAdapterTelemetryFallback(data, AdapterTelemetryReasonForced)
-		return
-	}
-
-	reason := telemetryInvalidReason(data, hasPowerTelemetry)
-	if reason == AdapterTelemetryReasonNone {
-		data.TelemetryAvailable = true
-		data.TelemetrySource = AdapterTelemetrySourceIOKit
-		data.TelemetryReason = AdapterTelemetryReasonNone
-		return
-	}
-
-	applyAdapterTelemetryFallback(data, reason)
-}
-
-func telemetryInvalidReason(data *RawData, hasPowerTelemetry bool) AdapterTelemetryReason {
-	if !hasPowerTelemetry {
-		return AdapterTelemetryReasonMissingIOKit
-	}
-	if data.SourceVoltage <= 0 || data.SourceAmperage <= 0 {
-		return AdapterTelemetryReasonInvalidIOKit
-	}
-	if data.SourceVoltage < minPlausibleAdapterMilliVolts || data.SourceVoltage > maxPlausibleAdapterMilliVolts {
-		return AdapterTelemetryReasonInvalidIOKit
-	}
-	if data.SourceAmperage < minPlausibleAdapterMilliAmps || data.SourceAmperage > maxPlausibleAdapterMilliAmps {
-		return AdapterTelemetryReasonInvalidIOKit
-	}
-
-	milliWatts := int64(data.SourceVoltage) * int64(data.SourceAmperage) / 1000
-	if milliWatts <= 0 || milliWatts > maxPlausibleAdapterMilliWatts {
-		return AdapterTelemetryReasonInvalidIOKit
-	}
-	return AdapterTelemetryReasonNone
-}
-
-func applyAdapterTelemetryFallback(data *RawData, triggerReason AdapterTelemetryReason) {
-	fallback, err := smcFetchDataFn([]string{smc.KeyAdapterVoltage, smc.KeyAdapterCurrent})
-	if err != nil {
-		data.TelemetryAvailable = false
-		data.TelemetrySource = AdapterTelemetrySourceUnavailable
-		data.TelemetryReason = AdapterTelemetryReasonSMCError
-		return
-	}
-	haveVoltage := false
-	haveAmperage := false
-	if v, ok := fallback[smc.KeyAdapterVoltage]; ok {
-		data.SourceVoltage = int(math.Round(v * 1000.0))
-		haveVoltage = true
-	}
-	if a, ok := fallback[smc.KeyAdapterCurrent]; ok {
-		data.SourceAmperage = int(math.Round(a * 1000.0))
-		haveAmperage = true
-	}
-	if !haveVoltage || !haveAmperage || data.SourceVoltage <= 0 || data.SourceAmperage <= 0 {
-		data.TelemetryAvailable = false
-		data.TelemetrySource = AdapterTelemetrySourceUnavailable
-		data.TelemetryReason = AdapterTelemetryReasonSMCError
-		return
-	}
-	data.TelemetryAvailable = true
-	data.TelemetrySource = AdapterTelemetrySourceSMCFallback
-	data.TelemetryReason = triggerReason
-}
@@ -21,9 +21,9 @@ static io_object_t             gPowerNotifier;
 static io_connect_t            gRootPort; // Root Power Domain connection
 
 // --- Go-side callbacks ---
-extern void pushBatteryUpdate();
-extern void pushWillSleep();
-extern void pushDidWake();
+extern void goBatteryUpdate();
+extern void goWillSleep();
+extern void goDidWake();
 
 // Forward-declare so both functions can see it
 static void registerInterest();
@@ -36,7 +36,7 @@ static void batteryChanged(
     void *messageArgument
 ) {
     // Tell Go there’s new data
-    pushBatteryUpdate();
+    goBatteryUpdate();
 
     // Tear down last notifier and re-arm for the next event
     IOObjectRelease(gNotifier);
@@ -52,7 +52,7 @@ static void powerCallback(
 ) {
     switch (messageType) {
         case kIOMessageSystemWillSleep:
-            pushWillSleep();
+            goWillSleep();
             // Acknowledge the notification to allow the sleep process to continue.
             // This is a required step.
             IOAllowPowerChange(gRootPort, (long)messageArgument);
@@ -62,7 +62,7 @@ static void powerCallback(
             IOAllowPowerChange(gRootPort, (long)messageArgument);
             break;
         case kIOMessageSystemHasPoweredOn:
-            pushDidWake();
+            goDidWake();
             break;
     }
 }
@@ -125,75 +125,19 @@ static void startNotifications() {
 */
 import "C"
 
-import (
-	"log"
-	"sync"
-)
-
-var (
-	streamHooksMu   sync.RWMutex
-	beforeSleepHook func()
-)
-
-func setBeforeSleepHook(fn func()) {
-	streamHooksMu.Lock()
-	defer streamHooksMu.Unlock()
-	beforeSleepHook = fn
-}
-
-func runBeforeSleepHook() {
-	streamHooksMu.RLock()
-	hook := beforeSleepHook
-	streamHooksMu.RUnlock()
-	if hook == nil {
-		return
-	}
-	defer func() {
-		if r := recover(); r != nil {
-			log.Printf("powerkit-go: before-sleep hook panicked: %v", r)
-		}
-	}()
-	hook()
-}
-
-func emitLossy(eventType InternalEventType) {
-	select {
-	case Events <- InternalEvent{Type: eventType}:
-	default:
-	}
-}
-
-func emitReliable(eventType InternalEventType) {
-	Events <- InternalEvent{Type: eventType}
-}
-
-func processWillSleepNotification(ack func()) {
-	runBeforeSleepHook()
-	emitReliable(SystemWillSleep)
-	if ack != nil {
-		ack()
-	}
-}
-
-// SetBeforeSleepHook installs the synchronous pre-sleep hook used by the
-// system event stream. The hook runs on the IOKit sleep callback path.
-func SetBeforeSleepHook(fn func()) {
-	setBeforeSleepHook(fn)
-}
-
-//export pushBatteryUpdate
-func pushBatteryUpdate() {
-	emitLossy(BatteryUpdate)
+//export goBatteryUpdate
+func goBatteryUpdate() {
+	pushBatteryUpdate()
 }
 
-//export pushWillSleep
-func pushWillSleep() {
-	processWillSleepNotification(nil)
+//export goWillSleep
+func goWillSleep() {
+	pushWillSleep()
 }
 
-//export pushDidWake
-func pushDidWake() {
-	emitReliable(SystemDidWake)
+//export goDidWake
+func goDidWake() {
+	pushDidWake()
 }
 
 // StartMonitor initializes the unified IOKit notification system.
@@ -0,0 +1,220 @@
+package iokit
+
+import (
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ParseBatteryPlist parses the XML plist written by
+// `ioreg -rw0 -c AppleSmartBattery -a` and returns the first battery with
+// the same fields FetchReading fills from the live registry. Adapter
+// telemetry is left unevaluated; see EvaluateAdapterTelemetry.
+func ParseBatteryPlist(r io.Reader) (*RawData, error) {
+	root, err := decodePlist(r)
+	if err != nil {
+		return nil, err
+	}
+
+	props, ok := root.(map[string]any)
+	if entries, isArray := root.([]any); isArray && len(entries) > 0 {
+		props, ok = entries[0].(map[string]any)
+	}
+	if !ok {
+		return nil, errors.New("ioreg plist does not contain an AppleSmartBattery dictionary")
+	}
+	return batteryFromProperties(props), nil
+}
+
+// batteryFromProperties mirrors get_all_battery_info.
+func batteryFromProperties(props map[string]any) *RawData {
+	data := &RawData{
+		IsCharging:         plistBool(props, "IsCharging"),
+		IsConnected:        plistBool(props, "ExternalConnected"),
+		IsFullyCharged:     plistBool(props, "FullyCharged"),
+		CycleCount:         plistInt(props, "CycleCount"),
+		DesignCapacity:     plistInt(props, "DesignCapacity"),
+		MaxCapacity:        plistInt(props, "AppleRawMaxCapacity"),
+		NominalCapacity:    plistInt(props, "NominalChargeCapacity"),
+		CurrentCapacityRaw: plistInt(props, "AppleRawCurrentCapacity"),
+		CurrentCharge:      plistInt(props, "CurrentCapacity"),
+		TimeToEmpty:        plistInt(props, "AvgTimeToEmpty"),
+		TimeToFull:         plistInt(props, "AvgTimeToFull"),
+		Temperature:        plistInt(props, "Temperature"),
+		Voltage:            plistInt(props, "Voltage"),
+		Amperage:           plistInt(props, "Amperage"),
+		SerialNumber:       plistString(props, "Serial"),
+		DeviceName:         plistString(props, "DeviceName"),
+		TelemetryAvailable: false,
+		TelemetrySource:    AdapterTelemetrySourceUnavailable,
+		TelemetryReason:    AdapterTelemetryReasonMissingIOKit,
+	}
+
+	if adapter, ok := props["AdapterDetails"].(map[string]any); ok {
+		data.AdapterWatts = plistInt(adapter, "Watts")
+		data.AdapterVoltage = plistInt(adapter, "AdapterVoltage")
+		data.AdapterAmperage = plistInt(adapter, "Current")
+		data.AdapterDesc = plistString(adapter, "Description")
+	}
+	if telemetry, ok := props["PowerTelemetryData"].(map[string]any); ok {
+		data.SourceVoltage = plistInt(telemetry, "SystemVoltageIn")
+		data.SourceAmperage = plistInt(telemetry, "SystemCurrentIn")
+		data.HasPowerTelemetry = true
+	}
+	if batteryData, ok := props["BatteryData"].(map[string]any); ok {
+		data.CellVoltages = plistIntArray(batteryData, "CellVoltage", 16)
+		data.CurrentChargeRaw = plistInt(batteryData, "StateOfCharge")
+	}
+	return data
+}
+
+func plistInt(dict map[string]any, key string) int {
+	v, _ := dict[key].(int64)
+	return int(v)
+}
+
+func plistBool(dict map[string]any, key string) bool {
+	v, _ := dict[key].(bool)
+	return v
+}
+
+func plistString(dict map[string]any, key string) string {
+	v, _ := dict[key].(string)
+	return v
+}
+
+// plistIntArray reads at most maxCount integers; non-integer elements read
+// as 0, as in get_long_array_prop.
+func plistIntArray(dict map[string]any, key string, maxCount int) []int {
+	arr, ok := dict[key].([]any)
+	if !ok || len(arr) == 0 {
+		return nil
+	}
+	if len(arr) > maxCount {
+		arr = arr[:maxCount]
+	}
+	out := make([]int, len(arr))
+	for i, v := range arr {
+		n, _ := v.(int64)
+		out[i] = int(n)
+	}
+	return out
+}
+
+// --- Minimal XML plist decoder ---
+
+// decodePlist decodes an XML property list into Go values: dict →
+// map[string]any, array → []any, integer → int64, real → float64,
+// string/date → string, true/false → bool and data → []byte.
+func decodePlist(r io.Reader) (any, error) {
+	dec := xml.NewDecoder(r)
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("parse plist: %w", err)
+		}
+		if start, ok := tok.(xml.StartElement); ok && start.Name.Local != "plist" {
+			return decodePlistValue(dec, start)
+		}
+	}
+}
+
+func decodePlistValue(dec *xml.Decoder, start xml.StartElement) (any, error) {
+	switch start.Name.Local {
+	case "dict":
+		return decodePlistDict(dec)
+	case "array":
+		return decodePlistArray(dec)
+	case "true", "false":
+		if err := dec.Skip(); err != nil {
+			return nil, fmt.Errorf("parse plist: %w", err)
+		}
+		return start.Name.Local == "true", nil
+	}
+
+	var text string
+	if err := dec.DecodeElement(&text, &start); err != nil {
+		return nil, fmt.Errorf("parse plist: %w", err)
+	}
+	return decodePlistScalar(start.Name.Local, strings.TrimSpace(text))
+}
+
+func decodePlistScalar(kind, text string) (any, error) {
+	switch kind {
+	case "integer":
+		return parsePlistInteger(text)
+	case "real":
+		return strconv.ParseFloat(text, 64)
+	case "string", "date":
+		return text, nil
+	case "data":
+		return base64.StdEncoding.DecodeString(strings.Join(strings.Fields(text), ""))
+	default:
+		return nil, fmt.Errorf("parse plist: unsupported element <%s>", kind)
+	}
+}
+
+// parsePlistInteger accepts the full signed and unsigned 64-bit range.
+// ioreg prints negative registry values such as a discharging Amperage as
+// their unsigned two's complement, which CFNumber reads back as negative.
+func parsePlistInteger(text string) (int64, error) {
+	if v, err := strconv.ParseInt(text, 0, 64); err == nil {
+		return v, nil
+	}
+	u, err := strconv.ParseUint(text, 0, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse plist integer %q: %w", text, err)
+	}
+	return int64(u), nil
+}
+
+func decodePlistDict(dec *xml.Decoder) (map[string]any, error) {
+	dict := make(map[string]any)
+	var key string
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("parse plist dict: %w", err)
+		}
+		switch t := tok.(type) {
+		case xml.EndElement:
+			return dict, nil
+		case xml.StartElement:
+			if t.Name.Local == "key" {
+				if err := dec.DecodeElement(&key, &t); err != nil {
+					return nil, fmt.Errorf("parse plist key: %w", err)
+				}
+				continue
+			}
+			value, err := decodePlistValue(dec, t)
+			if err != nil {
+				return nil, err
+			}
+			dict[key] = value
+		}
+	}
+}
+
+func decodePlistArray(dec *xml.Decoder) ([]any, error) {
+	var arr []any
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("parse plist array: %w", err)
+		}
+		switch t := tok.(type) {
+		case xml.EndElement:
+			return arr, nil
+		case xml.StartElement:
+			value, err := decodePlistValue(dec, t)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, value)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package iokit
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseBatteryPlist(t *testing.T) {
+	f, err := os.Open("testdata/AppleSmartBattery.plist")
+	if err != nil {
+		t.Fatalf("open fixture: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	data, err := ParseBatteryPlist(f)
+	if err != nil {
+		t.Fatalf("ParseBatteryPlist returned error: %v", err)
+	}
+
+	if !data.IsCharging || !data.IsConnected || data.IsFullyCharged {
+		t.Fatalf("unexpected state flags: %+v", data)
+	}
+	if data.CycleCount != 187 || data.DesignCapacity != 6249 || data.MaxCapacity != 6075 || data.NominalCapacity != 6210 {
+		t.Fatalf("unexpected capacities: %+v", data)
+	}
+	if data.Amperage != -1000 {
+		t.Fatalf("expected unsigned ioreg amperage to read as -1000, got %d", data.Amperage)
+	}
+	if data.CurrentCharge != 86 || data.CurrentChargeRaw != 86 || data.CurrentCapacityRaw != 5210 {
+		t.Fatalf("unexpected charge values: %+v", data)
+	}
+	if data.AdapterWatts != 94 || data.AdapterVoltage != 20000 || data.AdapterAmperage != 4700 || data.AdapterDesc != "pd charger" {
+		t.Fatalf("unexpected adapter details: %+v", data)
+	}
+	if !data.HasPowerTelemetry || data.SourceVoltage != 20150 || data.SourceAmperage != 1850 {
+		t.Fatalf("unexpected power telemetry: %+v", data)
+	}
+	if !reflect.DeepEqual(data.CellVoltages, []int{4102, 4108, 4111}) {
+		t.Fatalf("unexpected cell voltages: %v", data.CellVoltages)
+	}
+	if data.SerialNumber != "F8Y1234ABCD" || data.DeviceName != "bq40z651" || data.Temperature != 3055 {
+		t.Fatalf("unexpected identity values: %+v", data)
+	}
+}
+
+func TestParseBatteryPlistRejectsNonBattery(t *testing.T) {
+	for _, input := range []string{
+		`<plist version="1.0"><array/></plist>`,
+		`<plist version="1.0"><string>x</string></plist>`,
+		`not xml`,
+	} {
+		if _, err := ParseBatteryPlist(strings.NewReader(input)); err == nil {
+			t.Fatalf("expected error for %q", input)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package iokit
+
+import (
+	"math"
+
+	"github.com/peterneutron/powerkit-go/internal/smc"
+)
+
+const (
+	minPlausibleAdapterMilliVolts = 4500
+	maxPlausibleAdapterMilliVolts = 50000
+	minPlausibleAdapterMilliAmps  = 50
+	maxPlausibleAdapterMilliAmps  = 10000
+	maxPlausibleAdapterMilliWatts = 200000
+)
+
+var smcFetchDataFn = smc.FetchData
+
+// SMCFetchFunc reads decoded SMC keys for the adapter telemetry fallback.
+type SMCFetchFunc func(keys []string) (map[string]float64, error)
+
+// EvaluateAdapterTelemetry decides where adapter input telemetry comes from
+// for a reading returned by FetchReading, reading the SMC fallback through
+// fetch. It lets recorded readings be evaluated against recorded SMC values.
+func EvaluateAdapterTelemetry(data *RawData, forceFallback bool, fetch SMCFetchFunc) {
+	evaluateAdapterTelemetryWith(data, data.HasPowerTelemetry, forceFallback, fetch)
+}
+
+func evaluateAdapterTelemetry(data *RawData, hasPowerTelemetry bool, forceFallback bool) {
+	evaluateAdapterTelemetryWith(data, hasPowerTelemetry, forceFallback, smcFetchDataFn)
+}
+
+func evaluateAdapterTelemetryWith(data *RawData, hasPowerTelemetry bool, forceFallback bool, fetch SMCFetchFunc) {
+	if !data.IsConnected {
+		data.TelemetryAvailable = false
+		data.TelemetrySource = AdapterTelemetrySourceUnavailable
+		data.TelemetryReason = AdapterTelemetryReasonNoAdapter
+		return
+	}
+
+	if forceFallback {
+		applyAdapterTelemetryFallbackWith(data, AdapterTelemetryReasonForced, fetch)
+		return
+	}
+
+	reason := telemetryInvalidReason(data, hasPowerTelemetry)
+	if reason == AdapterTelemetryReasonNone {
+		data.TelemetryAvailable = true
+		data.TelemetrySource = AdapterTelemetrySourceIOKit
+		data.TelemetryReason = AdapterTelemetryReasonNone
+		return
+	}
+
+	applyAdapterTelemetryFallbackWith(data, reason, fetch)
+}
+
+func telemetryInvalidReason(data *RawData, hasPowerTelemetry bool) AdapterTelemetryReason {
+	if !hasPowerTelemetry {
+		return AdapterTelemetryReasonMissingIOKit
+	}
+	if data.SourceVoltage <= 0 || data.SourceAmperage <= 0 {
+		return AdapterTelemetryReasonInvalidIOKit
+	}
+	if data.SourceVoltage < minPlausibleAdapterMilliVolts || data.SourceVoltage > maxPlausibleAdapterMilliVolts {
+		return AdapterTelemetryReasonInvalidIOKit
+	}
+	if data.SourceAmperage < minPlausibleAdapterMilliAmps || data.SourceAmperage > maxPlausibleAdapterMilliAmps {
+		return AdapterTelemetryReasonInvalidIOKit
+	}
+
+	milliWatts := int64(data.SourceVoltage) * int64(data.SourceAmperage) / 1000
+	if milliWatts <= 0 || milliWatts > maxPlausibleAdapterMilliWatts {
+		return AdapterTelemetryReasonInvalidIOKit
+	}
+	return AdapterTelemetryReasonNone
+}
+
+func applyAdapterTelemetryFallback(data *RawData, triggerReason AdapterTelemetryReason) {
+	applyAdapterTelemetryFallbackWith(data, triggerReason, smcFetchDataFn)
+}
+
+func applyAdapterTelemetryFallbackWith(data *RawData, triggerReason AdapterTelemetryReason, fetch SMCFetchFunc) {
+	fallback, err := fetch([]string{smc.KeyAdapterVoltage, smc.KeyAdapterCurrent})
+	if err != nil {
+		data.TelemetryAvailable = false
+		data.TelemetrySource = AdapterTelemetrySourceUnavailable
+		data.TelemetryReason = AdapterTelemetryReasonSMCError
+		return
+	}
+	haveVoltage := false
+	haveAmperage := false
+	if v, ok := fallback[smc.KeyAdapterVoltage]; ok {
+		data.SourceVoltage = int(math.Round(v * 1000.0))
+		haveVoltage = true
+	}
+	if a, ok := fallback[smc.KeyAdapterCurrent]; ok {
+		data.SourceAmperage = int(math.Round(a * 1000.0))
+		haveAmperage = true
+	}
+	if !haveVoltage || !haveAmperage || data.SourceVoltage <= 0 || data.SourceAmperage <= 0 {
+		data.TelemetryAvailable = false
+		data.TelemetrySource = AdapterTelemetrySourceUnavailable
+		data.TelemetryReason = AdapterTelemetryReasonSMCError
+		return
+	}
+	data.TelemetryAvailable = true
+	data.TelemetrySource = AdapterTelemetrySourceSMCFallback
+	data.TelemetryReason = triggerReason
+}
@@ -0,0 +1,96 @@
+package os
+
+import (
+	"bufio"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// FirmwareInfo describes detected firmware metadata used for resolver gating.
+type FirmwareInfo struct {
+	Major   int
+	Version string
+	Source  string
+}
+
+// GetFirmwareMajorVersion is kept as a compatibility wrapper for older callsites.
+func GetFirmwareMajorVersion() int {
+	return GetFirmwareInfo().Major
+}
+
+func getFirmwareFromSystemProfiler() (string, int, error) {
+	cmd := exec.Command("system_profiler", "SPHardwareDataType")
+	out, err := cmd.Output()
+	if err != nil {
+		return "", 0, err
+	}
+	version, major, err := parseFirmwareRecordFromSystemProfiler(string(out))
+	if err != nil {
+		return "", 0, err
+	}
+	return version, major, nil
+}
+
+// parseFirmwareVersion is retained for backwards-compatible tests/callers.
+func parseFirmwareVersion(output string) (int, error) {
+	_, major, err := parseFirmwareRecordFromSystemProfiler(output)
+	return major, err
+}
+
+// parseFirmwareRecordFromSystemProfiler scans the text output of system_profiler
+// and returns the raw firmware value and its parsed major version.
+func parseFirmwareRecordFromSystemProfiler(output string) (string, int, error) {
+	labels := []string{
+		"System Firmware Version:",
+		"Boot ROM Version:",
+		"OS Loader Version:",
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		for _, label := range labels {
+			if !strings.Contains(line, label) {
+				continue
+			}
+			parts := strings.SplitN(line, ":", 2)
+			if len(parts) != 2 {
+				continue
+			}
+			valStr := strings.TrimSpace(parts[1])
+			if valStr == "" {
+				continue
+			}
+			major, err := extractFirmwareMajor(valStr)
+			if err != nil {
+				continue
+			}
+			return normalizeFirmwareValue(valStr), major, nil
+		}
+	}
+	return "", 0, fmt.Errorf("could not find firmware version in system_profiler output")
+}
+
+var firmwareVersionRE = regexp.MustCompile(`([0-9]{4,})(?:\.[0-9]+)+`)
+var firmwareMajorFallbackRE = regexp.MustCompile(`\b([0-9]{4,})\b`)
+
+func extractFirmwareMajor(value string) (int, error) {
+	if m := firmwareVersionRE.FindStringSubmatch(value); len(m) == 2 {
+		return strconv.Atoi(m[1])
+	}
+	if m := firmwareMajorFallbackRE.FindStringSubmatch(value); len(m) == 2 {
+		return strconv.Atoi(m[1])
+	}
+	return 0, fmt.Errorf("no firmware major version found in %q", value)
+}
+
+func normalizeFirmwareValue(value string) string {
+	value = strings.TrimSpace(value)
+	value = strings.Trim(value, "\"")
+	value = strings.Trim(value, "<>")
+	value = strings.Join(strings.Fields(value), " ")
+	return value
+}
@@ -1,5 +1,3 @@
-//go:build darwin
-
 // Package os provides internal OS helpers such as Low Power Mode control.
 package os
 
@@ -51,18 +49,17 @@ func GetLowPowerModeEnabled() (enabled bool, available bool, err error) {
 
 	enabled = false
 	available = false
-	for _, line := range strings.Split(strings.ToLower(string(out)), "\n") {
-		s := strings.TrimSpace(line)
-		if strings.HasPrefix(s, "lowpowermode") {
-			available = true
-			// Example formats:
-			//  lowpowermode         1
-			//  lowpowermode = 1
-			if strings.Contains(s, " 1") || strings.HasSuffix(s, "1") || strings.Contains(s, "= 1") {
-				enabled = true
-			}
-			break
+	for _, line := range strings.Split(string(out), "\n") {
+		// Example formats:
+		//  lowpowermode         1
+		//  lowpowermode = 1
+		key, value, ok := parseSettingLine(strings.TrimSpace(line))
+		if !ok || key != SettingLowPowerMode {
+			continue
 		}
+		available = true
+		enabled = strings.Fields(value)[0] == "1"
+		break
 	}
 
 	// Update cache only when available and parsed
@@ -80,25 +77,14 @@ func GetLowPowerModeEnabled() (enabled bool, available bool, err error) {
 // SetLowPowerMode sets Low Power Mode using pmset.
 // Requires root privileges.
 func SetLowPowerMode(enable bool) error {
-	target := "0"
-	if enable {
-		target = "1"
-	}
-
-	// Currently write all power sources: -a
-	// Foundation for future finer control using -b (battery) or -c (charger):
-	//   source := "-a" // or "-b" / "-c" in the future when API supports per-source control
-	//   cmd := exec.Command("/usr/bin/pmset", source, "lowpowermode", target)
-	// For now, keep -a to avoid dead code and ensure consistent behavior.
-	if err := pmsetExecFn("-a", "lowpowermode", target); err != nil {
-		return err
-	}
+	return SetLowPowerModeFor(PowerSourceAll, enable)
+}
 
-	// Invalidate cache immediately so next read reflects the change.
+// invalidateLowPowerModeCache makes the next read reflect a change immediately.
+func invalidateLowPowerModeCache() {
 	lpmMu.Lock()
 	lpmValid = false
 	lpmMu.Unlock()
-	return nil
 }
 
 // ToggleLowPowerMode toggles the current LPM state.
@@ -0,0 +1,14 @@
+//go:build !darwin
+
+package os
+
+// GetMajorVersion reports 0 on platforms other than macOS.
+func GetMajorVersion() int {
+	return 0
+}
+
+// GetFirmwareInfo reports an unknown firmware on platforms other than macOS,
+// where there is no Apple system firmware to inspect.
+func GetFirmwareInfo() FirmwareInfo {
+	return FirmwareInfo{Source: "unknown"}
+}
@@ -0,0 +1,342 @@
+package os
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PowerSource selects which pmset settings profile a change applies to.
+type PowerSource string
+
+const (
+	// PowerSourceAll applies a change to every profile (pmset -a).
+	PowerSourceAll PowerSource = "all"
+	// PowerSourceBattery is the battery profile (pmset -b).
+	PowerSourceBattery PowerSource = "battery"
+	// PowerSourceAC is the charger profile (pmset -c).
+	PowerSourceAC PowerSource = "ac"
+	// PowerSourceUPS is the UPS profile (pmset -u).
+	PowerSourceUPS PowerSource = "ups"
+)
+
+// pmsetFlag returns the pmset argument selecting source.
+func (s PowerSource) pmsetFlag() (string, error) {
+	switch s {
+	case PowerSourceAll:
+		return "-a", nil
+	case PowerSourceBattery:
+		return "-b", nil
+	case PowerSourceAC:
+		return "-c", nil
+	case PowerSourceUPS:
+		return "-u", nil
+	default:
+		return "", fmt.Errorf("unknown power source %q", string(s))
+	}
+}
+
+// Keys understood by PowerSettings and SetPowerSetting.
+const (
+	SettingDisplaySleep  = "displaysleep"
+	SettingSleep         = "sleep"
+	SettingDiskSleep     = "disksleep"
+	SettingPowerNap      = "powernap"
+	SettingHibernateMode = "hibernatemode"
+	SettingHibernateFile = "hibernatefile"
+	SettingStandby       = "standby"
+	SettingTCPKeepAlive  = "tcpkeepalive"
+	SettingLowPowerMode  = "lowpowermode"
+	SettingPowerMode     = "powermode"
+	SettingWakeOnLAN     = "womp"
+	SettingLidWake       = "lidwake"
+	SettingACWake        = "acwake"
+	SettingTTYSKeepAwake = "ttyskeepawake"
+	SettingProximityWake = "proximitywake"
+)
+
+// PowerSettings is one power source profile from `pmset -g custom`.
+// Durations are in minutes, with 0 meaning never. Values holds every key
+// as printed, including keys without a typed field.
+type PowerSettings struct {
+	DisplaySleep  int
+	Sleep         int
+	DiskSleep     int
+	PowerNap      bool
+	HibernateMode int
+	HibernateFile string
+	Standby       bool
+	TCPKeepAlive  bool
+	LowPowerMode  bool
+	PowerMode     int
+	WakeOnLAN     bool
+	LidWake       bool
+	ACWake        bool
+	TTYSKeepAwake bool
+	ProximityWake bool
+
+	Values map[string]string
+}
+
+// Has reports whether the profile lists key.
+func (p *PowerSettings) Has(key string) bool {
+	_, ok := p.Values[key]
+	return ok
+}
+
+// CustomSettings holds the per-source profiles. A profile is nil when the
+// system has no such power source (e.g. Battery on a desktop).
+type CustomSettings struct {
+	Battery *PowerSettings
+	AC      *PowerSettings
+	UPS     *PowerSettings
+}
+
+// cached profiles, shared with the event stream's per-update reads
+var (
+	customMu       sync.Mutex
+	customCachedAt time.Time
+	customValue    *CustomSettings
+)
+
+// GetCustomSettings runs `pmset -g custom` and parses its output. Results
+// are cached for the Low Power Mode TTL; callers must not modify them.
+func GetCustomSettings() (*CustomSettings, error) {
+	customMu.Lock()
+	if customValue != nil && time.Since(customCachedAt) < lpmTTL {
+		v := customValue
+		customMu.Unlock()
+		return v, nil
+	}
+	customMu.Unlock()
+
+	out, err := pmsetRunFn("-g", "custom")
+	if err != nil {
+		return nil, fmt.Errorf("pmset -g custom: %w", err)
+	}
+	settings, err := ParseCustomSettings(out)
+	if err != nil {
+		return nil, err
+	}
+
+	customMu.Lock()
+	customValue = settings
+	customCachedAt = time.Now()
+	customMu.Unlock()
+	return settings, nil
+}
+
+// ParseCustomSettings parses the output of `pmset -g custom`:
+//
+//	Battery Power:
+//	 displaysleep         2
+//	 lowpowermode         1
+//	AC Power:
+//	 displaysleep         10
+func ParseCustomSettings(out []byte) (*CustomSettings, error) {
+	settings := &CustomSettings{}
+	var current *PowerSettings
+	for _, line := range strings.Split(string(out), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+		if strings.HasSuffix(trimmed, ":") {
+			current = settings.section(strings.TrimSuffix(trimmed, ":"))
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		if key, value, ok := parseSettingLine(trimmed); ok {
+			current.Values[key] = value
+		}
+	}
+
+	if settings.Battery == nil && settings.AC == nil && settings.UPS == nil {
+		return nil, errors.New("pmset output contains no power source profiles")
+	}
+	for _, p := range []*PowerSettings{settings.Battery, settings.AC, settings.UPS} {
+		if p != nil {
+			p.applyValues()
+		}
+	}
+	return settings, nil
+}
+
+// section returns a new profile for a header line, or nil for headers that
+// are not power sources.
+func (c *CustomSettings) section(header string) *PowerSettings {
+	p := &PowerSettings{Values: make(map[string]string)}
+	switch strings.ToLower(header) {
+	case "battery power":
+		c.Battery = p
+	case "ac power":
+		c.AC = p
+	case "ups power":
+		c.UPS = p
+	default:
+		return nil
+	}
+	return p
+}
+
+// parseSettingLine splits "key value" or "key = value". The value keeps
+// everything after the key, so annotations such as
+// "sleep 1 (sleep prevented by powerd)" survive in Values.
+func parseSettingLine(line string) (key, value string, ok bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 2 {
+		return "", "", false
+	}
+	rest := fields[1:]
+	if rest[0] == "=" {
+		rest = rest[1:]
+	}
+	if len(rest) == 0 {
+		return "", "", false
+	}
+	return strings.ToLower(fields[0]), strings.Join(rest, " "), true
+}
+
+func (p *PowerSettings) applyValues() {
+	p.DisplaySleep = p.intValue(SettingDisplaySleep)
+	p.Sleep = p.intValue(SettingSleep)
+	p.DiskSleep = p.intValue(SettingDiskSleep)
+	p.PowerNap = p.intValue(SettingPowerNap) != 0
+	p.HibernateMode = p.intValue(SettingHibernateMode)
+	p.HibernateFile = p.Values[SettingHibernateFile]
+	p.Standby = p.intValue(SettingStandby) != 0
+	p.TCPKeepAlive = p.intValue(SettingTCPKeepAlive) != 0
+	p.LowPowerMode = p.intValue(SettingLowPowerMode) != 0
+	p.PowerMode = p.intValue(SettingPowerMode)
+	p.WakeOnLAN = p.intValue(SettingWakeOnLAN) != 0
+	p.LidWake = p.intValue(SettingLidWake) != 0
+	p.ACWake = p.intValue(SettingACWake) != 0
+	p.TTYSKeepAwake = p.intValue(SettingTTYSKeepAwake) != 0
+	p.ProximityWake = p.intValue(SettingProximityWake) != 0
+}
+
+// intValue parses the first word of a value; missing or non-numeric values
+// read as 0.
+func (p *PowerSettings) intValue(key string) int {
+	fields := strings.Fields(p.Values[key])
+	if len(fields) == 0 {
+		return 0
+	}
+	n, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// ValidatePowerSetting checks value against the rules for known keys:
+// timers are non-negative minutes, switches are 0 or 1 and hibernatemode
+// is 0, 3 or 25. Other keys only need to be a single word.
+func ValidatePowerSetting(key, value string) error {
+	if !isPMSetWord(key) {
+		return fmt.Errorf("invalid pmset setting %q", key)
+	}
+	if !isPMSetWord(value) {
+		return fmt.Errorf("invalid value %q for %s", value, key)
+	}
+
+	switch {
+	case minuteSettings[key]:
+		if n, err := strconv.Atoi(value); err != nil || n < 0 {
+			return fmt.Errorf("%s must be a non-negative number of minutes, got %q", key, value)
+		}
+	case switchSettings[key]:
+		if value != "0" && value != "1" {
+			return fmt.Errorf("%s must be 0 or 1, got %q", key, value)
+		}
+	case key == SettingHibernateMode:
+		if value != "0" && value != "3" && value != "25" {
+			return fmt.Errorf("unsupported hibernatemode %q (want 0, 3 or 25)", value)
+		}
+	}
+	return nil
+}
+
+var (
+	minuteSettings = map[string]bool{SettingDisplaySleep: true, SettingSleep: true, SettingDiskSleep: true}
+	switchSettings = map[string]bool{
+		SettingPowerNap: true, SettingStandby: true, SettingTCPKeepAlive: true,
+		SettingLowPowerMode: true, SettingWakeOnLAN: true, SettingLidWake: true,
+		SettingACWake: true, SettingTTYSKeepAwake: true, SettingProximityWake: true,
+	}
+)
+
+func isPMSetWord(s string) bool {
+	return s != "" && !strings.ContainsAny(s, " \t\n")
+}
+
+// SetPowerSetting runs `pmset <-a|-b|-c|-u> key value` after
+// ValidatePowerSetting. Requires root privileges.
+func SetPowerSetting(source PowerSource, key, value string) error {
+	flag, err := source.pmsetFlag()
+	if err != nil {
+		return err
+	}
+	if err := ValidatePowerSetting(key, value); err != nil {
+		return err
+	}
+	if err := pmsetExecFn(flag, key, value); err != nil {
+		return fmt.Errorf("pmset %s %s %s: %w", flag, key, value, err)
+	}
+	customMu.Lock()
+	customValue = nil
+	customMu.Unlock()
+	if key == SettingLowPowerMode {
+		invalidateLowPowerModeCache()
+	}
+	return nil
+}
+
+// SetDisplaySleep sets the display sleep timer in minutes (0 = never).
+func SetDisplaySleep(source PowerSource, minutes int) error {
+	return SetPowerSetting(source, SettingDisplaySleep, strconv.Itoa(minutes))
+}
+
+// SetSystemSleep sets the system sleep timer in minutes (0 = never).
+func SetSystemSleep(source PowerSource, minutes int) error {
+	return SetPowerSetting(source, SettingSleep, strconv.Itoa(minutes))
+}
+
+// SetDiskSleep sets the disk spindown timer in minutes (0 = never).
+func SetDiskSleep(source PowerSource, minutes int) error {
+	return SetPowerSetting(source, SettingDiskSleep, strconv.Itoa(minutes))
+}
+
+// SetPowerNap enables or disables Power Nap.
+func SetPowerNap(source PowerSource, enable bool) error {
+	return SetPowerSetting(source, SettingPowerNap, BoolValue(enable))
+}
+
+// SetTCPKeepAlive enables or disables TCP keepalive during sleep.
+func SetTCPKeepAlive(source PowerSource, enable bool) error {
+	return SetPowerSetting(source, SettingTCPKeepAlive, BoolValue(enable))
+}
+
+// SetHibernateMode sets the hibernation mode: 0 (sleep only), 3 (safe
+// sleep) or 25 (hibernate).
+func SetHibernateMode(source PowerSource, mode int) error {
+	return SetPowerSetting(source, SettingHibernateMode, strconv.Itoa(mode))
+}
+
+// SetLowPowerModeFor enables or disables Low Power Mode for one power source.
+func SetLowPowerModeFor(source PowerSource, enable bool) error {
+	return SetPowerSetting(source, SettingLowPowerMode, BoolValue(enable))
+}
+
+// BoolValue formats a switch the way pmset expects it.
+func BoolValue(enable bool) string {
+	if enable {
+		return "1"
+	}
+	return "0"
+}
@@ -0,0 +1,212 @@
+package os
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+const pmsetCustomOutput = `Battery Power:
+ lidwake              1
+ lowpowermode         1
+ standbydelayhigh     86400
+ proximitywake        0
+ standby              1
+ powernap             0
+ disksleep            10
+ hibernatemode        3
+ ttyskeepawake        1
+ displaysleep         2
+ hibernatefile        /var/vm/sleepimage
+ sleep                1
+ tcpkeepalive         1
+AC Power:
+ lidwake              1
+ lowpowermode         0
+ womp                 1
+ powernap             1
+ disksleep            10
+ hibernatemode        3
+ displaysleep         10
+ hibernatefile        /var/vm/sleepimage
+ sleep                0 (sleep prevented by coreaudiod)
+ tcpkeepalive         1
+`
+
+func TestParseCustomSettings(t *testing.T) {
+	settings, err := ParseCustomSettings([]byte(pmsetCustomOutput))
+	if err != nil {
+		t.Fatalf("ParseCustomSettings returned error: %v", err)
+	}
+	if settings.UPS != nil {
+		t.Fatalf("expected no UPS profile, got %+v", settings.UPS)
+	}
+
+	battery := settings.Battery
+	if battery == nil {
+		t.Fatalf("expected battery profile")
+	}
+	if battery.DisplaySleep != 2 || battery.Sleep != 1 || battery.DiskSleep != 10 {
+		t.Fatalf("unexpected battery timers: %+v", battery)
+	}
+	if !battery.LowPowerMode || battery.PowerNap || !battery.Standby || battery.HibernateMode != 3 {
+		t.Fatalf("unexpected battery flags: %+v", battery)
+	}
+	if battery.HibernateFile != "/var/vm/sleepimage" || battery.Values["standbydelayhigh"] != "86400" {
+		t.Fatalf("unexpected battery values: %+v", battery.Values)
+	}
+	if battery.Has(SettingWakeOnLAN) {
+		t.Fatalf("battery profile should not list womp")
+	}
+
+	ac := settings.AC
+	if ac == nil {
+		t.Fatalf("expected AC profile")
+	}
+	if ac.LowPowerMode || !ac.PowerNap || !ac.WakeOnLAN || ac.DisplaySleep != 10 {
+		t.Fatalf("unexpected AC profile: %+v", ac)
+	}
+	if ac.Sleep != 0 || ac.Values[SettingSleep] != "0 (sleep prevented by coreaudiod)" {
+		t.Fatalf("expected annotated sleep value to parse as 0, got %d / %q", ac.Sleep, ac.Values[SettingSleep])
+	}
+}
+
+func TestParseCustomSettingsRejectsUnknownOutput(t *testing.T) {
+	if _, err := ParseCustomSettings([]byte("Usage: pmset <options>\n")); err == nil {
+		t.Fatalf("expected error for output without profiles")
+	}
+}
+
+func TestPowerSettingSetters(t *testing.T) {
+	oldExec := pmsetExecFn
+	t.Cleanup(func() { pmsetExecFn = oldExec })
+
+	var got []string
+	pmsetExecFn = func(args ...string) error {
+		got = args
+		return nil
+	}
+
+	tests := []struct {
+		name string
+		set  func() error
+		want []string
+	}{
+		{"display sleep on battery", func() error { return SetDisplaySleep(PowerSourceBattery, 5) }, []string{"-b", "displaysleep", "5"}},
+		{"system sleep on charger", func() error { return SetSystemSleep(PowerSourceAC, 0) }, []string{"-c", "sleep", "0"}},
+		{"disk sleep everywhere", func() error { return SetDiskSleep(PowerSourceAll, 10) }, []string{"-a", "disksleep", "10"}},
+		{"power nap on UPS", func() error { return SetPowerNap(PowerSourceUPS, true) }, []string{"-u", "powernap", "1"}},
+		{"tcp keepalive", func() error { return SetTCPKeepAlive(PowerSourceAC, false) }, []string{"-c", "tcpkeepalive", "0"}},
+		{"hibernate mode", func() error { return SetHibernateMode(PowerSourceBattery, 25) }, []string{"-b", "hibernatemode", "25"}},
+		{"low power mode", func() error { return SetLowPowerModeFor(PowerSourceBattery, true) }, []string{"-b", "lowpowermode", "1"}},
+		{"legacy low power mode", func() error { return SetLowPowerMode(false) }, []string{"-a", "lowpowermode", "0"}},
+	}
+	for _, tt := range tests {
+		got = nil
+		if err := tt.set(); err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Fatalf("%s: expected pmset %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestPowerSettingSettersRejectInvalidInput(t *testing.T) {
+	oldExec := pmsetExecFn
+	t.Cleanup(func() { pmsetExecFn = oldExec })
+
+	pmsetExecFn = func(args ...string) error {
+		t.Fatalf("pmset should not run for invalid input, got %v", args)
+		return nil
+	}
+
+	invalid := []func() error{
+		func() error { return SetDisplaySleep(PowerSourceAC, -1) },
+		func() error { return SetHibernateMode(PowerSourceAC, 1) },
+		func() error { return SetPowerNap(PowerSource("desktop"), true) },
+		func() error { return SetPowerSetting(PowerSourceAll, "sleep 0", "1") },
+	}
+	for i, set := range invalid {
+		if err := set(); err == nil {
+			t.Fatalf("case %d: expected error", i)
+		}
+	}
+}
+
+func TestSetLowPowerModeForInvalidatesCache(t *testing.T) {
+	oldRun, oldExec := pmsetRunFn, pmsetExecFn
+	t.Cleanup(func() {
+		pmsetRunFn = oldRun
+		pmsetExecFn = oldExec
+	})
+
+	resetLPMCache()
+	state := "0"
+	pmsetRunFn = func(_ ...string) ([]byte, error) {
+		return []byte(" lowpowermode " + state + "\n"), nil
+	}
+	pmsetExecFn = func(args ...string) error {
+		state = args[2]
+		return nil
+	}
+
+	if enabled, _, _ := GetLowPowerModeEnabled(); enabled {
+		t.Fatalf("expected initial read to be disabled")
+	}
+	if err := SetLowPowerModeFor(PowerSourceAC, true); err != nil {
+		t.Fatalf("SetLowPowerModeFor returned error: %v", err)
+	}
+	if enabled, _, _ := GetLowPowerModeEnabled(); !enabled {
+		t.Fatalf("expected read after set to bypass the cache")
+	}
+
+	pmsetExecFn = func(...string) error { return errors.New("exit status 1") }
+	if err := SetLowPowerModeFor(PowerSourceAC, false); err == nil {
+		t.Fatalf("expected pmset failure to be returned")
+	}
+}
+
+func TestGetCustomSettingsCachesUntilSet(t *testing.T) {
+	oldRun, oldExec := pmsetRunFn, pmsetExecFn
+	t.Cleanup(func() {
+		pmsetRunFn = oldRun
+		pmsetExecFn = oldExec
+		customMu.Lock()
+		customValue = nil
+		customMu.Unlock()
+	})
+
+	customMu.Lock()
+	customValue = nil
+	customMu.Unlock()
+
+	runs := 0
+	pmsetRunFn = func(args ...string) ([]byte, error) {
+		runs++
+		if !reflect.DeepEqual(args, []string{"-g", "custom"}) {
+			t.Fatalf("unexpected pmset args %v", args)
+		}
+		return []byte(pmsetCustomOutput), nil
+	}
+	pmsetExecFn = func(...string) error { return nil }
+
+	for i := 0; i < 2; i++ {
+		if _, err := GetCustomSettings(); err != nil {
+			t.Fatalf("GetCustomSettings returned error: %v", err)
+		}
+	}
+	if runs != 1 {
+		t.Fatalf("expected cached second read, got %d pmset runs", runs)
+	}
+
+	if err := SetLowPowerModeFor(PowerSourceBattery, false); err != nil {
+		t.Fatalf("SetLowPowerModeFor returned error: %v", err)
+	}
+	if _, err := GetCustomSettings(); err != nil {
+		t.Fatalf("GetCustomSettings returned error: %v", err)
+	}
+	if runs != 2 {
+		t.Fatalf("expected a set to invalidate the cache, got %d pmset runs", runs)
+	}
+}
@@ -93,35 +93,9 @@ import "C"
 import (
 	"errors"
 	"fmt"
-	"sync"
 	"unsafe"
 )
 
-// AssertionType defines the type of sleep to prevent.
-type AssertionType int
-
-const (
-	// PreventSystemSleep prevents the system from sleeping due to user inactivity.
-	// This is for long-running background tasks. The display may still sleep.
-	PreventSystemSleep AssertionType = iota
-
-	// PreventDisplaySleep prevents the display from sleeping. This implies the
-	// system will also not sleep. This is for presentation or video playback.
-	PreventDisplaySleep
-)
-
-// AssertionID is a Go-native type for the underlying C IOPMAssertionID.
-type AssertionID uint32
-
-var (
-	// mu protects access to the global assertion IDs.
-	mu sync.Mutex
-
-	// We now store IDs for both assertion types separately.
-	systemSleepAssertionID  AssertionID
-	displaySleepAssertionID AssertionID
-)
-
 // PreventSleep creates a power assertion of the specified type.
 // It is safe to call this function multiple times for the same type; it will
 // only create one assertion of each type. The reason is shown in Activity Monitor.
@@ -189,48 +163,6 @@ func AllowSleep(assertionType AssertionType) {
 	*currentIDPtr = 0 // Reset our state.
 }
 
-// AllowAllSleep is a convenience function to release all active assertions
-// created by this package. This is useful for cleanup on application exit.
-func AllowAllSleep() {
-	AllowSleep(PreventSystemSleep)
-	AllowSleep(PreventDisplaySleep)
-}
-
-// IsActive reports whether an assertion of the given type is currently
-// active (created and not released) by this process.
-func IsActive(assertionType AssertionType) bool {
-	mu.Lock()
-	defer mu.Unlock()
-
-	switch assertionType {
-	case PreventSystemSleep:
-		return systemSleepAssertionID != 0
-	case PreventDisplaySleep:
-		return displaySleepAssertionID != 0
-	default:
-		return false
-	}
-}
-
-// GetAssertionID returns the active assertion ID for the given type, if any.
-// The boolean return indicates whether an assertion is active.
-func GetAssertionID(assertionType AssertionType) (AssertionID, bool) {
-	mu.Lock()
-	defer mu.Unlock()
-
-	switch assertionType {
-	case PreventSystemSleep:
-		if systemSleepAssertionID != 0 {
-			return systemSleepAssertionID, true
-		}
-	case PreventDisplaySleep:
-		if displaySleepAssertionID != 0 {
-			return displaySleepAssertionID, true
-		}
-	}
-	return 0, false
-}
-
 // GlobalSleepStatus queries macOS for global assertion counts using IOPM APIs.
 // Returns true/false for whether system and display sleep are allowed systemwide.
 func GlobalSleepStatus() (systemAllowed bool, displayAllowed bool, err error) {
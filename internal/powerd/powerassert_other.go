@@ -0,0 +1,24 @@
+//go:build !darwin
+
+package powerd
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+)
+
+var errNoIOPM = fmt.Errorf("power assertions are not available on %s: %w", runtime.GOOS, errors.ErrUnsupported)
+
+// PreventSleep always fails on platforms without IOPMLib.
+func PreventSleep(_ AssertionType, _ string) (AssertionID, error) {
+	return 0, errNoIOPM
+}
+
+// AllowSleep is a no-op on platforms without IOPMLib; no assertion can be active.
+func AllowSleep(_ AssertionType) {}
+
+// GlobalSleepStatus always fails on platforms without IOPMLib.
+func GlobalSleepStatus() (systemAllowed bool, displayAllowed bool, err error) {
+	return true, true, errNoIOPM
+}
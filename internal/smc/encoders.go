@@ -0,0 +1,117 @@
+package smc
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// encoderFunc encodes a value into the byte layout of one SMC data type.
+// Encoders are the inverse of the decoders in smcDecoders.
+type encoderFunc func(value float64) ([]byte, error)
+
+// smcEncoder pairs an encoder with the data size its type occupies.
+type smcEncoder struct {
+	size   int
+	encode encoderFunc
+}
+
+// smcEncoders mirrors smcDecoders for writes.
+var smcEncoders = map[string]smcEncoder{
+	"flt ": {4, encodeFlt},
+	"sp78": {2, encodeSp78},
+	"fpe2": {2, encodeFpe2},
+	"ui8 ": {1, encodeUnsigned(1)},
+	"ui16": {2, encodeUnsigned(2)},
+	"ui32": {4, encodeUnsigned(4)},
+	"si8 ": {1, encodeSigned(1)},
+	"si16": {2, encodeSigned(2)},
+	"flag": {1, encodeFlag},
+}
+
+// EncodeValue encodes value for a key of the given data type and size, as
+// reported by FetchRawData. Values that the type cannot represent (out of
+// range, or fractional for integer types) are rejected rather than clamped
+// or truncated.
+func EncodeValue(dataType string, dataSize int, value float64) ([]byte, error) {
+	enc, found := smcEncoders[dataType]
+	if !found {
+		return nil, fmt.Errorf("unsupported SMC data type for writing: '%s'", dataType)
+	}
+	if dataSize != enc.size {
+		return nil, fmt.Errorf("invalid data size for type '%s': expected %d, got %d", dataType, enc.size, dataSize)
+	}
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return nil, fmt.Errorf("cannot encode %v as '%s'", value, dataType)
+	}
+	return enc.encode(value)
+}
+
+// --- Individual Encoder Functions ---
+
+func encodeFlt(value float64) ([]byte, error) {
+	if math.Abs(value) > math.MaxFloat32 {
+		return nil, fmt.Errorf("value %v out of range for type 'flt '", value)
+	}
+	b := make([]byte, 4)
+	binary.LittleEndian.PutUint32(b, math.Float32bits(float32(value)))
+	return b, nil
+}
+
+func encodeSp78(value float64) ([]byte, error) {
+	scaled := math.Round(value * 256.0)
+	if scaled < math.MinInt16 || scaled > math.MaxInt16 {
+		return nil, fmt.Errorf("value %v out of range for type 'sp78'", value)
+	}
+	b := make([]byte, 2)
+	binary.LittleEndian.PutUint16(b, uint16(int16(scaled)))
+	return b, nil
+}
+
+func encodeFpe2(value float64) ([]byte, error) {
+	scaled := math.Round(value * 4.0)
+	if scaled < 0 || scaled > math.MaxUint16 {
+		return nil, fmt.Errorf("value %v out of range for type 'fpe2'", value)
+	}
+	b := make([]byte, 2)
+	binary.LittleEndian.PutUint16(b, uint16(scaled))
+	return b, nil
+}
+
+// encodeUnsigned returns a little-endian encoder for a size-byte unsigned integer.
+func encodeUnsigned(size int) encoderFunc {
+	limit := math.Ldexp(1, size*8) - 1
+	return func(value float64) ([]byte, error) {
+		if value != math.Trunc(value) || value < 0 || value > limit {
+			return nil, fmt.Errorf("value %v out of range for a %d-byte unsigned integer", value, size)
+		}
+		return putLittleEndian(uint64(value), size), nil
+	}
+}
+
+// encodeSigned returns a little-endian encoder for a size-byte two's
+// complement integer.
+func encodeSigned(size int) encoderFunc {
+	limit := math.Ldexp(1, size*8-1)
+	return func(value float64) ([]byte, error) {
+		if value != math.Trunc(value) || value < -limit || value > limit-1 {
+			return nil, fmt.Errorf("value %v out of range for a %d-byte signed integer", value, size)
+		}
+		return putLittleEndian(uint64(int64(value)), size), nil
+	}
+}
+
+func encodeFlag(value float64) ([]byte, error) {
+	if value != 0 && value != 1 {
+		return nil, fmt.Errorf("value %v out of range for type 'flag': expected 0 or 1", value)
+	}
+	return []byte{byte(value)}, nil
+}
+
+func putLittleEndian(v uint64, size int) []byte {
+	b := make([]byte, size)
+	for i := range b {
+		b[i] = byte(v >> (8 * i))
+	}
+	return b
+}
@@ -0,0 +1,63 @@
+package smc
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEncodeValueRoundTrips(t *testing.T) {
+	tests := []struct {
+		dataType string
+		size     int
+		value    float64
+		want     []byte
+	}{
+		{"flt ", 4, 19.5, []byte{0x00, 0x00, 0x9c, 0x41}},
+		{"sp78", 2, -1.5, []byte{0x80, 0xfe}},
+		{"fpe2", 2, 1200.25, []byte{0xc1, 0x12}},
+		{"ui8 ", 1, 80, []byte{0x50}},
+		{"ui16", 2, 0x1234, []byte{0x34, 0x12}},
+		{"ui32", 4, 1, []byte{0x01, 0x00, 0x00, 0x00}},
+		{"si8 ", 1, -2, []byte{0xfe}},
+		{"si16", 2, -300, []byte{0xd4, 0xfe}},
+		{"flag", 1, 1, []byte{0x01}},
+	}
+	for _, tt := range tests {
+		got, err := EncodeValue(tt.dataType, tt.size, tt.value)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", tt.dataType, err)
+		}
+		if !bytes.Equal(got, tt.want) {
+			t.Fatalf("%q: expected % x, got % x", tt.dataType, tt.want, got)
+		}
+		decoded, err := decodeSMCValue(tt.dataType, got)
+		if err != nil || decoded != tt.value {
+			t.Fatalf("%q: expected decode back to %v, got %v (%v)", tt.dataType, tt.value, decoded, err)
+		}
+	}
+}
+
+func TestEncodeValueRejectsUnrepresentableValues(t *testing.T) {
+	tests := []struct {
+		dataType string
+		size     int
+		value    float64
+	}{
+		{"ui8 ", 1, 256},
+		{"ui8 ", 1, -1},
+		{"ui16", 2, 1.5},
+		{"si8 ", 1, 128},
+		{"si16", 2, -32769},
+		{"sp78", 2, 128},
+		{"fpe2", 2, -0.25},
+		{"flag", 1, 2},
+		{"flt ", 4, 1e39},
+		{"ui32", 2, 1},
+		{"ch8*", 4, 1},
+	}
+	for _, tt := range tests {
+		if _, err := EncodeValue(tt.dataType, tt.size, tt.value); err == nil {
+			t.Fatalf("%q (size %d): expected %v to be rejected", tt.dataType, tt.size, tt.value)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package smc
+
+import (
+	"fmt"
+	"log"
+)
+
+// RawSMCValue holds the raw, undecoded result of an SMC query.
+type RawSMCValue struct {
+	DataType string
+	DataSize int
+	Data     []byte
+}
+
+// KeyInfo describes an SMC key without its value. Attributes is the raw
+// dataAttributes byte reported by the key-info command.
+type KeyInfo struct {
+	Key        string
+	DataType   string
+	DataSize   int
+	Attributes uint8
+}
+
+// FetchData retrieves a map of SMC keys and their decoded float values.
+// It works by first fetching the raw data and then decoding it.
+func FetchData(keys []string) (map[string]float64, error) {
+	rawResults, err := FetchRawData(keys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch raw SMC data: %w", err)
+	}
+	return DecodeRawData(rawResults)
+}
+
+// DecodeRawData decodes raw SMC values to floats. Keys whose data type has no
+// decoder are omitted; it fails only when nothing could be decoded.
+func DecodeRawData(rawResults map[string]RawSMCValue) (map[string]float64, error) {
+	decodedResults := make(map[string]float64, len(rawResults))
+	for key, rawValue := range rawResults {
+		// Use the Go-side translator to decode the value.
+		decodedValue, err := decodeSMCValue(rawValue.DataType, rawValue.Data)
+		if err == nil {
+			decodedResults[key] = decodedValue
+		} else {
+			// Provide visibility when a key cannot be decoded.
+			log.Printf("powerkit/smc: failed to decode key %s (type %s): %v", key, rawValue.DataType, err)
+		}
+		// Note: Keys with unsupported data types are silently ignored.
+	}
+
+	if len(decodedResults) == 0 {
+		return nil, fmt.Errorf("SMC read succeeded but no keys could be decoded to a float value")
+	}
+
+	return decodedResults, nil
+}
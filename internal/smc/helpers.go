@@ -1,104 +1,256 @@
-//go:build darwin
-
 package smc
 
 import (
 	"encoding/binary"
+	"encoding/hex"
 	"fmt"
 	"math"
+	"strconv"
+	"strings"
 )
 
-// decoderFunc defines the signature for any function that can decode a raw SMC byte slice.
-type decoderFunc func(data []byte) (float64, error)
+// ValueKind classifies a decoded SMC value.
+type ValueKind string
+
+const (
+	// KindNumber values carry their value in Number.
+	KindNumber ValueKind = "number"
+	// KindBool values carry the raw flag byte in Number; String is "true"
+	// for any non-zero byte, which is the boolean reading.
+	KindBool ValueKind = "bool"
+	// KindString values are text (ch8*).
+	KindString ValueKind = "string"
+	// KindBytes values are opaque (hex_ and unknown types).
+	KindBytes ValueKind = "bytes"
+	// KindStruct values carry their members in Fields.
+	KindStruct ValueKind = "struct"
+)
+
+// DecodedValue is a typed SMC value. String is set for every kind: the
+// exact decimal form for numbers, the text for strings and lowercase hex
+// for bytes.
+type DecodedValue struct {
+	Kind   ValueKind
+	Number float64
+	String string
+	Fields map[string]DecodedValue
+}
+
+// decoderFunc decodes a raw SMC byte slice. order is the byte order of
+// multi-byte fields: little-endian on Apple Silicon, big-endian on Intel.
+type decoderFunc func(data []byte, order binary.ByteOrder) (DecodedValue, error)
 
 // smcDecoders is a dispatch map that links a data type string to its corresponding decoder function.
 // This pattern avoids a large switch statement, reducing cyclomatic complexity.
+// Fixed-point types (fpXY, spXY) not listed here are handled by fixedPointDecoder.
 var smcDecoders = map[string]decoderFunc{
 	"flt ": decodeFlt,
-	"sp78": decodeSp78,
-	"fpe2": decodeFpe2,
-	"ui8 ": decodeUI8,
-	"ui16": decodeUI16,
-	"ui32": decodeUI32,
-	"si8 ": decodeSi8,
-	"si16": decodeSi16,
+	"ui8 ": decodeUnsigned(1),
+	"ui16": decodeUnsigned(2),
+	"ui32": decodeUnsigned(4),
+	"ui64": decodeUnsigned(8),
+	"si8 ": decodeSigned(1),
+	"si16": decodeSigned(2),
+	"si32": decodeSigned(4),
+	"si64": decodeSigned(8),
 	"flag": decodeFlag,
+	"ioft": decodeIOFixed,
+	"ch8*": decodeChars,
+	"hex_": decodeHex,
+	"{fds": decodeFanDescriptor,
+	"{pwm": decodeWords,
 }
 
-// decodeSMCValue is the Go "universal translator". It uses the smcDecoders dispatch map
-// to find the correct decoder for the given data type and executes it.
-func decodeSMCValue(dataType string, data []byte) (float64, error) {
+// DecodeValue decodes data of the given SMC data type into a typed value
+// using the given byte order.
+func DecodeValue(dataType string, data []byte, order binary.ByteOrder) (DecodedValue, error) {
 	decoder, found := smcDecoders[dataType]
 	if !found {
-		return 0, fmt.Errorf("unsupported SMC data type: '%s'", dataType)
+		decoder, found = fixedPointDecoder(dataType)
+	}
+	if !found {
+		return DecodedValue{}, fmt.Errorf("unsupported SMC data type: '%s'", dataType)
 	}
-	return decoder(data)
+	return decoder(data, order)
+}
+
+// decodeSMCValue is the Go "universal translator" behind FetchData. It
+// decodes Apple Silicon (little-endian) data and accepts numeric and flag
+// values only.
+func decodeSMCValue(dataType string, data []byte) (float64, error) {
+	v, err := DecodeValue(dataType, data, binary.LittleEndian)
+	if err != nil {
+		return 0, err
+	}
+	if v.Kind != KindNumber && v.Kind != KindBool {
+		return 0, fmt.Errorf("SMC data type '%s' is not numeric (%s)", dataType, v.Kind)
+	}
+	return v.Number, nil
+}
+
+func numberValue(v float64, exact string) DecodedValue {
+	return DecodedValue{Kind: KindNumber, Number: v, String: exact}
+}
+
+func checkSize(dataType string, data []byte, want int) error {
+	if len(data) != want {
+		return fmt.Errorf("invalid data size for type '%s': expected %d, got %d", dataType, want, len(data))
+	}
+	return nil
 }
 
 // --- Individual Decoder Functions ---
 
-func decodeFlt(data []byte) (float64, error) {
-	if len(data) != 4 {
-		return 0, fmt.Errorf("invalid data size for type 'flt ': expected 4, got %d", len(data))
+// decodeFlt reads an IEEE-754 float. Apple Silicon stores it little-endian.
+func decodeFlt(data []byte, order binary.ByteOrder) (DecodedValue, error) {
+	if err := checkSize("flt ", data, 4); err != nil {
+		return DecodedValue{}, err
 	}
-	bits := binary.LittleEndian.Uint32(data)
-	return float64(math.Float32frombits(bits)), nil
+	v := float64(math.Float32frombits(order.Uint32(data)))
+	return numberValue(v, strconv.FormatFloat(v, 'g', -1, 32)), nil
 }
 
-func decodeSp78(data []byte) (float64, error) {
-	if len(data) != 2 {
-		return 0, fmt.Errorf("invalid data size for type 'sp78': expected 2, got %d", len(data))
+// readUint reads a size-byte unsigned integer in the given order.
+func readUint(data []byte, order binary.ByteOrder) uint64 {
+	switch len(data) {
+	case 1:
+		return uint64(data[0])
+	case 2:
+		return uint64(order.Uint16(data))
+	case 4:
+		return uint64(order.Uint32(data))
+	default:
+		return order.Uint64(data)
 	}
-	val := int16(binary.LittleEndian.Uint16(data))
-	return float64(val) / 256.0, nil
 }
 
-func decodeFpe2(data []byte) (float64, error) {
-	if len(data) != 2 {
-		return 0, fmt.Errorf("invalid data size for type 'fpe2': expected 2, got %d", len(data))
+// decodeUnsigned decodes ui8/ui16/ui32/ui64. Single-byte types accept
+// longer payloads and read the first byte, as some keys pad them.
+func decodeUnsigned(size int) decoderFunc {
+	return func(data []byte, order binary.ByteOrder) (DecodedValue, error) {
+		if size == 1 && len(data) > 1 {
+			data = data[:1]
+		}
+		if len(data) != size {
+			return DecodedValue{}, fmt.Errorf("invalid data size for a %d-byte unsigned integer, got %d", size, len(data))
+		}
+		v := readUint(data, order)
+		return numberValue(float64(v), strconv.FormatUint(v, 10)), nil
 	}
-	val := binary.LittleEndian.Uint16(data)
-	return float64(val) / 4.0, nil
 }
 
-func decodeUI8(data []byte) (float64, error) {
-	if len(data) < 1 {
-		return 0, fmt.Errorf("invalid data size for type 'ui8 ': expected at least 1, got %d", len(data))
+// decodeSigned decodes si8/si16/si32/si64 as two's complement.
+func decodeSigned(size int) decoderFunc {
+	return func(data []byte, order binary.ByteOrder) (DecodedValue, error) {
+		if size == 1 && len(data) > 1 {
+			data = data[:1]
+		}
+		if len(data) != size {
+			return DecodedValue{}, fmt.Errorf("invalid data size for a %d-byte signed integer, got %d", size, len(data))
+		}
+		shift := uint(64 - 8*size)
+		v := int64(readUint(data, order)<<shift) >> shift
+		return numberValue(float64(v), strconv.FormatInt(v, 10)), nil
 	}
-	return float64(data[0]), nil
 }
 
-func decodeUI16(data []byte) (float64, error) {
-	if len(data) != 2 {
-		return 0, fmt.Errorf("invalid data size for type 'ui16': expected 2, got %d", len(data))
+func decodeFlag(data []byte, _ binary.ByteOrder) (DecodedValue, error) {
+	if len(data) < 1 {
+		return DecodedValue{}, fmt.Errorf("invalid data size for type 'flag': expected at least 1, got %d", len(data))
 	}
-	return float64(binary.LittleEndian.Uint16(data)), nil
+	// Number keeps the raw byte, since some flag keys have more than two
+	// states; only String reflects the boolean reading.
+	v := DecodedValue{Kind: KindBool, Number: float64(data[0]), String: "false"}
+	if data[0] != 0 {
+		v.String = "true"
+	}
+	return v, nil
 }
 
-func decodeUI32(data []byte) (float64, error) {
-	if len(data) != 4 {
-		return 0, fmt.Errorf("invalid data size for type 'ui32': expected 4, got %d", len(data))
+// decodeIOFixed decodes an 8-byte unsigned fixed-point value with 16
+// fractional bits.
+func decodeIOFixed(data []byte, order binary.ByteOrder) (DecodedValue, error) {
+	if err := checkSize("ioft", data, 8); err != nil {
+		return DecodedValue{}, err
 	}
-	return float64(binary.LittleEndian.Uint32(data)), nil
+	v := float64(order.Uint64(data)) / 65536.0
+	return numberValue(v, strconv.FormatFloat(v, 'f', -1, 64)), nil
 }
 
-func decodeSi8(data []byte) (float64, error) {
-	if len(data) < 1 {
-		return 0, fmt.Errorf("invalid data size for type 'si8 ': expected at least 1, got %d", len(data))
+// fixedPointDecoder builds a decoder for fpXY (unsigned) and spXY (signed)
+// types, where X and Y are hex digits giving the integer and fraction bits
+// of a 16-bit value, e.g. fpe2, fp88, sp78, sp4b.
+func fixedPointDecoder(dataType string) (decoderFunc, bool) {
+	if len(dataType) != 4 || (dataType[:2] != "fp" && dataType[:2] != "sp") {
+		return nil, false
+	}
+	intBits, err1 := strconv.ParseUint(dataType[2:3], 16, 8)
+	fracBits, err2 := strconv.ParseUint(dataType[3:4], 16, 8)
+	if err1 != nil || err2 != nil {
+		return nil, false
+	}
+	signed := dataType[:2] == "sp"
+	total := intBits + fracBits
+	if (signed && total != 15) || (!signed && total != 16) {
+		return nil, false
 	}
-	return float64(int8(data[0])), nil
+
+	scale := math.Ldexp(1, int(fracBits))
+	return func(data []byte, order binary.ByteOrder) (DecodedValue, error) {
+		if err := checkSize(dataType, data, 2); err != nil {
+			return DecodedValue{}, err
+		}
+		raw := order.Uint16(data)
+		v := float64(raw) / scale
+		if signed {
+			v = float64(int16(raw)) / scale
+		}
+		return numberValue(v, strconv.FormatFloat(v, 'f', -1, 64)), nil
+	}, true
+}
+
+// decodeChars decodes ch8* text, dropping trailing NUL padding.
+func decodeChars(data []byte, _ binary.ByteOrder) (DecodedValue, error) {
+	s := strings.TrimRight(string(data), "\x00")
+	return DecodedValue{Kind: KindString, String: s}, nil
+}
+
+func decodeHex(data []byte, _ binary.ByteOrder) (DecodedValue, error) {
+	return DecodedValue{Kind: KindBytes, String: hex.EncodeToString(data)}, nil
 }
 
-func decodeSi16(data []byte) (float64, error) {
-	if len(data) != 2 {
-		return 0, fmt.Errorf("invalid data size for type 'si16': expected 2, got %d", len(data))
+// decodeFanDescriptor decodes the 16-byte {fds fan descriptor: type, zone,
+// location, a reserved byte and a 12-character function name.
+func decodeFanDescriptor(data []byte, _ binary.ByteOrder) (DecodedValue, error) {
+	if err := checkSize("{fds", data, 16); err != nil {
+		return DecodedValue{}, err
+	}
+	byteField := func(b byte) DecodedValue {
+		return numberValue(float64(b), strconv.Itoa(int(b)))
 	}
-	return float64(int16(binary.LittleEndian.Uint16(data))), nil
+	name, _ := decodeChars(data[4:], nil)
+	return DecodedValue{
+		Kind:   KindStruct,
+		String: name.String,
+		Fields: map[string]DecodedValue{
+			"type":     byteField(data[0]),
+			"zone":     byteField(data[1]),
+			"location": byteField(data[2]),
+			"function": name,
+		},
+	}, nil
 }
 
-func decodeFlag(data []byte) (float64, error) {
-	if len(data) < 1 {
-		return 0, fmt.Errorf("invalid data size for type 'flag': expected at least 1, got %d", len(data))
+// decodeWords exposes a struct with no published layout ({pwm) as its
+// 16-bit words, keyed by index, in the SMC byte order.
+func decodeWords(data []byte, order binary.ByteOrder) (DecodedValue, error) {
+	if len(data) == 0 || len(data)%2 != 0 {
+		return DecodedValue{}, fmt.Errorf("invalid data size for a 16-bit word struct: %d", len(data))
+	}
+	fields := make(map[string]DecodedValue, len(data)/2)
+	for i := 0; i < len(data); i += 2 {
+		w := order.Uint16(data[i:])
+		fields[strconv.Itoa(i/2)] = numberValue(float64(w), strconv.Itoa(int(w)))
 	}
-	return float64(data[0]), nil
+	return DecodedValue{Kind: KindStruct, String: hex.EncodeToString(data), Fields: fields}, nil
 }
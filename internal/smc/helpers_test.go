@@ -0,0 +1,81 @@
+package smc
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+func TestDecodeValueTypes(t *testing.T) {
+	tests := []struct {
+		dataType string
+		data     []byte
+		order    binary.ByteOrder
+		kind     ValueKind
+		number   float64
+		text     string
+	}{
+		{"fp88", []byte{0x80, 0x21}, binary.LittleEndian, KindNumber, 33.5, "33.5"},
+		{"fp79", []byte{0x04, 0x00}, binary.BigEndian, KindNumber, 2, "2"},
+		{"fpe2", []byte{0x12, 0xc1}, binary.BigEndian, KindNumber, 1200.25, "1200.25"},
+		{"sp87", []byte{0xff, 0xfe}, binary.BigEndian, KindNumber, -2.0 / 128, "-0.015625"},
+		{"sp96", []byte{0x40, 0x00}, binary.LittleEndian, KindNumber, 1, "1"},
+		{"sp4b", []byte{0x00, 0x08}, binary.LittleEndian, KindNumber, 1, "1"},
+		{"si32", []byte{0xff, 0xff, 0xff, 0xff}, binary.LittleEndian, KindNumber, -1, "-1"},
+		{"ui64", []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, binary.LittleEndian, KindNumber, 18446744073709551615, "18446744073709551615"},
+		{"ui16", []byte{0x12, 0x34}, binary.BigEndian, KindNumber, 0x1234, "4660"},
+		{"ioft", []byte{0x00, 0x80, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00}, binary.LittleEndian, KindNumber, 2.5, "2.5"},
+		{"flag", []byte{0x01}, binary.LittleEndian, KindBool, 1, "true"},
+		{"flag", []byte{0x03}, binary.LittleEndian, KindBool, 3, "true"},
+		{"flag", []byte{0x00}, binary.LittleEndian, KindBool, 0, "false"},
+		{"ch8*", []byte("Mac-1\x00\x00\x00"), binary.LittleEndian, KindString, 0, "Mac-1"},
+		{"hex_", []byte{0xde, 0xad}, binary.LittleEndian, KindBytes, 0, "dead"},
+	}
+	for _, tt := range tests {
+		v, err := DecodeValue(tt.dataType, tt.data, tt.order)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", tt.dataType, err)
+		}
+		if v.Kind != tt.kind || v.Number != tt.number || v.String != tt.text {
+			t.Fatalf("%q: expected %s %v %q, got %+v", tt.dataType, tt.kind, tt.number, tt.text, v)
+		}
+	}
+}
+
+func TestDecodeValueStructs(t *testing.T) {
+	fds := append([]byte{0x01, 0x00, 0x02, 0x00}, []byte("Left Side\x00\x00\x00")...)
+	v, err := DecodeValue("{fds", fds, binary.LittleEndian)
+	if err != nil {
+		t.Fatalf("{fds: unexpected error: %v", err)
+	}
+	if v.Kind != KindStruct || v.Fields["function"].String != "Left Side" || v.Fields["location"].Number != 2 {
+		t.Fatalf("unexpected fan descriptor: %+v", v)
+	}
+
+	pwm, err := DecodeValue("{pwm", []byte{0x00, 0x10, 0x00, 0x20}, binary.BigEndian)
+	if err != nil {
+		t.Fatalf("{pwm: unexpected error: %v", err)
+	}
+	if pwm.Fields["0"].Number != 0x10 || pwm.Fields["1"].Number != 0x20 {
+		t.Fatalf("unexpected pwm words: %+v", pwm.Fields)
+	}
+}
+
+func TestDecodeRawDataKeepsNumericKeysOnly(t *testing.T) {
+	decoded, err := DecodeRawData(map[string]RawSMCValue{
+		"TC0P": {DataType: "sp78", DataSize: 2, Data: []byte{0x00, 0x2a}},
+		"F0Md": {DataType: "fp88", DataSize: 2, Data: []byte{0x00, 0x01}},
+		"RPlt": {DataType: "ch8*", DataSize: 4, Data: []byte("j314")},
+	})
+	if err != nil {
+		t.Fatalf("DecodeRawData returned error: %v", err)
+	}
+	if decoded["TC0P"] != 42 || decoded["F0Md"] != 1 {
+		t.Fatalf("unexpected numeric values: %v", decoded)
+	}
+	if _, ok := decoded["RPlt"]; ok {
+		t.Fatalf("expected string key to be omitted from float results")
+	}
+	if _, err := DecodeValue("fpzz", []byte{0, 0}, binary.LittleEndian); err == nil {
+		t.Fatalf("expected unsupported type error")
+	}
+}
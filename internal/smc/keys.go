@@ -1,6 +1,8 @@
 // Package smc provides internal access to the System Management Controller.
 package smc
 
+import "fmt"
+
 // SMC Key Constants
 const (
 	// Adapter / DC In
@@ -22,8 +24,26 @@ const (
 
 	// Magsafe LED Control
 	KeyMagsafeLED = "ACLC"
+
+	// KeyCount holds the number of keys the SMC exposes.
+	KeyCount = "#KEY"
+
+	// Fans. The per-fan keys are formats taking the fan index (0-9).
+	KeyFanCount        = "FNum"
+	KeyFanUnlock       = "Ftst" // gates F%dMd writes on newer firmware
+	KeyFanActualFormat = "F%dAc"
+	KeyFanMinFormat    = "F%dMn"
+	KeyFanMaxFormat    = "F%dMx"
+	KeyFanTargetFormat = "F%dTg"
+	KeyFanModeFormat   = "F%dMd"
 )
 
+// FanKey returns the key for fan index built from one of the KeyFan*Format
+// constants, for example FanKey(KeyFanActualFormat, 0) == "F0Ac".
+func FanKey(format string, index int) string {
+	return fmt.Sprintf(format, index)
+}
+
 // KeysToRead is the standard list of keys fetched by the main GetSystemInfo function.
 var KeysToRead = []string{
 	KeyAdapterVoltage,
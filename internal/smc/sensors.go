@@ -0,0 +1,65 @@
+package smc
+
+// SensorCategory groups catalog sensors by what they measure.
+type SensorCategory string
+
+// Sensor categories.
+const (
+	CategoryTemperature SensorCategory = "temperature"
+	CategoryFan         SensorCategory = "fan"
+	CategoryVoltage     SensorCategory = "voltage"
+	CategoryCurrent     SensorCategory = "current"
+	CategoryPower       SensorCategory = "power"
+)
+
+// Sensor describes a known SMC sensor key. The decoded value is multiplied
+// by Scale to give a reading in Unit.
+type Sensor struct {
+	Key      string
+	Name     string
+	Category SensorCategory
+	Unit     string
+	Scale    float64
+}
+
+// Sensors is the curated sensor catalog. Keys vary by model; a key that is
+// missing on a machine is simply not reported. Intel-era keys are kept
+// alongside Apple Silicon ones.
+var Sensors = []Sensor{
+	// Temperatures (°C)
+	{Key: "TB0T", Name: "Battery", Category: CategoryTemperature, Unit: "°C", Scale: 1},
+	{Key: "TB1T", Name: "Battery Cell 1", Category: CategoryTemperature, Unit: "°C", Scale: 1},
+	{Key: "TB2T", Name: "Battery Cell 2", Category: CategoryTemperature, Unit: "°C", Scale: 1},
+	{Key: "Tp01", Name: "CPU Performance Core 1", Category: CategoryTemperature, Unit: "°C", Scale: 1},
+	{Key: "Tp05", Name: "CPU Performance Core 2", Category: CategoryTemperature, Unit: "°C", Scale: 1},
+	{Key: "Tp09", Name: "CPU Performance Core 3", Category: CategoryTemperature, Unit: "°C", Scale: 1},
+	{Key: "Tp0D", Name: "CPU Performance Core 4", Category: CategoryTemperature, Unit: "°C", Scale: 1},
+	{Key: "Te05", Name: "CPU Efficiency Core 1", Category: CategoryTemperature, Unit: "°C", Scale: 1},
+	{Key: "Te0L", Name: "CPU Efficiency Core 2", Category: CategoryTemperature, Unit: "°C", Scale: 1},
+	{Key: "Tg05", Name: "GPU 1", Category: CategoryTemperature, Unit: "°C", Scale: 1},
+	{Key: "Tg0D", Name: "GPU 2", Category: CategoryTemperature, Unit: "°C", Scale: 1},
+	{Key: "TC0P", Name: "CPU Proximity", Category: CategoryTemperature, Unit: "°C", Scale: 1},
+	{Key: "TG0P", Name: "GPU Proximity", Category: CategoryTemperature, Unit: "°C", Scale: 1},
+	{Key: "TH0x", Name: "SSD", Category: CategoryTemperature, Unit: "°C", Scale: 1},
+	{Key: "Ts0P", Name: "Palm Rest", Category: CategoryTemperature, Unit: "°C", Scale: 1},
+	{Key: "TW0P", Name: "Wireless Module", Category: CategoryTemperature, Unit: "°C", Scale: 1},
+
+	// Fans (RPM)
+	{Key: "F0Ac", Name: "Fan 1 Speed", Category: CategoryFan, Unit: "rpm", Scale: 1},
+	{Key: "F0Tg", Name: "Fan 1 Target", Category: CategoryFan, Unit: "rpm", Scale: 1},
+	{Key: "F1Ac", Name: "Fan 2 Speed", Category: CategoryFan, Unit: "rpm", Scale: 1},
+	{Key: "F1Tg", Name: "Fan 2 Target", Category: CategoryFan, Unit: "rpm", Scale: 1},
+
+	// Voltages (V)
+	{Key: KeyAdapterVoltage, Name: "DC In", Category: CategoryVoltage, Unit: "V", Scale: 1},
+	{Key: KeyBatteryVoltage, Name: "Battery", Category: CategoryVoltage, Unit: "V", Scale: 0.001},
+
+	// Currents (A)
+	{Key: KeyAdapterCurrent, Name: "DC In", Category: CategoryCurrent, Unit: "A", Scale: 1},
+	{Key: KeyBatteryCurrent, Name: "Battery", Category: CategoryCurrent, Unit: "A", Scale: 0.001},
+
+	// Power (W)
+	{Key: "PSTR", Name: "System Total", Category: CategoryPower, Unit: "W", Scale: 1},
+	{Key: "PDTR", Name: "DC In", Category: CategoryPower, Unit: "W", Scale: 1},
+	{Key: "PPBR", Name: "Battery", Category: CategoryPower, Unit: "W", Scale: 1},
+}
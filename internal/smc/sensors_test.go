@@ -0,0 +1,22 @@
+package smc
+
+import "testing"
+
+func TestSensorCatalogIsWellFormed(t *testing.T) {
+	categories := map[SensorCategory]bool{
+		CategoryTemperature: true, CategoryFan: true, CategoryVoltage: true, CategoryCurrent: true, CategoryPower: true,
+	}
+	seen := make(map[string]bool, len(Sensors))
+	for _, s := range Sensors {
+		if len(s.Key) != 4 {
+			t.Fatalf("sensor %q: key must be 4 characters", s.Key)
+		}
+		if seen[s.Key] {
+			t.Fatalf("sensor %q listed twice", s.Key)
+		}
+		seen[s.Key] = true
+		if !categories[s.Category] || s.Name == "" || s.Unit == "" || s.Scale == 0 {
+			t.Fatalf("sensor %q is incomplete: %+v", s.Key, s)
+		}
+	}
+}
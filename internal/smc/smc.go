@@ -13,6 +13,7 @@ package smc
 #define KERNEL_INDEX_SMC 2
 #define SMC_CMD_READ_BYTES 5
 #define SMC_CMD_WRITE_BYTES 6
+#define SMC_CMD_READ_INDEX 8
 #define SMC_CMD_READ_KEYINFO 9
 
 // --- Data Structures ---
@@ -63,6 +64,22 @@ kern_return_t smc_read_key_raw(
     UInt32* dataSizeResult
 );
 
+// Reads the type, size and attribute byte of a key without its value.
+kern_return_t smc_read_key_info(
+    io_connect_t conn,
+    const char* key,
+    char* dataTypeResult,
+    UInt32* dataSizeResult,
+    unsigned char* attributesResult
+);
+
+// Reads the name of the key at a given index (0 to #KEY - 1).
+kern_return_t smc_read_key_at_index(
+    io_connect_t conn,
+    UInt32 index,
+    char* keyResult
+);
+
 // Writes raw bytes to a given SMC key.
 kern_return_t smc_write_key(
     io_connect_t conn,
@@ -137,6 +154,52 @@ kern_return_t smc_read_key_raw(
     return KERN_SUCCESS;
 }
 
+// --- SMC_READ_KEY_INFO Function ---
+kern_return_t smc_read_key_info(
+    io_connect_t conn,
+    const char* key,
+    char* dataTypeResult,
+    UInt32* dataSizeResult,
+    unsigned char* attributesResult
+) {
+    SMCKeyData_t input;
+    SMCKeyData_t output;
+    size_t structSize = sizeof(SMCKeyData_t);
+
+    memset(&input, 0, structSize);
+    memset(&output, 0, structSize);
+    input.key = str_to_key(key);
+    input.data8 = SMC_CMD_READ_KEYINFO;
+    kern_return_t kr = smc_call(conn, &input, &output);
+    if (kr != KERN_SUCCESS || output.result != 0) return KERN_FAILURE;
+
+    key_to_str(output.keyInfo.dataType, dataTypeResult);
+    *dataSizeResult = output.keyInfo.dataSize;
+    *attributesResult = (unsigned char)output.keyInfo.dataAttributes;
+    return KERN_SUCCESS;
+}
+
+// --- SMC_READ_KEY_AT_INDEX Function ---
+kern_return_t smc_read_key_at_index(
+    io_connect_t conn,
+    UInt32 index,
+    char* keyResult
+) {
+    SMCKeyData_t input;
+    SMCKeyData_t output;
+    size_t structSize = sizeof(SMCKeyData_t);
+
+    memset(&input, 0, structSize);
+    memset(&output, 0, structSize);
+    input.data8 = SMC_CMD_READ_INDEX;
+    input.data32 = index;
+    kern_return_t kr = smc_call(conn, &input, &output);
+    if (kr != KERN_SUCCESS || output.result != 0) return KERN_FAILURE;
+
+    key_to_str(output.key, keyResult);
+    return KERN_SUCCESS;
+}
+
 // --- SMC_WRITE_KEY Function ---
 kern_return_t smc_write_key(
     io_connect_t conn,
@@ -189,8 +252,8 @@ kern_return_t smc_write_key(
 import "C"
 
 import (
+	"encoding/binary"
 	"fmt"
-	"log"
 	"sync"
 	"unsafe"
 )
@@ -229,41 +292,6 @@ func CloseConnection() {
 	}
 }
 
-// RawSMCValue holds the raw, undecoded result of an SMC query.
-type RawSMCValue struct {
-	DataType string
-	DataSize int
-	Data     []byte
-}
-
-// FetchData retrieves a map of SMC keys and their decoded float values.
-// It works by first fetching the raw data and then decoding it.
-func FetchData(keys []string) (map[string]float64, error) {
-	rawResults, err := FetchRawData(keys)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch raw SMC data: %w", err)
-	}
-
-	decodedResults := make(map[string]float64, len(rawResults))
-	for key, rawValue := range rawResults {
-		// Use the Go-side translator to decode the value.
-		decodedValue, err := decodeSMCValue(rawValue.DataType, rawValue.Data)
-		if err == nil {
-			decodedResults[key] = decodedValue
-		} else {
-			// Provide visibility when a key cannot be decoded.
-			log.Printf("powerkit/smc: failed to decode key %s (type %s): %v", key, rawValue.DataType, err)
-		}
-		// Note: Keys with unsupported data types are silently ignored.
-	}
-
-	if len(decodedResults) == 0 {
-		return nil, fmt.Errorf("SMC read succeeded but no keys could be decoded to a float value")
-	}
-
-	return decodedResults, nil
-}
-
 // FetchRawData retrieves the raw, undecoded metadata and byte values for a given list of SMC keys.
 func FetchRawData(keys []string) (map[string]RawSMCValue, error) {
 	smcMu.Lock()
@@ -312,6 +340,107 @@ func FetchRawData(keys []string) (map[string]RawSMCValue, error) {
 	return results, nil
 }
 
+// ListKeys enumerates every key the SMC exposes, in index order, using the
+// #KEY count and the read-key-by-index command. Keys whose info cannot be
+// read are listed by name only.
+func ListKeys() ([]KeyInfo, error) {
+	smcMu.Lock()
+	defer smcMu.Unlock()
+
+	conn, err := getSMCConnection()
+	if err != nil {
+		return nil, err
+	}
+	if conn == 0 {
+		return nil, fmt.Errorf("SMC connection is not valid")
+	}
+
+	count, err := keyCount(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]KeyInfo, 0, count)
+	for i := uint32(0); i < count; i++ {
+		var keyResult [5]C.char
+		if C.smc_read_key_at_index(conn, C.UInt32(i), &keyResult[0]) != C.KERN_SUCCESS {
+			continue
+		}
+		name := C.GoString(&keyResult[0])
+		info, ok := readKeyInfo(conn, name)
+		if !ok {
+			info = KeyInfo{Key: name}
+		}
+		keys = append(keys, info)
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("SMC reported %d keys but none could be read by index", count)
+	}
+	return keys, nil
+}
+
+// ReadKeyInfo reads the type, size and attributes of the given keys.
+// Missing keys are omitted; it fails only when none of the keys exist.
+func ReadKeyInfo(keys []string) (map[string]KeyInfo, error) {
+	smcMu.Lock()
+	defer smcMu.Unlock()
+
+	conn, err := getSMCConnection()
+	if err != nil {
+		return nil, err
+	}
+	if conn == 0 {
+		return nil, fmt.Errorf("SMC connection is not valid")
+	}
+
+	results := make(map[string]KeyInfo, len(keys))
+	for _, key := range keys {
+		if info, ok := readKeyInfo(conn, key); ok {
+			results[key] = info
+		}
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("SMC key info read failed for all requested keys")
+	}
+	return results, nil
+}
+
+// keyCount reads #KEY, which is big-endian on every SMC generation.
+func keyCount(conn C.io_connect_t) (uint32, error) {
+	ckey := C.CString(KeyCount)
+	defer C.free(unsafe.Pointer(ckey))
+
+	var dataTypeResult [5]C.char
+	var bytesResult [32]C.uchar
+	var dataSizeResult C.UInt32
+	kr := C.smc_read_key_raw(conn, ckey, &dataTypeResult[0], &bytesResult[0], &dataSizeResult)
+	if kr != C.KERN_SUCCESS || dataSizeResult < 4 {
+		return 0, fmt.Errorf("failed to read SMC key count (%s) with kern_return: %d", KeyCount, kr)
+	}
+	data := C.GoBytes(unsafe.Pointer(&bytesResult[0]), 4)
+	return binary.BigEndian.Uint32(data), nil
+}
+
+func readKeyInfo(conn C.io_connect_t, key string) (KeyInfo, bool) {
+	ckey := C.CString(key)
+	defer C.free(unsafe.Pointer(ckey))
+
+	var dataTypeResult [5]C.char
+	var dataSizeResult C.UInt32
+	var attributesResult C.uchar
+	kr := C.smc_read_key_info(conn, ckey, &dataTypeResult[0], &dataSizeResult, &attributesResult)
+	if kr != C.KERN_SUCCESS {
+		return KeyInfo{}, false
+	}
+	return KeyInfo{
+		Key:        key,
+		DataType:   C.GoString(&dataTypeResult[0]),
+		DataSize:   int(dataSizeResult),
+		Attributes: uint8(attributesResult),
+	}, true
+}
+
 // WriteData writes raw bytes to a given SMC key.
 func WriteData(key string, data []byte) error {
 	// This function opens its own connection to ensure write operations
@@ -0,0 +1,35 @@
+//go:build !darwin
+
+package smc
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+)
+
+// errNoSMC is returned by every hardware entrypoint on platforms without an AppleSMC service.
+var errNoSMC = fmt.Errorf("SMC is not available on %s: %w", runtime.GOOS, errors.ErrUnsupported)
+
+// CloseConnection is a no-op on platforms without an SMC.
+func CloseConnection() {}
+
+// FetchRawData always fails on platforms without an SMC.
+func FetchRawData(_ []string) (map[string]RawSMCValue, error) {
+	return nil, errNoSMC
+}
+
+// WriteData always fails on platforms without an SMC.
+func WriteData(_ string, _ []byte) error {
+	return errNoSMC
+}
+
+// ReadKeyInfo always fails on platforms without an SMC.
+func ReadKeyInfo(_ []string) (map[string]KeyInfo, error) {
+	return nil, errNoSMC
+}
+
+// ListKeys always fails on platforms without an SMC.
+func ListKeys() ([]KeyInfo, error) {
+	return nil, errNoSMC
+}
@@ -0,0 +1,172 @@
+// Package sysfs reads battery and adapter state from the Linux power_supply
+// class. Values are returned in the kernel's units; conversion to the
+// library's IOKit-equivalent units happens in the public package.
+package sysfs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// DefaultRoot is the standard location of the power_supply class.
+const DefaultRoot = "/sys/class/power_supply"
+
+// ErrNoBattery is returned when no system battery is present under the root.
+var ErrNoBattery = errors.New("no battery found in power_supply class")
+
+// Battery holds the attributes of a power_supply device of type Battery.
+// Attributes the driver does not expose are left at zero.
+type Battery struct {
+	Name         string
+	Status       string // Charging | Discharging | Not charging | Full | Unknown
+	Capacity     int    // %
+	CycleCount   int
+	Temp         int // tenths of a degree Celsius
+	ModelName    string
+	Manufacturer string
+	SerialNumber string
+
+	ChargeNow        int64 // µAh
+	ChargeFull       int64 // µAh
+	ChargeFullDesign int64 // µAh
+	EnergyNow        int64 // µWh
+	EnergyFull       int64 // µWh
+	EnergyFullDesign int64 // µWh
+	VoltageNow       int64 // µV
+	VoltageMinDesign int64 // µV
+	CurrentNow       int64 // µA
+	PowerNow         int64 // µW
+	TimeToEmptyNow   int64 // seconds
+	TimeToFullNow    int64 // seconds
+}
+
+// Adapter holds the attributes of an external power source such as Mains,
+// USB or USB_PD.
+type Adapter struct {
+	Name      string
+	Type      string
+	Online    bool
+	ModelName string
+
+	VoltageNow int64 // µV
+	CurrentNow int64 // µA
+	VoltageMax int64 // µV
+	CurrentMax int64 // µA
+}
+
+// Reading is one pass over the power_supply class.
+type Reading struct {
+	Battery  Battery
+	Adapters []Adapter
+}
+
+// OnlineAdapter returns the first adapter reporting online, if any.
+func (r *Reading) OnlineAdapter() (Adapter, bool) {
+	for _, a := range r.Adapters {
+		if a.Online {
+			return a, true
+		}
+	}
+	return Adapter{}, false
+}
+
+// Read scans root for the first system battery and every external power
+// source. Devices with scope "Device" (peripheral batteries such as mice)
+// are ignored.
+func Read(root string) (*Reading, error) {
+	if root == "" {
+		root = DefaultRoot
+	}
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, fmt.Errorf("read power_supply class: %w", err)
+	}
+
+	reading := &Reading{}
+	foundBattery := false
+	for _, entry := range entries {
+		dir := filepath.Join(root, entry.Name())
+		if strings.EqualFold(readString(dir, "scope"), "Device") {
+			continue
+		}
+		switch supplyType := readString(dir, "type"); supplyType {
+		case "":
+			continue
+		case "Battery":
+			if !foundBattery {
+				reading.Battery = readBattery(dir, entry.Name())
+				foundBattery = true
+			}
+		default:
+			reading.Adapters = append(reading.Adapters, readAdapter(dir, entry.Name(), supplyType))
+		}
+	}
+
+	if !foundBattery {
+		return nil, fmt.Errorf("%w: %s", ErrNoBattery, root)
+	}
+	return reading, nil
+}
+
+func readBattery(dir, name string) Battery {
+	return Battery{
+		Name:             name,
+		Status:           readString(dir, "status"),
+		Capacity:         int(readInt(dir, "capacity")),
+		CycleCount:       int(readInt(dir, "cycle_count")),
+		Temp:             int(readInt(dir, "temp")),
+		ModelName:        readString(dir, "model_name"),
+		Manufacturer:     readString(dir, "manufacturer"),
+		SerialNumber:     readString(dir, "serial_number"),
+		ChargeNow:        readInt(dir, "charge_now"),
+		ChargeFull:       readInt(dir, "charge_full"),
+		ChargeFullDesign: readInt(dir, "charge_full_design"),
+		EnergyNow:        readInt(dir, "energy_now"),
+		EnergyFull:       readInt(dir, "energy_full"),
+		EnergyFullDesign: readInt(dir, "energy_full_design"),
+		VoltageNow:       readInt(dir, "voltage_now"),
+		VoltageMinDesign: readInt(dir, "voltage_min_design"),
+		CurrentNow:       readInt(dir, "current_now"),
+		PowerNow:         readInt(dir, "power_now"),
+		TimeToEmptyNow:   readInt(dir, "time_to_empty_now"),
+		TimeToFullNow:    readInt(dir, "time_to_full_now"),
+	}
+}
+
+func readAdapter(dir, name, supplyType string) Adapter {
+	return Adapter{
+		Name:       name,
+		Type:       supplyType,
+		Online:     readInt(dir, "online") > 0,
+		ModelName:  readString(dir, "model_name"),
+		VoltageNow: readInt(dir, "voltage_now"),
+		CurrentNow: readInt(dir, "current_now"),
+		VoltageMax: readInt(dir, "voltage_max"),
+		CurrentMax: readInt(dir, "current_max"),
+	}
+}
+
+// readString returns the trimmed contents of an attribute, or "" when the
+// attribute is missing or unreadable. Some drivers return EIO/ENODATA for
+// attributes they advertise but cannot currently report.
+func readString(dir, attr string) string {
+	b, err := os.ReadFile(filepath.Join(dir, attr))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(b))
+}
+
+// readInt returns an attribute parsed as an integer, or 0 when it is missing
+// or malformed.
+func readInt(dir, attr string) int64 {
+	v, err := strconv.ParseInt(readString(dir, attr), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return v
+}
@@ -0,0 +1,82 @@
+package sysfs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeSupply(t *testing.T, root, name string, attrs map[string]string) {
+	t.Helper()
+	dir := filepath.Join(root, name)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("mkdir %s: %v", dir, err)
+	}
+	for attr, value := range attrs {
+		if err := os.WriteFile(filepath.Join(dir, attr), []byte(value+"\n"), 0o644); err != nil {
+			t.Fatalf("write %s/%s: %v", name, attr, err)
+		}
+	}
+}
+
+func TestReadBatteryAndAdapters(t *testing.T) {
+	root := t.TempDir()
+	writeSupply(t, root, "AC", map[string]string{"type": "Mains", "online": "1"})
+	writeSupply(t, root, "BAT0", map[string]string{
+		"type":               "Battery",
+		"status":             "Charging",
+		"capacity":           "63",
+		"energy_full":        "50000000",
+		"energy_full_design": "57000000",
+		"voltage_now":        "12450000",
+		"current_now":        "1200000",
+		"cycle_count":        "212",
+		"temp":               "312",
+	})
+	writeSupply(t, root, "hidpp_battery_0", map[string]string{"type": "Battery", "scope": "Device", "capacity": "20"})
+
+	reading, err := Read(root)
+	if err != nil {
+		t.Fatalf("Read returned error: %v", err)
+	}
+	b := reading.Battery
+	if b.Name != "BAT0" || b.Status != "Charging" || b.Capacity != 63 || b.CycleCount != 212 || b.Temp != 312 {
+		t.Fatalf("unexpected battery: %+v", b)
+	}
+	if b.EnergyFull != 50000000 || b.EnergyFullDesign != 57000000 || b.VoltageNow != 12450000 || b.CurrentNow != 1200000 {
+		t.Fatalf("unexpected battery electrical values: %+v", b)
+	}
+	adapter, ok := reading.OnlineAdapter()
+	if !ok || adapter.Name != "AC" || adapter.Type != "Mains" {
+		t.Fatalf("expected online Mains adapter, got %+v ok=%v", adapter, ok)
+	}
+}
+
+func TestReadIgnoresUnreadableAttributes(t *testing.T) {
+	root := t.TempDir()
+	writeSupply(t, root, "BAT1", map[string]string{"type": "Battery", "capacity": "n/a"})
+
+	reading, err := Read(root)
+	if err != nil {
+		t.Fatalf("Read returned error: %v", err)
+	}
+	if reading.Battery.Capacity != 0 {
+		t.Fatalf("expected malformed capacity to read as 0, got %d", reading.Battery.Capacity)
+	}
+	if _, ok := reading.OnlineAdapter(); ok {
+		t.Fatalf("expected no online adapter")
+	}
+}
+
+func TestReadWithoutBattery(t *testing.T) {
+	root := t.TempDir()
+	writeSupply(t, root, "AC", map[string]string{"type": "Mains", "online": "1"})
+
+	if _, err := Read(root); !errors.Is(err, ErrNoBattery) {
+		t.Fatalf("expected ErrNoBattery, got %v", err)
+	}
+	if _, err := Read(filepath.Join(root, "missing")); err == nil {
+		t.Fatalf("expected error for missing root")
+	}
+}
@@ -0,0 +1,118 @@
+package daemon
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+
+	"github.com/peterneutron/powerkit-go/pkg/powerkit"
+)
+
+// Client calls a daemon over its Unix socket. It needs no privileges
+// beyond access to the socket and is safe for concurrent use.
+type Client struct {
+	mu     sync.Mutex
+	conn   net.Conn
+	reader *bufio.Reader
+	nextID int
+}
+
+type clientResponse struct {
+	ID     json.RawMessage `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *Error          `json:"error"`
+}
+
+// Dial connects to the daemon listening at path.
+func Dial(path string) (*Client, error) {
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("could not connect to powerkit daemon: %w", err)
+	}
+	return &Client{conn: conn, reader: bufio.NewReader(conn)}, nil
+}
+
+// Close closes the connection.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// Status returns the daemon's status.
+func (c *Client) Status() (*Status, error) {
+	return c.call(MethodStatus, nil)
+}
+
+// SetLimit starts or updates charge limiting.
+func (c *Client) SetLimit(limit powerkit.ChargeLimit) (*Status, error) {
+	return c.call(MethodSetLimit, limit)
+}
+
+// ClearLimit stops charge limiting and re-enables charging.
+func (c *Client) ClearLimit() (*Status, error) {
+	return c.call(MethodClearLimit, nil)
+}
+
+// SetCharging enables or disables charging while no limit is set.
+func (c *Client) SetCharging(enabled bool) (*Status, error) {
+	return c.call(MethodSetCharging, EnableParams{Enabled: enabled})
+}
+
+// SetAdapter enables or disables the adapter.
+func (c *Client) SetAdapter(enabled bool) (*Status, error) {
+	return c.call(MethodSetAdapter, EnableParams{Enabled: enabled})
+}
+
+// SetTopUp sets the top-up schedule, or stops topping up when schedule is
+// nil.
+func (c *Client) SetTopUp(schedule *powerkit.TopUpSchedule) (*Status, error) {
+	return c.call(MethodSetTopUp, TopUpParams{Schedule: schedule})
+}
+
+// SetThermal sets the thermal policy, or stops pausing charging on
+// temperature when policy is nil.
+func (c *Client) SetThermal(policy *powerkit.ThermalPolicy) (*Status, error) {
+	return c.call(MethodSetThermal, ThermalParams{Policy: policy})
+}
+
+// call sends one request and waits for its response. A rejected request
+// returns an *Error.
+func (c *Client) call(method string, params any) (*Status, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.nextID++
+	req := request{JSONRPC: jsonRPCVersion, ID: json.RawMessage(strconv.Itoa(c.nextID)), Method: method}
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return nil, err
+		}
+		req.Params = data
+	}
+	if err := json.NewEncoder(c.conn).Encode(req); err != nil {
+		return nil, fmt.Errorf("could not send %s request: %w", method, err)
+	}
+
+	line, err := c.reader.ReadBytes('\n')
+	if err != nil {
+		return nil, fmt.Errorf("could not read %s response: %w", method, err)
+	}
+	var resp clientResponse
+	if err := json.Unmarshal(line, &resp); err != nil {
+		return nil, fmt.Errorf("invalid %s response: %w", method, err)
+	}
+	if string(resp.ID) != string(req.ID) {
+		return nil, fmt.Errorf("invalid %s response: id %s, want %s", method, resp.ID, req.ID)
+	}
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+	var status Status
+	if err := json.Unmarshal(resp.Result, &status); err != nil {
+		return nil, fmt.Errorf("invalid %s result: %w", method, err)
+	}
+	return &status, nil
+}
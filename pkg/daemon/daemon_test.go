@@ -0,0 +1,308 @@
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/peterneutron/powerkit-go/internal/smc"
+	"github.com/peterneutron/powerkit-go/pkg/powerkit"
+	"github.com/peterneutron/powerkit-go/pkg/smcsim"
+)
+
+// fakeBattery reports a charge the test sets; the adapter is connected.
+type fakeBattery struct {
+	mu     sync.Mutex
+	charge int
+}
+
+func (b *fakeBattery) FetchBatteryData(bool) (*powerkit.RawBatteryData, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return &powerkit.RawBatteryData{CurrentCharge: b.charge, IsConnected: true}, nil
+}
+
+type fakeEvents struct{}
+
+func (fakeEvents) SubscribeEvents(func()) (<-chan powerkit.EventType, error) {
+	return make(chan powerkit.EventType), nil
+}
+
+// rig is a daemon on a simulated modern Mac, served on a socket in a
+// temporary directory.
+type rig struct {
+	dev    *smcsim.Device
+	socket string
+	state  string
+	stop   func()
+}
+
+func startRig(t *testing.T, charge int, dir string, opts ...ServerOption) *rig {
+	t.Helper()
+	dev := smcsim.NewModern()
+	backend := dev.Backend()
+	backend.Battery = &fakeBattery{charge: charge}
+	backend.Events = fakeEvents{}
+	client := powerkit.New(powerkit.WithBackend(backend), powerkit.WithRootCheck(false))
+
+	r := &rig{dev: dev, socket: filepath.Join(dir, "powerkit.sock"), state: filepath.Join(dir, "state.json")}
+	ln, err := Listen(r.socket, 0o600)
+	if err != nil {
+		t.Fatalf("Listen returned error: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	opts = append([]ServerOption{WithStatePath(r.state)}, opts...)
+	go func() { done <- NewServer(client, opts...).Serve(ctx, ln) }()
+	r.stop = func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Fatalf("Serve returned error: %v", err)
+		}
+	}
+	return r
+}
+
+func (r *rig) chargingEnabled(t *testing.T) bool {
+	t.Helper()
+	k, ok := r.dev.Key(smc.KeyIsChargingEnabled)
+	if !ok {
+		t.Fatalf("simulated SMC has no %s", smc.KeyIsChargingEnabled)
+	}
+	return k.Data[0] == 0x00
+}
+
+func dial(t *testing.T, socket string) *Client {
+	t.Helper()
+	c, err := Dial(socket)
+	if err != nil {
+		t.Fatalf("Dial returned error: %v", err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+	return c
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestDaemonChargeLimit(t *testing.T) {
+	r := startRig(t, 85, t.TempDir())
+	defer r.stop()
+	c := dial(t, r.socket)
+
+	status, err := c.SetLimit(powerkit.ChargeLimit{Upper: 80, Lower: 75})
+	if err != nil {
+		t.Fatalf("SetLimit returned error: %v", err)
+	}
+	if status.Limit == nil || status.Limit.Upper != 80 || status.Charge != 85 {
+		t.Fatalf("unexpected status after SetLimit: %+v", status)
+	}
+	waitFor(t, "the limiter to hold charging", func() bool { return !r.chargingEnabled(t) })
+
+	var rpcErr *Error
+	if _, err := c.SetCharging(true); !errors.As(err, &rpcErr) || rpcErr.Code != CodeLimitActive {
+		t.Fatalf("expected CodeLimitActive, got %v", err)
+	}
+	if _, err := c.SetLimit(powerkit.ChargeLimit{Upper: 70, Lower: 75}); !errors.As(err, &rpcErr) || rpcErr.Code != CodeInvalidParams {
+		t.Fatalf("expected CodeInvalidParams for an inverted limit, got %v", err)
+	}
+
+	status, err = c.ClearLimit()
+	if err != nil {
+		t.Fatalf("ClearLimit returned error: %v", err)
+	}
+	if status.Limit != nil || !status.ChargingEnabled || !r.chargingEnabled(t) {
+		t.Fatalf("expected charging re-enabled after ClearLimit, got %+v", status)
+	}
+
+	if _, err := c.SetCharging(false); err != nil {
+		t.Fatalf("SetCharging returned error: %v", err)
+	}
+	if r.chargingEnabled(t) {
+		t.Fatalf("expected charging disabled")
+	}
+	status, err = c.SetAdapter(false)
+	if err != nil {
+		t.Fatalf("SetAdapter returned error: %v", err)
+	}
+	if status.AdapterEnabled {
+		t.Fatalf("expected adapter disabled, got %+v", status)
+	}
+}
+
+// asPeer makes the server see every connection as coming from uid.
+func asPeer(uid uint32) ServerOption {
+	return func(s *Server) {
+		s.peerUID = func(net.Conn) (uint32, error) { return uid, nil }
+	}
+}
+
+func TestDaemonRejectsUnprivilegedMutations(t *testing.T) {
+	r := startRig(t, 85, t.TempDir(), asPeer(501))
+	defer r.stop()
+	c := dial(t, r.socket)
+
+	if _, err := c.Status(); err != nil {
+		t.Fatalf("Status returned error: %v", err)
+	}
+	calls := map[string]func() (*Status, error){
+		MethodSetLimit:    func() (*Status, error) { return c.SetLimit(powerkit.ChargeLimit{Upper: 80, Lower: 75}) },
+		MethodClearLimit:  c.ClearLimit,
+		MethodSetCharging: func() (*Status, error) { return c.SetCharging(false) },
+		MethodSetAdapter:  func() (*Status, error) { return c.SetAdapter(false) },
+		MethodSetTopUp:    func() (*Status, error) { return c.SetTopUp(nil) },
+		MethodSetThermal:  func() (*Status, error) { return c.SetThermal(nil) },
+	}
+	for method, call := range calls {
+		var rpcErr *Error
+		if _, err := call(); !errors.As(err, &rpcErr) || rpcErr.Code != CodeUnauthorized {
+			t.Fatalf("%s: expected CodeUnauthorized, got %v", method, err)
+		}
+	}
+	if k, _ := r.dev.Key(smc.KeyIsAdapterEnabled); k.Data[0] != 0x00 || !r.chargingEnabled(t) {
+		t.Fatalf("expected no SMC change from rejected calls")
+	}
+}
+
+func TestDaemonControlGroup(t *testing.T) {
+	member, err := user.Lookup("daemon")
+	if err != nil || member.Uid == "0" {
+		t.Skip("no unprivileged 'daemon' user to test group membership with")
+	}
+	uid, _ := strconv.ParseUint(member.Uid, 10, 32)
+	r := startRig(t, 85, t.TempDir(), asPeer(uint32(uid)), WithControlGroup(member.Gid))
+	defer r.stop()
+
+	if _, err := dial(t, r.socket).SetCharging(false); err != nil {
+		t.Fatalf("expected a control group member to set charging, got %v", err)
+	}
+	if r.chargingEnabled(t) {
+		t.Fatalf("expected charging disabled")
+	}
+}
+
+func TestDaemonRestoresPersistedLimit(t *testing.T) {
+	dir := t.TempDir()
+	r := startRig(t, 90, dir)
+	c := dial(t, r.socket)
+	if _, err := c.SetLimit(powerkit.ChargeLimit{Upper: 80, Lower: 70}); err != nil {
+		t.Fatalf("SetLimit returned error: %v", err)
+	}
+	schedule, err := powerkit.ParseTopUpSchedule("weekdays 08:00")
+	if err != nil {
+		t.Fatalf("ParseTopUpSchedule returned error: %v", err)
+	}
+	if status, err := c.SetTopUp(&schedule); err != nil || status.TopUp == nil {
+		t.Fatalf("unexpected SetTopUp result %+v (err %v)", status, err)
+	}
+	policy := powerkit.DefaultThermalPolicy()
+	if status, err := c.SetThermal(&policy); err != nil || status.Thermal == nil {
+		t.Fatalf("unexpected SetThermal result %+v (err %v)", status, err)
+	}
+	r.stop()
+	if !r.chargingEnabled(t) {
+		t.Fatalf("expected charging re-enabled when the daemon stops")
+	}
+
+	r = startRig(t, 90, dir)
+	defer r.stop()
+	status, err := dial(t, r.socket).Status()
+	if err != nil {
+		t.Fatalf("Status returned error: %v", err)
+	}
+	if status.Limit == nil || *status.Limit != (powerkit.ChargeLimit{Upper: 80, Lower: 70}) {
+		t.Fatalf("expected the persisted limit, got %+v", status.Limit)
+	}
+	if status.TopUp == nil || status.TopUp.String() != "weekdays 08:00" {
+		t.Fatalf("expected the persisted top-up, got %v", status.TopUp)
+	}
+	if status.Thermal == nil || *status.Thermal != powerkit.DefaultThermalPolicy() {
+		t.Fatalf("expected the persisted thermal policy, got %+v", status.Thermal)
+	}
+	waitFor(t, "the restored limiter to hold charging", func() bool { return !r.chargingEnabled(t) })
+}
+
+func TestListenRefusesLiveDaemon(t *testing.T) {
+	dir := t.TempDir()
+	r := startRig(t, 85, dir)
+	if _, err := Listen(r.socket, 0o600); !errors.Is(err, ErrAlreadyRunning) {
+		t.Fatalf("expected ErrAlreadyRunning, got %v", err)
+	}
+	if _, err := dial(t, r.socket).Status(); err != nil {
+		t.Fatalf("expected the running daemon to keep its socket, got %v", err)
+	}
+	r.stop()
+
+	// A socket left behind by a crashed daemon is replaced.
+	stale, err := net.Listen("unix", r.socket)
+	if err != nil {
+		t.Fatalf("net.Listen returned error: %v", err)
+	}
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	_ = stale.Close()
+	ln, err := Listen(r.socket, 0o600)
+	if err != nil {
+		t.Fatalf("expected a stale socket to be replaced, got %v", err)
+	}
+	_ = ln.Close()
+}
+
+func TestDaemonProtocolErrors(t *testing.T) {
+	r := startRig(t, 50, t.TempDir())
+	defer r.stop()
+	conn, err := net.Dial("unix", r.socket)
+	if err != nil {
+		t.Fatalf("Dial returned error: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+	reader := bufio.NewReader(conn)
+
+	tests := []struct {
+		name    string
+		request string
+		code    int
+	}{
+		{name: "parse error", request: `{"jsonrpc":`, code: CodeParseError},
+		{name: "wrong version", request: `{"jsonrpc":"1.0","id":1,"method":"status"}`, code: CodeInvalidRequest},
+		{name: "unknown method", request: `{"jsonrpc":"2.0","id":2,"method":"reboot"}`, code: CodeMethodNotFound},
+		{name: "missing params", request: `{"jsonrpc":"2.0","id":3,"method":"set_limit"}`, code: CodeInvalidParams},
+		{name: "invalid thermal policy", request: `{"jsonrpc":"2.0","id":6,"method":"set_thermal","params":{"policy":{"ceiling":35,"resume":40}}}`, code: CodeInvalidParams},
+		{name: "invalid schedule", request: `{"jsonrpc":"2.0","id":5,"method":"set_top_up","params":{"schedule":"daily 25:00"}}`, code: CodeInvalidParams},
+		{name: "notification then request", request: "{\"jsonrpc\":\"2.0\",\"method\":\"status\"}\n{\"jsonrpc\":\"2.0\",\"id\":4,\"method\":\"reboot\"}", code: CodeMethodNotFound},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := conn.Write([]byte(tc.request + "\n")); err != nil {
+				t.Fatalf("Write returned error: %v", err)
+			}
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				t.Fatalf("ReadString returned error: %v", err)
+			}
+			var resp clientResponse
+			if err := json.Unmarshal([]byte(line), &resp); err != nil {
+				t.Fatalf("invalid response %q: %v", line, err)
+			}
+			if resp.Error == nil || resp.Error.Code != tc.code {
+				t.Fatalf("expected error code %d, got %s", tc.code, strings.TrimSpace(line))
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package daemon
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/user"
+	"slices"
+	"strconv"
+	"syscall"
+)
+
+// peer is the identity of a connected client.
+type peer struct {
+	uid uint32
+	err error
+}
+
+// mayControl reports whether p may call mutating methods: root, the user
+// the daemon runs as, or a member of the control group.
+func (s *Server) mayControl(p peer) bool {
+	if p.err != nil {
+		return false
+	}
+	if p.uid == 0 || p.uid == uint32(os.Geteuid()) {
+		return true
+	}
+	return s.controlGroup != "" && inGroup(p.uid, s.controlGroup)
+}
+
+// inGroup reports whether the user with uid is a member of the group with
+// gid, including as the primary group.
+func inGroup(uid uint32, gid string) bool {
+	u, err := user.LookupId(strconv.FormatUint(uint64(uid), 10))
+	if err != nil {
+		return false
+	}
+	groups, err := u.GroupIds()
+	return u.Gid == gid || (err == nil && slices.Contains(groups, gid))
+}
+
+func syscallConn(conn net.Conn) (syscall.RawConn, error) {
+	sc, ok := conn.(syscall.Conn)
+	if !ok {
+		return nil, fmt.Errorf("connection %T has no file descriptor", conn)
+	}
+	return sc.SyscallConn()
+}
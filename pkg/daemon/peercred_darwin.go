@@ -0,0 +1,54 @@
+//go:build darwin
+
+package daemon
+
+import (
+	"fmt"
+	"net"
+	"syscall"
+	"unsafe"
+)
+
+// Darwin's LOCAL_PEERCRED socket option, at level SOL_LOCAL, returns a
+// struct xucred.
+const (
+	solLocal       = 0
+	localPeerCred  = 0x001
+	xucredVersion  = 0
+	xucredMaxGroup = 16
+)
+
+type xucred struct {
+	Version uint32
+	UID     uint32
+	NGroups int16
+	Groups  [xucredMaxGroup]uint32
+}
+
+// peerUID reads the peer's effective user ID with LOCAL_PEERCRED.
+func peerUID(conn net.Conn) (uint32, error) {
+	raw, err := syscallConn(conn)
+	if err != nil {
+		return 0, err
+	}
+	var cred xucred
+	var credErr error
+	err = raw.Control(func(fd uintptr) {
+		size := uint32(unsafe.Sizeof(cred))
+		_, _, errno := syscall.Syscall6(syscall.SYS_GETSOCKOPT, fd, solLocal, localPeerCred,
+			uintptr(unsafe.Pointer(&cred)), uintptr(unsafe.Pointer(&size)), 0)
+		if errno != 0 {
+			credErr = errno
+		}
+	})
+	if err == nil {
+		err = credErr
+	}
+	if err == nil && cred.Version != xucredVersion {
+		err = fmt.Errorf("unexpected xucred version %d", cred.Version)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("could not read peer credentials: %w", err)
+	}
+	return cred.UID, nil
+}
@@ -0,0 +1,29 @@
+//go:build linux
+
+package daemon
+
+import (
+	"fmt"
+	"net"
+	"syscall"
+)
+
+// peerUID reads the peer's effective user ID with SO_PEERCRED.
+func peerUID(conn net.Conn) (uint32, error) {
+	raw, err := syscallConn(conn)
+	if err != nil {
+		return 0, err
+	}
+	var cred *syscall.Ucred
+	var credErr error
+	err = raw.Control(func(fd uintptr) {
+		cred, credErr = syscall.GetsockoptUcred(int(fd), syscall.SOL_SOCKET, syscall.SO_PEERCRED)
+	})
+	if err == nil {
+		err = credErr
+	}
+	if err != nil {
+		return 0, fmt.Errorf("could not read peer credentials: %w", err)
+	}
+	return cred.Uid, nil
+}
@@ -0,0 +1,14 @@
+//go:build !darwin && !linux
+
+package daemon
+
+import (
+	"errors"
+	"net"
+)
+
+// peerUID is unavailable on this platform, so only read-only methods are
+// served.
+func peerUID(net.Conn) (uint32, error) {
+	return 0, errors.New("peer credentials are not supported on this platform")
+}
@@ -0,0 +1,120 @@
+// Package daemon runs charge and adapter control as a privileged service
+// and lets unprivileged processes drive it over a Unix socket. The protocol
+// is JSON-RPC 2.0 with one request or response object per line.
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/peterneutron/powerkit-go/pkg/powerkit"
+)
+
+// DefaultSocketPath is where powerkit-cli daemon listens by default.
+const DefaultSocketPath = "/var/run/powerkit.sock"
+
+// Methods served by the daemon.
+const (
+	// MethodStatus takes no params and returns a Status. It is the only
+	// method open to every peer that can reach the socket.
+	MethodStatus = "status"
+	// MethodSetLimit takes a powerkit.ChargeLimit, starts or updates the
+	// charge limiter and returns a Status.
+	MethodSetLimit = "set_limit"
+	// MethodClearLimit stops the charge limiter, which re-enables charging,
+	// and returns a Status.
+	MethodClearLimit = "clear_limit"
+	// MethodSetCharging takes EnableParams and returns a Status. It fails
+	// with CodeLimitActive while a limit is set.
+	MethodSetCharging = "set_charging"
+	// MethodSetAdapter takes EnableParams and returns a Status.
+	MethodSetAdapter = "set_adapter"
+	// MethodSetTopUp takes TopUpParams and returns a Status. The schedule
+	// applies whenever a limit is set.
+	MethodSetTopUp = "set_top_up"
+	// MethodSetThermal takes ThermalParams and returns a Status. The policy
+	// applies whenever a limit is set.
+	MethodSetThermal = "set_thermal"
+)
+
+// JSON-RPC error codes. CodeFailed carries the error of a failed operation;
+// CodeUnauthorized rejects a mutating method from a peer that is not root,
+// the daemon's user or a member of its control group.
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeFailed         = -32000
+	CodeLimitActive    = -32001
+	CodeUnauthorized   = -32002
+)
+
+const jsonRPCVersion = "2.0"
+
+// EnableParams are the params of MethodSetCharging and MethodSetAdapter.
+type EnableParams struct {
+	Enabled bool `json:"enabled"`
+}
+
+// TopUpParams are the params of MethodSetTopUp. A null Schedule stops
+// topping up.
+type TopUpParams struct {
+	Schedule *powerkit.TopUpSchedule `json:"schedule"`
+}
+
+// ThermalParams are the params of MethodSetThermal. A null Policy stops
+// pausing charging on temperature.
+type ThermalParams struct {
+	Policy *powerkit.ThermalPolicy `json:"policy"`
+}
+
+// Status is the daemon's view of the battery and its controls.
+type Status struct {
+	Charge           int  `json:"charge"`
+	AdapterConnected bool `json:"adapter_connected"`
+	ChargingEnabled  bool `json:"charging_enabled"`
+	AdapterEnabled   bool `json:"adapter_enabled"`
+	// Limit is the active charge limit, nil when none is set. Holding is
+	// true while the limiter keeps charging disabled.
+	Limit   *powerkit.ChargeLimit `json:"limit"`
+	Holding bool                  `json:"holding"`
+	// TopUp is the top-up schedule, nil when none is set. ToppingUp is true
+	// while the limiter charges to 100% for TopUpDeadline.
+	TopUp         *powerkit.TopUpSchedule `json:"top_up,omitempty"`
+	ToppingUp     bool                    `json:"topping_up"`
+	TopUpDeadline *time.Time              `json:"top_up_deadline,omitempty"`
+	// Thermal is the thermal policy, nil when none is set. ThermalPaused is
+	// true while it holds charging.
+	Thermal       *powerkit.ThermalPolicy `json:"thermal,omitempty"`
+	ThermalPaused bool                    `json:"thermal_paused"`
+	// LimiterError is the limiter's last evaluation error.
+	LimiterError string    `json:"limiter_error,omitempty"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// Error is a JSON-RPC error object. It is returned by Client calls the
+// daemon rejected.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("daemon error %d: %s", e.Code, e.Message)
+}
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
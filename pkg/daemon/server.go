@@ -0,0 +1,487 @@
+package daemon
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/peterneutron/powerkit-go/pkg/powerkit"
+)
+
+// ErrAlreadyRunning is returned by Listen when another daemon answers on
+// the socket path.
+var ErrAlreadyRunning = errors.New("daemon already running")
+
+// maxRequestSize bounds a single request line.
+const maxRequestSize = 64 * 1024
+
+// Server owns charging and adapter control for one powerkit client. It
+// runs a powerkit.ChargeLimiter, with the top-up schedule and thermal
+// policy if set, while a limit is set.
+type Server struct {
+	client       *powerkit.Client
+	statePath    string
+	controlGroup string
+	peerUID      func(net.Conn) (uint32, error)
+
+	mu      sync.Mutex
+	limiter *powerkit.ChargeLimiter
+	limit   *powerkit.ChargeLimit
+	topUp   *powerkit.TopUpSchedule
+	thermal *powerkit.ThermalPolicy
+	stop    context.CancelFunc
+	stopped chan struct{}
+	runErr  error
+}
+
+// ServerOption configures a Server created by NewServer.
+type ServerOption func(*Server)
+
+// WithStatePath persists the charge limit, top-up schedule and thermal
+// policy to path, so a restarted daemon resumes limiting.
+func WithStatePath(path string) ServerOption {
+	return func(s *Server) {
+		s.statePath = path
+	}
+}
+
+// WithControlGroup lets members of the group with ID gid call mutating
+// methods, besides root and the user the daemon runs as.
+func WithControlGroup(gid string) ServerOption {
+	return func(s *Server) {
+		s.controlGroup = gid
+	}
+}
+
+// NewServer creates a server controlling the battery through client, or
+// the default client when client is nil. A limit persisted at the state
+// path is restored when Serve starts.
+func NewServer(client *powerkit.Client, opts ...ServerOption) *Server {
+	if client == nil {
+		client = powerkit.Default()
+	}
+	s := &Server{client: client, peerUID: peerUID}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Listen creates the Unix socket at path with the given permissions,
+// replacing a stale socket left by an earlier run. It returns
+// ErrAlreadyRunning when a daemon still answers on path.
+func Listen(path string, mode os.FileMode) (net.Listener, error) {
+	if info, err := os.Lstat(path); err == nil && info.Mode()&os.ModeSocket != 0 {
+		conn, err := net.Dial("unix", path)
+		if err == nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("%w on %s", ErrAlreadyRunning, path)
+		}
+		if !errors.Is(err, syscall.ECONNREFUSED) && !errors.Is(err, syscall.ENOENT) {
+			return nil, err
+		}
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+	}
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, mode); err != nil {
+		_ = ln.Close()
+		return nil, err
+	}
+	return ln, nil
+}
+
+// Serve restores the persisted limit and answers connections on ln until
+// ctx is done. It then closes ln and stops the limiter, which re-enables
+// charging.
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	if err := s.restore(); err != nil {
+		return err
+	}
+	defer s.stopLimiter()
+
+	go func() {
+		<-ctx.Done()
+		_ = ln.Close()
+	}()
+
+	var conns sync.WaitGroup
+	defer conns.Wait()
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		conns.Add(1)
+		go func() {
+			defer conns.Done()
+			s.serveConn(ctx, conn)
+		}()
+	}
+}
+
+// serveConn answers requests on conn until the peer closes it or ctx is done.
+// The peer's credentials are read once and gate every mutating request.
+func (s *Server) serveConn(ctx context.Context, conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+	var p peer
+	p.uid, p.err = s.peerUID(conn)
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, 4096), maxRequestSize)
+	enc := json.NewEncoder(conn)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		resp, reply := s.handle(line, p)
+		if !reply {
+			continue
+		}
+		if err := enc.Encode(resp); err != nil {
+			return
+		}
+	}
+}
+
+// handle decodes and dispatches one request. Notifications, requests
+// without an id, get no reply.
+func (s *Server) handle(line []byte, p peer) (response, bool) {
+	var req request
+	if err := json.Unmarshal(line, &req); err != nil {
+		return errorResponse(nil, CodeParseError, err.Error()), true
+	}
+	if req.JSONRPC != jsonRPCVersion || req.Method == "" {
+		return errorResponse(req.ID, CodeInvalidRequest, "expected a JSON-RPC 2.0 request"), true
+	}
+
+	var result *Status
+	rpcErr := s.authorize(req.Method, p)
+	if rpcErr == nil {
+		result, rpcErr = s.call(req.Method, req.Params)
+	}
+	if len(req.ID) == 0 {
+		return response{}, false
+	}
+	if rpcErr != nil {
+		return response{JSONRPC: jsonRPCVersion, ID: req.ID, Error: rpcErr}, true
+	}
+	return response{JSONRPC: jsonRPCVersion, ID: req.ID, Result: result}, true
+}
+
+// authorize rejects mutating methods from peers that may not control the
+// battery.
+func (s *Server) authorize(method string, p peer) *Error {
+	if method == MethodStatus || s.mayControl(p) {
+		return nil
+	}
+	if p.err != nil {
+		return &Error{Code: CodeUnauthorized, Message: fmt.Sprintf("%s requires a verified peer: %v", method, p.err)}
+	}
+	return &Error{Code: CodeUnauthorized, Message: fmt.Sprintf("%s is not permitted for uid %d", method, p.uid)}
+}
+
+func (s *Server) call(method string, params json.RawMessage) (*Status, *Error) {
+	switch method {
+	case MethodStatus:
+		return s.status(), nil
+	case MethodSetLimit:
+		var limit powerkit.ChargeLimit
+		if err := decodeParams(params, &limit); err != nil {
+			return nil, err
+		}
+		return s.result(s.setLimit(limit))
+	case MethodClearLimit:
+		return s.result(s.clearLimit())
+	case MethodSetCharging, MethodSetAdapter:
+		var p EnableParams
+		if err := decodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		return s.result(s.setControl(method, p.Enabled))
+	case MethodSetTopUp:
+		var p TopUpParams
+		if err := decodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		return s.result(s.setTopUp(p.Schedule))
+	case MethodSetThermal:
+		var p ThermalParams
+		if err := decodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		return s.result(s.setThermal(p.Policy))
+	default:
+		return nil, &Error{Code: CodeMethodNotFound, Message: fmt.Sprintf("unknown method %q", method)}
+	}
+}
+
+// result turns an operation error into a JSON-RPC error or the new status.
+func (s *Server) result(err error) (*Status, *Error) {
+	var rpcErr *Error
+	switch {
+	case errors.As(err, &rpcErr):
+		return nil, rpcErr
+	case err != nil:
+		return nil, &Error{Code: CodeFailed, Message: err.Error()}
+	}
+	return s.status(), nil
+}
+
+func decodeParams(params json.RawMessage, v any) *Error {
+	if len(params) == 0 {
+		return &Error{Code: CodeInvalidParams, Message: "missing params"}
+	}
+	if err := json.Unmarshal(params, v); err != nil {
+		return &Error{Code: CodeInvalidParams, Message: err.Error()}
+	}
+	return nil
+}
+
+func errorResponse(id json.RawMessage, code int, message string) response {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	return response{JSONRPC: jsonRPCVersion, ID: id, Error: &Error{Code: code, Message: message}}
+}
+
+// status reads the battery and SMC state and merges in the limiter's.
+func (s *Server) status() *Status {
+	st := &Status{UpdatedAt: time.Now()}
+	if info, err := s.client.GetSystemInfo(powerkit.FetchOptions{QueryIOKit: true, QuerySMC: true}); err == nil {
+		if info.IOKit != nil {
+			st.Charge = info.IOKit.Battery.CurrentCharge
+			st.AdapterConnected = info.IOKit.State.IsConnected
+		}
+		if info.SMC != nil {
+			st.ChargingEnabled = info.SMC.State.IsChargingEnabled
+			st.AdapterEnabled = info.SMC.State.IsAdapterEnabled
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st.TopUp, st.Thermal = s.topUp, s.thermal
+	if s.limit != nil {
+		limit := *s.limit
+		st.Limit = &limit
+		ls := s.limiter.Status()
+		st.Holding = ls.Holding
+		st.ToppingUp = ls.ToppingUp
+		st.TopUpDeadline = ls.TopUpDeadline
+		st.ThermalPaused = ls.ThermalPaused
+		st.LimiterError = ls.LastError
+	}
+	if s.runErr != nil {
+		st.LimiterError = s.runErr.Error()
+	}
+	return st
+}
+
+// setLimit starts the limiter or changes its limit, then persists it.
+func (s *Server) setLimit(limit powerkit.ChargeLimit) error {
+	if err := limit.Validate(); err != nil {
+		return &Error{Code: CodeInvalidParams, Message: err.Error()}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.limiter == nil {
+		limiter, err := powerkit.NewChargeLimiter(s.client, limit)
+		if err != nil {
+			return err
+		}
+		if err := limiter.SetTopUp(s.topUp); err != nil {
+			return err
+		}
+		if err := limiter.SetThermalPolicy(s.thermal); err != nil {
+			return err
+		}
+		s.limiter = limiter
+	} else if err := s.limiter.SetLimit(limit); err != nil {
+		return err
+	}
+	s.limit = &limit
+	if s.stop == nil {
+		s.startLimiter()
+	}
+	return s.saveState()
+}
+
+func (s *Server) clearLimit() error {
+	s.stopLimiter()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.limit = nil
+	return s.saveState()
+}
+
+// setTopUp sets the top-up schedule of the current and any later limiter,
+// then persists it.
+func (s *Server) setTopUp(schedule *powerkit.TopUpSchedule) error {
+	if schedule != nil {
+		if err := schedule.Validate(); err != nil {
+			return &Error{Code: CodeInvalidParams, Message: err.Error()}
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.limiter != nil {
+		if err := s.limiter.SetTopUp(schedule); err != nil {
+			return err
+		}
+	}
+	s.topUp = schedule
+	return s.saveState()
+}
+
+// setThermal sets the thermal policy of the current and any later limiter,
+// then persists it.
+func (s *Server) setThermal(policy *powerkit.ThermalPolicy) error {
+	if policy != nil {
+		if err := policy.Validate(); err != nil {
+			return &Error{Code: CodeInvalidParams, Message: err.Error()}
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.limiter != nil {
+		if err := s.limiter.SetThermalPolicy(policy); err != nil {
+			return err
+		}
+	}
+	s.thermal = policy
+	return s.saveState()
+}
+
+func (s *Server) setControl(method string, enable bool) error {
+	if method == MethodSetAdapter {
+		var action powerkit.AdapterAction = powerkit.AdapterActionOff
+		if enable {
+			action = powerkit.AdapterActionOn
+		}
+		return s.client.SetAdapterState(action)
+	}
+
+	s.mu.Lock()
+	active := s.limit != nil
+	s.mu.Unlock()
+	if active {
+		return &Error{Code: CodeLimitActive, Message: "a charge limit is active; clear it before setting charging"}
+	}
+	var action powerkit.ChargingAction = powerkit.ChargingActionOff
+	if enable {
+		action = powerkit.ChargingActionOn
+	}
+	return s.client.SetChargingState(action)
+}
+
+// startLimiter runs the limiter in the background. The caller holds s.mu.
+func (s *Server) startLimiter() {
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	s.stop, s.stopped, s.runErr = cancel, stopped, nil
+
+	limiter := s.limiter
+	go func() {
+		defer close(stopped)
+		err := limiter.Run(ctx)
+		if errors.Is(err, context.Canceled) {
+			return
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.runErr = err
+		if s.stopped == stopped {
+			s.stop = nil
+		}
+	}()
+}
+
+// stopLimiter stops a running limiter and waits for it to re-enable
+// charging.
+func (s *Server) stopLimiter() {
+	s.mu.Lock()
+	stop, stopped := s.stop, s.stopped
+	s.stop = nil
+	s.mu.Unlock()
+	if stop == nil {
+		return
+	}
+	stop()
+	<-stopped
+}
+
+// state is the persisted form of the daemon's settings.
+type state struct {
+	Limit   *powerkit.ChargeLimit   `json:"limit"`
+	TopUp   *powerkit.TopUpSchedule `json:"top_up,omitempty"`
+	Thermal *powerkit.ThermalPolicy `json:"thermal,omitempty"`
+}
+
+// saveState writes the limit, top-up schedule and thermal policy to the
+// state path. The caller holds s.mu.
+func (s *Server) saveState() error {
+	if s.statePath == "" {
+		return nil
+	}
+	data, err := json.Marshal(state{Limit: s.limit, TopUp: s.topUp, Thermal: s.thermal})
+	if err != nil {
+		return err
+	}
+	tmp := s.statePath + ".tmp"
+	if err := os.MkdirAll(filepath.Dir(s.statePath), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.statePath)
+}
+
+// restore starts limiting to the persisted limit, if any, with the
+// persisted top-up schedule and thermal policy.
+func (s *Server) restore() error {
+	if s.statePath == "" {
+		return nil
+	}
+	data, err := os.ReadFile(s.statePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var st state
+	if err := json.Unmarshal(data, &st); err != nil {
+		return fmt.Errorf("could not read daemon state %s: %w", s.statePath, err)
+	}
+	s.mu.Lock()
+	s.topUp, s.thermal = st.TopUp, st.Thermal
+	s.mu.Unlock()
+	if st.Limit == nil {
+		return nil
+	}
+	return s.setLimit(*st.Limit)
+}
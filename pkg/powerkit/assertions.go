@@ -1,8 +1,8 @@
-//go:build darwin
-
 package powerkit
 
 import (
+	"strconv"
+
 	"github.com/peterneutron/powerkit-go/internal/powerd"
 )
 
@@ -22,32 +22,52 @@ type AssertionID uint32
 // CreateAssertion creates a power assertion of the specified type.
 // It is safe to call this function multiple times; it will only create one
 // assertion of each type. The reason is shown in Activity Monitor.
-func CreateAssertion(assertionType AssertionType, reason string) (AssertionID, error) {
-	id, err := powerd.PreventSleep(powerd.AssertionType(assertionType), reason)
-	return AssertionID(id), err
+func (c *Client) CreateAssertion(assertionType AssertionType, reason string) (AssertionID, error) {
+	id, err := c.backend.CreateAssertion(assertionType, reason)
+	c.recordAudit(AuditRecord{Operation: "create assertion", Key: assertionAuditKey(assertionType), New: reason}, err)
+	return id, err
 }
 
 // ReleaseAssertion releases an active power assertion of the specified type.
 // It is safe to call this function even if no assertion of that type is active.
-func ReleaseAssertion(assertionType AssertionType) {
-	powerd.AllowSleep(powerd.AssertionType(assertionType))
+func (c *Client) ReleaseAssertion(assertionType AssertionType) {
+	id, active := c.backend.ActiveAssertion(assertionType)
+	c.backend.ReleaseAssertion(assertionType)
+	record := AuditRecord{Operation: "release assertion", Key: assertionAuditKey(assertionType)}
+	if active {
+		record.Previous = strconv.FormatUint(uint64(id), 10)
+	}
+	c.recordAudit(record, nil)
 }
 
 // AllowAllSleep is a convenience function to release all active assertions
 // created by this package. This is useful for cleanup on application exit.
-func AllowAllSleep() {
-	powerd.AllowAllSleep()
+func (c *Client) AllowAllSleep() {
+	c.backend.ReleaseAllAssertions()
+	c.recordAudit(AuditRecord{Operation: "release all assertions"}, nil)
+}
+
+// assertionAuditKey names an assertion type in audit records.
+func assertionAuditKey(assertionType AssertionType) string {
+	switch assertionType {
+	case AssertionTypePreventSystemSleep:
+		return "prevent_system_sleep"
+	case AssertionTypePreventDisplaySleep:
+		return "prevent_display_sleep"
+	default:
+		return "unknown"
+	}
 }
 
 // IsAssertionActive reports whether an assertion of the given type is active
 // (created and not released) by this process.
-func IsAssertionActive(assertionType AssertionType) bool {
-	return powerd.IsActive(powerd.AssertionType(assertionType))
+func (c *Client) IsAssertionActive(assertionType AssertionType) bool {
+	_, active := c.backend.ActiveAssertion(assertionType)
+	return active
 }
 
 // GetAssertionID returns the active assertion ID for the given type, if any.
 // The boolean return indicates whether an assertion is active.
-func GetAssertionID(assertionType AssertionType) (AssertionID, bool) {
-	id, ok := powerd.GetAssertionID(powerd.AssertionType(assertionType))
-	return AssertionID(id), ok
+func (c *Client) GetAssertionID(assertionType AssertionType) (AssertionID, bool) {
+	return c.backend.ActiveAssertion(assertionType)
 }
@@ -0,0 +1,181 @@
+package powerkit
+
+import (
+	"encoding/hex"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/peterneutron/powerkit-go/internal/audit"
+)
+
+// Audit journal defaults used when NewAuditJournal is given zero limits.
+const (
+	DefaultAuditMaxBytes   = 10 << 20
+	DefaultAuditMaxBackups = 5
+)
+
+// Audit results.
+const (
+	AuditResultOK     = "ok"
+	AuditResultError  = "error"
+	AuditResultDenied = "denied"
+)
+
+// AuditRecord describes one mutating call. For SMC writes Key is the SMC key
+// and Previous/New are lowercase hex; for power settings Key is the pmset
+// setting and the values are pmset values; for assertions Key is the
+// assertion type.
+type AuditRecord struct {
+	Time                   time.Time `json:"time"`
+	Actor                  string    `json:"actor,omitempty"`
+	Operation              string    `json:"operation"`
+	Source                 string    `json:"source,omitempty"`
+	Key                    string    `json:"key,omitempty"`
+	Previous               string    `json:"previous,omitempty"`
+	New                    string    `json:"new,omitempty"`
+	FirmwareProfileID      string    `json:"firmware_profile_id"`
+	FirmwareProfileVersion int       `json:"firmware_profile_version"`
+	Result                 string    `json:"result"`
+	Error                  string    `json:"error,omitempty"`
+}
+
+// AuditSink receives an AuditRecord after every mutating call.
+type AuditSink interface {
+	RecordAudit(AuditRecord) error
+}
+
+// AuditJournal is an AuditSink writing append-only JSON Lines to a file
+// that rotates by size.
+type AuditJournal struct {
+	journal *audit.Journal
+}
+
+// NewAuditJournal opens or creates the journal at path with owner-only
+// permissions. The file rotates to path.1, path.2, ... once it would exceed
+// maxBytes, keeping maxBackups old files. Zero limits use
+// DefaultAuditMaxBytes and DefaultAuditMaxBackups.
+func NewAuditJournal(path string, maxBytes int64, maxBackups int) (*AuditJournal, error) {
+	if maxBytes <= 0 {
+		maxBytes = DefaultAuditMaxBytes
+	}
+	if maxBackups <= 0 {
+		maxBackups = DefaultAuditMaxBackups
+	}
+	j, err := audit.Open(path, maxBytes, maxBackups)
+	if err != nil {
+		return nil, err
+	}
+	return &AuditJournal{journal: j}, nil
+}
+
+// RecordAudit implements AuditSink.
+func (a *AuditJournal) RecordAudit(r AuditRecord) error {
+	return a.journal.Append(r)
+}
+
+// Close closes the journal file.
+func (a *AuditJournal) Close() error {
+	return a.journal.Close()
+}
+
+// WithAuditSink records every mutating call (SMC writes, Low Power Mode and
+// pmset changes, MagSafe LED changes, assertion create/release) to sink.
+func WithAuditSink(sink AuditSink) Option {
+	return func(c *Client) {
+		c.auditSink = sink
+	}
+}
+
+// WithAuditActor sets the caller-supplied actor stored in audit records,
+// for example the user or service on whose behalf the change is made.
+func WithAuditActor(actor string) Option {
+	return func(c *Client) {
+		c.auditActor = actor
+	}
+}
+
+// auditing reports whether the client has an audit sink.
+func (c *Client) auditing() bool {
+	return c.auditSink != nil
+}
+
+// recordAudit completes r with the time, actor, profile and result of err
+// and hands it to the sink. A failing sink is logged, not returned: the
+// audited change has already happened.
+func (c *Client) recordAudit(r AuditRecord, err error) {
+	if c.auditSink == nil {
+		return
+	}
+	_, cfg := c.profile()
+	r.Time = time.Now().UTC()
+	r.Actor = c.auditActor
+	r.FirmwareProfileID = cfg.FirmwareProfileID
+	r.FirmwareProfileVersion = cfg.FirmwareProfileVersion
+	r.Result = AuditResultOK
+	if err != nil {
+		r.Result = AuditResultError
+		if errors.Is(err, ErrWriteDenied) || errors.Is(err, ErrPermissionRequired) {
+			r.Result = AuditResultDenied
+		}
+		r.Error = err.Error()
+	}
+	if sinkErr := c.auditSink.RecordAudit(r); sinkErr != nil {
+		c.logger.Printf("Warning: failed to record audit entry for %s: %v", r.Operation, sinkErr)
+	}
+}
+
+// auditedWriteData writes an SMC key, recording the previous and new bytes
+// when auditing.
+func (c *Client) auditedWriteData(op, key string, data []byte) error {
+	if !c.auditing() {
+		return c.backend.WriteData(key, data)
+	}
+	var previous string
+	if values, err := c.backend.FetchRawData([]string{key}); err == nil {
+		if v, ok := values[key]; ok {
+			previous = hex.EncodeToString(v.Data)
+		}
+	}
+	err := c.backend.WriteData(key, data)
+	c.recordAudit(AuditRecord{Operation: op, Key: key, Previous: previous, New: hex.EncodeToString(data)}, err)
+	return err
+}
+
+// auditedSetPowerSetting applies a pmset setting, recording the previous
+// value per power source when auditing.
+func (c *Client) auditedSetPowerSetting(op string, source PowerSource, key, value string, set func() error) error {
+	if !c.auditing() {
+		return set()
+	}
+	previous := c.powerSettingSnapshot(source, key)
+	err := set()
+	c.recordAudit(AuditRecord{Operation: op, Source: string(source), Key: key, Previous: previous, New: value}, err)
+	return err
+}
+
+// powerSettingSnapshot formats the current value of key for source, or
+// "battery=1,ac=0"-style for every source when source is all.
+func (c *Client) powerSettingSnapshot(source PowerSource, key string) string {
+	settings, err := c.backend.GetPowerSettings()
+	if err != nil || settings == nil {
+		return ""
+	}
+	bySource := map[PowerSource]*PowerSettings{
+		PowerSourceBattery: settings.Battery,
+		PowerSourceAC:      settings.AC,
+		PowerSourceUPS:     settings.UPS,
+	}
+	if p := bySource[source]; p != nil {
+		return p.Values[key]
+	}
+	var parts []string
+	for s, p := range bySource {
+		if p != nil && p.Has(key) {
+			parts = append(parts, string(s)+"="+p.Values[key])
+		}
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ",")
+}
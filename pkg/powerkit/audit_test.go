@@ -0,0 +1,143 @@
+package powerkit
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type recordingSink struct {
+	records []AuditRecord
+}
+
+func (s *recordingSink) RecordAudit(r AuditRecord) error {
+	s.records = append(s.records, r)
+	return nil
+}
+
+func TestAuditRecordsSMCWrites(t *testing.T) {
+	m := &memorySMC{values: map[string][]byte{"CHTE": {0x00, 0x00, 0x00, 0x00}}}
+	sink := &recordingSink{}
+	client := New(
+		WithBackend(m.backend(FirmwareInfo{Major: FirmwareMajorVersionThreshold})),
+		WithRootCheck(false),
+		WithAuditSink(sink),
+		WithAuditActor("it-ops"),
+	)
+
+	if err := client.SetChargingState(ChargingActionOff); err != nil {
+		t.Fatalf("SetChargingState returned error: %v", err)
+	}
+	if err := client.SetAdapterState(AdapterActionOff); err == nil {
+		t.Fatalf("expected error writing a missing adapter key")
+	}
+
+	if len(sink.records) != 2 {
+		t.Fatalf("expected 2 audit records, got %+v", sink.records)
+	}
+	r := sink.records[0]
+	if r.Operation != opSetChargingState || r.Key != "CHTE" || r.Previous != "00000000" || r.New != "01000000" {
+		t.Fatalf("unexpected charging record: %+v", r)
+	}
+	if r.Actor != "it-ops" || r.FirmwareProfileID != profileModernID || r.Result != AuditResultOK || r.Time.IsZero() {
+		t.Fatalf("unexpected record metadata: %+v", r)
+	}
+	if r := sink.records[1]; r.Key != "CHIE" || r.Previous != "" || r.Result != AuditResultError || r.Error == "" {
+		t.Fatalf("expected a failed adapter record without previous bytes, got %+v", r)
+	}
+}
+
+func TestAuditRecordsRootDenials(t *testing.T) {
+	geteuid = func() int { return 501 }
+	defer func() { geteuid = os.Geteuid }()
+	m := &memorySMC{values: map[string][]byte{"CHTE": {0x00, 0x00, 0x00, 0x00}}}
+	sink := &recordingSink{}
+	client := New(WithBackend(m.backend(FirmwareInfo{Major: FirmwareMajorVersionThreshold})), WithAuditSink(sink))
+
+	if err := client.SetChargingState(ChargingActionOff); !errors.Is(err, ErrPermissionRequired) {
+		t.Fatalf("expected ErrPermissionRequired, got %v", err)
+	}
+	if len(m.writes) != 0 {
+		t.Fatalf("expected no SMC writes, got %v", m.writes)
+	}
+	if len(sink.records) != 1 {
+		t.Fatalf("expected one audit record, got %+v", sink.records)
+	}
+	if r := sink.records[0]; r.Operation != opSetChargingState || r.Result != AuditResultDenied || r.Error == "" {
+		t.Fatalf("unexpected denial record: %+v", r)
+	}
+}
+
+func TestAuditRecordsSettingsAndAssertions(t *testing.T) {
+	sink := &recordingSink{}
+	stub := &stubBackend{
+		powerSettings: func() (*PowerSettingsBySource, error) {
+			return &PowerSettingsBySource{
+				Battery: &PowerSettings{Values: map[string]string{"lowpowermode": "1"}},
+				AC:      &PowerSettings{Values: map[string]string{"lowpowermode": "0"}},
+			}, nil
+		},
+		setLowPowerMode: func(bool) error { return nil },
+		setPowerSetting: func(PowerSource, string, string) error { return nil },
+	}
+	client := New(WithBackend(stub), WithRootCheck(false), WithAuditSink(sink))
+
+	if err := client.SetLowPowerMode(true); err != nil {
+		t.Fatalf("SetLowPowerMode returned error: %v", err)
+	}
+	if err := client.SetLowPowerModeFor(PowerSourceAC, true); err != nil {
+		t.Fatalf("SetLowPowerModeFor returned error: %v", err)
+	}
+	if _, err := client.CreateAssertion(AssertionTypePreventSystemSleep, "backup"); !errors.Is(err, ErrNotSupported) {
+		t.Fatalf("expected stub assertion error, got %v", err)
+	}
+
+	want := []AuditRecord{
+		{Operation: "set low power mode", Source: "all", Key: "lowpowermode", Previous: "ac=0,battery=1", New: "1", Result: AuditResultOK},
+		{Operation: "set low power mode", Source: "ac", Key: "lowpowermode", Previous: "0", New: "1", Result: AuditResultOK},
+		{Operation: "create assertion", Key: "prevent_system_sleep", New: "backup", Result: AuditResultError},
+	}
+	if len(sink.records) != len(want) {
+		t.Fatalf("expected %d records, got %+v", len(want), sink.records)
+	}
+	for i, w := range want {
+		got := sink.records[i]
+		if got.Operation != w.Operation || got.Source != w.Source || got.Key != w.Key ||
+			got.Previous != w.Previous || got.New != w.New || got.Result != w.Result {
+			t.Fatalf("record %d: expected %+v, got %+v", i, w, got)
+		}
+	}
+}
+
+func TestAuditJournalWritesJSONLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "powerkit-audit.jsonl")
+	journal, err := NewAuditJournal(path, 0, 0)
+	if err != nil {
+		t.Fatalf("NewAuditJournal returned error: %v", err)
+	}
+	client := New(WithBackend(&stubBackend{}), WithAuditSink(journal), WithAuditActor("tester"))
+	client.ReleaseAssertion(AssertionTypePreventDisplaySleep)
+	client.AllowAllSleep()
+	if err := journal.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read journal: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 journal lines, got %q", data)
+	}
+	var r map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &r); err != nil {
+		t.Fatalf("journal line is not JSON: %v", err)
+	}
+	if r["operation"] != "release assertion" || r["actor"] != "tester" || r["key"] != "prevent_display_sleep" || r["result"] != "ok" {
+		t.Fatalf("unexpected journal record: %v", r)
+	}
+}
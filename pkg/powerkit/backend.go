@@ -0,0 +1,312 @@
+package powerkit
+
+import "fmt"
+
+// --- Backend Interfaces ---
+
+// BatteryReader reads battery and adapter state. On macOS this is backed by
+// the AppleSmartBattery IOKit service.
+type BatteryReader interface {
+	// FetchBatteryData returns the unprocessed battery and adapter readings.
+	// When forceTelemetryFallback is true, adapter input telemetry should be
+	// taken from the fallback source even if the primary source reports it.
+	FetchBatteryData(forceTelemetryFallback bool) (*RawBatteryData, error)
+}
+
+// SMCReader reads keys from a System Management Controller.
+type SMCReader interface {
+	// FetchData returns the requested keys decoded to float values.
+	// Keys that are missing or cannot be decoded are omitted.
+	FetchData(keys []string) (map[string]float64, error)
+	// FetchRawData returns the requested keys as raw, undecoded values.
+	// Keys that are missing are omitted.
+	FetchRawData(keys []string) (map[string]RawSMCValue, error)
+}
+
+// SMCWriter writes raw bytes to System Management Controller keys.
+type SMCWriter interface {
+	WriteData(key string, data []byte) error
+}
+
+// SMCDevice combines read and write access to an SMC.
+type SMCDevice interface {
+	SMCReader
+	SMCWriter
+}
+
+// SMCCatalog enumerates the keys an SMC exposes and their metadata.
+type SMCCatalog interface {
+	// ListSMCKeys returns every key with its type, size and attributes, in
+	// the SMC's index order.
+	ListSMCKeys() ([]SMCKeyInfo, error)
+	// FetchKeyInfo returns the type, size and attributes of the given keys.
+	// Missing keys are omitted.
+	FetchKeyInfo(keys []string) (map[string]SMCKeyInfo, error)
+}
+
+// AssertionManager creates and inspects sleep assertions.
+type AssertionManager interface {
+	CreateAssertion(assertionType AssertionType, reason string) (AssertionID, error)
+	ReleaseAssertion(assertionType AssertionType)
+	ReleaseAllAssertions()
+	// ActiveAssertion returns the ID of the assertion of the given type held
+	// by this process, if any.
+	ActiveAssertion(assertionType AssertionType) (AssertionID, bool)
+	// GlobalSleepStatus reports whether system and display sleep are allowed
+	// system-wide, taking assertions from every process into account.
+	GlobalSleepStatus() (systemAllowed bool, displayAllowed bool, err error)
+}
+
+// LowPowerModeController reads and changes the Low Power Mode setting.
+type LowPowerModeController interface {
+	GetLowPowerModeEnabled() (enabled bool, available bool, err error)
+	SetLowPowerMode(enable bool) error
+}
+
+// FirmwareReader reports the system firmware used to resolve the SMC
+// control profile.
+type FirmwareReader interface {
+	FirmwareInfo() FirmwareInfo
+}
+
+// EventSource delivers power and sleep notifications to the event stream.
+type EventSource interface {
+	// SubscribeEvents starts delivering notifications on the returned channel
+	// until it is closed. beforeSleep, when non-nil, must run synchronously
+	// before sleep is acknowledged. Sources should queue an initial
+	// EventTypeBatteryUpdate so subscribers observe the current state.
+	SubscribeEvents(beforeSleep func()) (<-chan EventType, error)
+}
+
+// Backend supplies every hardware and OS facility the library consumes.
+// The default backend talks to IOKit, the SMC, IOPMLib and pmset on macOS;
+// other implementations let the coordinator, calculations and JSON
+// projection run on any GOOS.
+type Backend interface {
+	BatteryReader
+	SMCDevice
+	SMCCatalog
+	AssertionManager
+	LowPowerModeController
+	PowerSettingsController
+	FirmwareReader
+	EventSource
+}
+
+// FirmwareInfo describes detected firmware metadata used for resolver gating.
+type FirmwareInfo struct {
+	// Major is the parsed major firmware version, or 0 when unknown.
+	Major int `json:"major"`
+	// Version is the normalized firmware version string.
+	Version string `json:"version"`
+	// Source identifies where the version was read from.
+	// Values: ioreg_device_tree | system_profiler | unknown
+	Source string `json:"source"`
+}
+
+// RawBatteryData holds unprocessed battery and adapter readings in the
+// units reported by IOKit: capacities in mAh, voltages in mV, currents in mA,
+// temperature in hundredths of a degree Celsius and times in minutes.
+type RawBatteryData struct {
+	CurrentCharge      int `json:"current_charge"`
+	CurrentChargeRaw   int `json:"current_charge_raw"`
+	CurrentCapacityRaw int `json:"current_capacity_raw"`
+
+	IsCharging      bool   `json:"is_charging"`
+	IsConnected     bool   `json:"is_connected"`
+	IsFullyCharged  bool   `json:"is_fully_charged"`
+	CycleCount      int    `json:"cycle_count"`
+	DesignCapacity  int    `json:"design_capacity"`
+	MaxCapacity     int    `json:"max_capacity"`
+	NominalCapacity int    `json:"nominal_capacity"`
+	TimeToEmpty     int    `json:"time_to_empty"`
+	TimeToFull      int    `json:"time_to_full"`
+	Temperature     int    `json:"temperature"`
+	Voltage         int    `json:"voltage"`
+	Amperage        int    `json:"amperage"`
+	SerialNumber    string `json:"serial_number"`
+	DeviceName      string `json:"device_name"`
+	CellVoltages    []int  `json:"cell_voltages"`
+
+	AdapterWatts    int    `json:"adapter_watts"`
+	AdapterVoltage  int    `json:"adapter_voltage"`
+	AdapterAmperage int    `json:"adapter_amperage"`
+	AdapterDesc     string `json:"adapter_desc"`
+	SourceVoltage   int    `json:"source_voltage"`
+	SourceAmperage  int    `json:"source_amperage"`
+
+	// HasPowerTelemetry reports whether the primary source exposed adapter
+	// input telemetry at all (IOKit PowerTelemetryData).
+	HasPowerTelemetry bool `json:"has_power_telemetry"`
+
+	// Adapter telemetry provenance. Source is one of iokit | smc_fallback |
+	// sysfs | unavailable and Reason one of none | no_adapter | missing_iokit |
+	// invalid_iokit | missing_sysfs | forced | smc_error.
+	TelemetryAvailable bool   `json:"telemetry_available"`
+	TelemetrySource    string `json:"telemetry_source,omitempty"`
+	TelemetryReason    string `json:"telemetry_reason,omitempty"`
+	ForceFallback      bool   `json:"force_fallback"`
+}
+
+// --- Composite Backend ---
+
+// CompositeBackend assembles a Backend from individual components, so a
+// caller only has to provide the parts their platform supports. Operations
+// on a nil component fail with ErrNotSupported.
+type CompositeBackend struct {
+	Battery       BatteryReader
+	SMC           SMCDevice
+	Assertions    AssertionManager
+	LowPowerMode  LowPowerModeController
+	PowerSettings PowerSettingsController
+	Firmware      FirmwareReader
+	Events        EventSource
+}
+
+var _ Backend = (*CompositeBackend)(nil)
+
+// FetchBatteryData implements BatteryReader.
+func (c *CompositeBackend) FetchBatteryData(forceTelemetryFallback bool) (*RawBatteryData, error) {
+	if c.Battery == nil {
+		return nil, fmt.Errorf("%w: no battery reader configured", ErrNotSupported)
+	}
+	return c.Battery.FetchBatteryData(forceTelemetryFallback)
+}
+
+// fetchBatteryReading forwards to the battery reader when it can return
+// unevaluated readings, so snapshots of a composite keep them.
+func (c *CompositeBackend) fetchBatteryReading() (*RawBatteryData, error) {
+	if f, ok := c.Battery.(batteryReadingFetcher); ok {
+		return f.fetchBatteryReading()
+	}
+	return c.FetchBatteryData(false)
+}
+
+// FetchData implements SMCReader.
+func (c *CompositeBackend) FetchData(keys []string) (map[string]float64, error) {
+	if c.SMC == nil {
+		return nil, fmt.Errorf("%w: no SMC configured", ErrNotSupported)
+	}
+	return c.SMC.FetchData(keys)
+}
+
+// FetchRawData implements SMCReader.
+func (c *CompositeBackend) FetchRawData(keys []string) (map[string]RawSMCValue, error) {
+	if c.SMC == nil {
+		return nil, fmt.Errorf("%w: no SMC configured", ErrNotSupported)
+	}
+	return c.SMC.FetchRawData(keys)
+}
+
+// WriteData implements SMCWriter.
+func (c *CompositeBackend) WriteData(key string, data []byte) error {
+	if c.SMC == nil {
+		return fmt.Errorf("%w: no SMC configured", ErrNotSupported)
+	}
+	return c.SMC.WriteData(key, data)
+}
+
+// ListSMCKeys implements SMCCatalog when the SMC component does.
+func (c *CompositeBackend) ListSMCKeys() ([]SMCKeyInfo, error) {
+	catalog, ok := c.SMC.(SMCCatalog)
+	if !ok {
+		return nil, fmt.Errorf("%w: SMC does not support key enumeration", ErrNotSupported)
+	}
+	return catalog.ListSMCKeys()
+}
+
+// FetchKeyInfo implements SMCCatalog when the SMC component does.
+func (c *CompositeBackend) FetchKeyInfo(keys []string) (map[string]SMCKeyInfo, error) {
+	catalog, ok := c.SMC.(SMCCatalog)
+	if !ok {
+		return nil, fmt.Errorf("%w: SMC does not report key info", ErrNotSupported)
+	}
+	return catalog.FetchKeyInfo(keys)
+}
+
+// CreateAssertion implements AssertionManager.
+func (c *CompositeBackend) CreateAssertion(assertionType AssertionType, reason string) (AssertionID, error) {
+	if c.Assertions == nil {
+		return 0, fmt.Errorf("%w: no assertion manager configured", ErrNotSupported)
+	}
+	return c.Assertions.CreateAssertion(assertionType, reason)
+}
+
+// ReleaseAssertion implements AssertionManager.
+func (c *CompositeBackend) ReleaseAssertion(assertionType AssertionType) {
+	if c.Assertions != nil {
+		c.Assertions.ReleaseAssertion(assertionType)
+	}
+}
+
+// ReleaseAllAssertions implements AssertionManager.
+func (c *CompositeBackend) ReleaseAllAssertions() {
+	if c.Assertions != nil {
+		c.Assertions.ReleaseAllAssertions()
+	}
+}
+
+// ActiveAssertion implements AssertionManager.
+func (c *CompositeBackend) ActiveAssertion(assertionType AssertionType) (AssertionID, bool) {
+	if c.Assertions == nil {
+		return 0, false
+	}
+	return c.Assertions.ActiveAssertion(assertionType)
+}
+
+// GlobalSleepStatus implements AssertionManager.
+func (c *CompositeBackend) GlobalSleepStatus() (systemAllowed bool, displayAllowed bool, err error) {
+	if c.Assertions == nil {
+		return true, true, fmt.Errorf("%w: no assertion manager configured", ErrNotSupported)
+	}
+	return c.Assertions.GlobalSleepStatus()
+}
+
+// GetLowPowerModeEnabled implements LowPowerModeController.
+func (c *CompositeBackend) GetLowPowerModeEnabled() (enabled bool, available bool, err error) {
+	if c.LowPowerMode == nil {
+		return false, false, nil
+	}
+	return c.LowPowerMode.GetLowPowerModeEnabled()
+}
+
+// SetLowPowerMode implements LowPowerModeController.
+func (c *CompositeBackend) SetLowPowerMode(enable bool) error {
+	if c.LowPowerMode == nil {
+		return fmt.Errorf("%w: no low power mode controller configured", ErrNotSupported)
+	}
+	return c.LowPowerMode.SetLowPowerMode(enable)
+}
+
+// GetPowerSettings implements PowerSettingsController.
+func (c *CompositeBackend) GetPowerSettings() (*PowerSettingsBySource, error) {
+	if c.PowerSettings == nil {
+		return nil, fmt.Errorf("%w: no power settings controller configured", ErrNotSupported)
+	}
+	return c.PowerSettings.GetPowerSettings()
+}
+
+// SetPowerSetting implements PowerSettingsController.
+func (c *CompositeBackend) SetPowerSetting(source PowerSource, key, value string) error {
+	if c.PowerSettings == nil {
+		return fmt.Errorf("%w: no power settings controller configured", ErrNotSupported)
+	}
+	return c.PowerSettings.SetPowerSetting(source, key, value)
+}
+
+// FirmwareInfo implements FirmwareReader. Without a firmware reader the
+// firmware is reported as unknown.
+func (c *CompositeBackend) FirmwareInfo() FirmwareInfo {
+	if c.Firmware == nil {
+		return FirmwareInfo{Source: firmwareSourceUnknown}
+	}
+	return c.Firmware.FirmwareInfo()
+}
+
+// SubscribeEvents implements EventSource.
+func (c *CompositeBackend) SubscribeEvents(beforeSleep func()) (<-chan EventType, error) {
+	if c.Events == nil {
+		return nil, fmt.Errorf("%w: no event source configured", ErrNotSupported)
+	}
+	return c.Events.SubscribeEvents(beforeSleep)
+}
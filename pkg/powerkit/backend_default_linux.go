@@ -0,0 +1,8 @@
+//go:build linux
+
+package powerkit
+
+// defaultBackend reads the battery from sysfs on Linux.
+func defaultBackend() Backend {
+	return NewSysfsBackend("")
+}
@@ -0,0 +1,8 @@
+//go:build !linux
+
+package powerkit
+
+// defaultBackend returns the system backend.
+func defaultBackend() Backend {
+	return systemBackend{}
+}
@@ -0,0 +1,59 @@
+package powerkit
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/peterneutron/powerkit-go/internal/iokit"
+)
+
+// IORegBatteryReader is a BatteryReader over a saved
+// `ioreg -rw0 -c AppleSmartBattery -a` dump. It reports the same fields as
+// the live IOKit reader, so dumps collected from other machines can be run
+// through GetSystemInfo on any platform.
+type IORegBatteryReader struct {
+	reading *iokit.RawData
+
+	// SMC, when set, answers the adapter telemetry fallback. Without it a
+	// fallback is reported as smc_error.
+	SMC SMCReader
+}
+
+var (
+	_ BatteryReader         = (*IORegBatteryReader)(nil)
+	_ batteryReadingFetcher = (*IORegBatteryReader)(nil)
+)
+
+// NewIORegBatteryReader parses an ioreg XML plist dump of AppleSmartBattery.
+func NewIORegBatteryReader(r io.Reader) (*IORegBatteryReader, error) {
+	reading, err := iokit.ParseBatteryPlist(r)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse ioreg dump: %w", err)
+	}
+	return &IORegBatteryReader{reading: reading}, nil
+}
+
+// FetchBatteryData implements BatteryReader. Adapter telemetry is evaluated
+// exactly as for a live IOKit reading.
+func (r *IORegBatteryReader) FetchBatteryData(forceTelemetryFallback bool) (*RawBatteryData, error) {
+	raw := *r.reading
+	raw.CellVoltages = append([]int(nil), r.reading.CellVoltages...)
+	raw.ForceFallback = forceTelemetryFallback
+	iokit.EvaluateAdapterTelemetry(&raw, forceTelemetryFallback, r.fetchFallback)
+	return newRawBatteryData(&raw), nil
+}
+
+func (r *IORegBatteryReader) fetchBatteryReading() (*RawBatteryData, error) {
+	raw := newRawBatteryData(r.reading)
+	raw.CellVoltages = append([]int(nil), raw.CellVoltages...)
+	raw.TelemetrySource = ""
+	raw.TelemetryReason = ""
+	return raw, nil
+}
+
+func (r *IORegBatteryReader) fetchFallback(keys []string) (map[string]float64, error) {
+	if r.SMC == nil {
+		return nil, fmt.Errorf("%w: ioreg dump has no SMC", ErrNotSupported)
+	}
+	return r.SMC.FetchData(keys)
+}
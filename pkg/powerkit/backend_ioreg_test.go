@@ -0,0 +1,104 @@
+package powerkit
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/peterneutron/powerkit-go/internal/smc"
+)
+
+const ioregDump = `<?xml version="1.0" encoding="UTF-8"?>
+<plist version="1.0">
+<array>
+	<dict>
+		<key>AdapterDetails</key>
+		<dict>
+			<key>Description</key>
+			<string>pd charger</string>
+			<key>Watts</key>
+			<integer>70</integer>
+		</dict>
+		<key>Amperage</key>
+		<integer>2000</integer>
+		<key>AppleRawMaxCapacity</key>
+		<integer>4500</integer>
+		<key>BatteryData</key>
+		<dict>
+			<key>CellVoltage</key>
+			<array>
+				<integer>4000</integer>
+				<integer>4004</integer>
+			</array>
+		</dict>
+		<key>DesignCapacity</key>
+		<integer>5000</integer>
+		<key>ExternalConnected</key>
+		<true/>
+		<key>IsCharging</key>
+		<true/>
+		<key>NominalChargeCapacity</key>
+		<integer>4600</integer>
+		<key>PowerTelemetryData</key>
+		<dict>
+			<key>SystemCurrentIn</key>
+			<integer>%CURRENT%</integer>
+			<key>SystemVoltageIn</key>
+			<integer>20000</integer>
+		</dict>
+		<key>Voltage</key>
+		<integer>12000</integer>
+	</dict>
+</array>
+</plist>`
+
+func newTestIORegReader(t *testing.T, systemCurrentIn string) *IORegBatteryReader {
+	t.Helper()
+	reader, err := NewIORegBatteryReader(strings.NewReader(strings.Replace(ioregDump, "%CURRENT%", systemCurrentIn, 1)))
+	if err != nil {
+		t.Fatalf("NewIORegBatteryReader returned error: %v", err)
+	}
+	return reader
+}
+
+func TestIORegBatteryReaderGetSystemInfo(t *testing.T) {
+	client := New(WithBackend(&CompositeBackend{Battery: newTestIORegReader(t, "3000")}))
+
+	info, err := client.GetSystemInfo(FetchOptions{QueryIOKit: true})
+	if err != nil {
+		t.Fatalf("GetSystemInfo returned error: %v", err)
+	}
+	calc := info.IOKit.Calculations
+	if calc.HealthByMaxCapacity != 90 || calc.HealthByNominalCapacity != 92 || calc.ConditionAdjustedHealth != 95 {
+		t.Fatalf("unexpected health metrics: %+v", calc)
+	}
+	if calc.AdapterPower != 60 || calc.SystemPower != 36 {
+		t.Fatalf("unexpected power metrics: %+v", calc)
+	}
+	if src := info.ToJSON().Sources.AdapterTelemetry; src.Source != "iokit" || src.Reason != "none" {
+		t.Fatalf("expected iokit telemetry, got %+v", src)
+	}
+}
+
+func TestIORegBatteryReaderFallsBackToSMC(t *testing.T) {
+	reader := newTestIORegReader(t, "0")
+	reader.SMC = &stubBackend{fetchSMC: func([]string) (map[string]float64, error) {
+		return map[string]float64{smc.KeyAdapterVoltage: 20, smc.KeyAdapterCurrent: 2.5}, nil
+	}}
+
+	raw, err := reader.FetchBatteryData(false)
+	if err != nil {
+		t.Fatalf("FetchBatteryData returned error: %v", err)
+	}
+	if raw.TelemetrySource != "smc_fallback" || raw.TelemetryReason != "invalid_iokit" || raw.SourceAmperage != 2500 {
+		t.Fatalf("expected SMC fallback for zero SystemCurrentIn, got %+v", raw)
+	}
+
+	reader.SMC = nil
+	raw, err = reader.FetchBatteryData(false)
+	if err != nil {
+		t.Fatalf("FetchBatteryData returned error: %v", err)
+	}
+	if raw.TelemetryAvailable || raw.TelemetryReason != "smc_error" {
+		t.Fatalf("expected smc_error without an SMC, got %+v", raw)
+	}
+}
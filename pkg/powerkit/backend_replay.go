@@ -0,0 +1,306 @@
+package powerkit
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/peterneutron/powerkit-go/internal/iokit"
+	"github.com/peterneutron/powerkit-go/internal/smc"
+)
+
+// ReplayBackend is a read-only Backend that serves recorded snapshots as if
+// they came from hardware. Reads are answered from the current frame. The
+// event stream emits one EventTypeBatteryUpdate per frame, advancing to the
+// next frame once the previous update has been read, and closes after the
+// last frame; reads then keep returning the last frame.
+type ReplayBackend struct {
+	frames []*Snapshot
+
+	mu        sync.Mutex
+	index     int
+	streaming bool
+	consumed  chan struct{}
+}
+
+var (
+	_ Backend               = (*ReplayBackend)(nil)
+	_ batteryReadingFetcher = (*ReplayBackend)(nil)
+)
+
+// NewReplayBackend returns a backend replaying frames in order. It panics if
+// no frames are given.
+func NewReplayBackend(frames ...*Snapshot) *ReplayBackend {
+	if len(frames) == 0 {
+		panic("powerkit: NewReplayBackend requires at least one snapshot")
+	}
+	return &ReplayBackend{frames: frames}
+}
+
+func (r *ReplayBackend) current() *Snapshot {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.frames[r.index]
+}
+
+// FetchBatteryData implements BatteryReader. Readings recorded before
+// evaluation, or any reading when forceTelemetryFallback is set, go through
+// the same adapter telemetry evaluation as live IOKit data, with the SMC
+// fallback answered from the frame.
+func (r *ReplayBackend) FetchBatteryData(forceTelemetryFallback bool) (*RawBatteryData, error) {
+	defer r.markConsumed()
+
+	frame := r.current()
+	if frame.Battery == nil {
+		return nil, r.missing(frame.BatteryError, "battery reading")
+	}
+	if frame.Battery.TelemetrySource != "" && !forceTelemetryFallback {
+		return copyRawBatteryData(frame.Battery), nil
+	}
+
+	raw := newIOKitRawData(frame.Battery)
+	raw.ForceFallback = forceTelemetryFallback
+	iokit.EvaluateAdapterTelemetry(raw, forceTelemetryFallback, func(keys []string) (map[string]float64, error) {
+		return frameFetchData(frame, keys)
+	})
+	return newRawBatteryData(raw), nil
+}
+
+func (r *ReplayBackend) fetchBatteryReading() (*RawBatteryData, error) {
+	frame := r.current()
+	if frame.Battery == nil {
+		return nil, r.missing(frame.BatteryError, "battery reading")
+	}
+	return copyRawBatteryData(frame.Battery), nil
+}
+
+// FetchData implements SMCReader.
+func (r *ReplayBackend) FetchData(keys []string) (map[string]float64, error) {
+	return frameFetchData(r.current(), keys)
+}
+
+// FetchRawData implements SMCReader. Keys missing from the frame are omitted.
+func (r *ReplayBackend) FetchRawData(keys []string) (map[string]RawSMCValue, error) {
+	return frameFetchRawData(r.current(), keys)
+}
+
+// ListSMCKeys implements SMCCatalog with the keys recorded in the frame,
+// sorted by name. Snapshots do not record attributes.
+func (r *ReplayBackend) ListSMCKeys() ([]SMCKeyInfo, error) {
+	frame := r.current()
+	if frame.SMC == nil {
+		return nil, fmt.Errorf("%w: snapshot has no SMC values", ErrNotSupported)
+	}
+	keys := make([]SMCKeyInfo, 0, len(frame.SMC))
+	for name, val := range frame.SMC {
+		keys = append(keys, SMCKeyInfo{Key: name, DataType: val.DataType, DataSize: val.DataSize})
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Key < keys[j].Key })
+	return keys, nil
+}
+
+// FetchKeyInfo implements SMCCatalog with the keys recorded in the frame.
+// Snapshots do not record attributes, so no replayed key is writable.
+func (r *ReplayBackend) FetchKeyInfo(keys []string) (map[string]SMCKeyInfo, error) {
+	values, err := frameFetchRawData(r.current(), keys)
+	if err != nil {
+		return nil, err
+	}
+	infos := make(map[string]SMCKeyInfo, len(values))
+	for name, val := range values {
+		infos[name] = SMCKeyInfo{Key: name, DataType: val.DataType, DataSize: val.DataSize}
+	}
+	return infos, nil
+}
+
+// WriteData implements SMCWriter. Replayed data is read-only.
+func (r *ReplayBackend) WriteData(key string, _ []byte) error {
+	return fmt.Errorf("%w: cannot write SMC key '%s' during snapshot replay", ErrNotSupported, key)
+}
+
+// CreateAssertion implements AssertionManager. Replayed data is read-only.
+func (r *ReplayBackend) CreateAssertion(AssertionType, string) (AssertionID, error) {
+	return 0, fmt.Errorf("%w: cannot create assertions during snapshot replay", ErrNotSupported)
+}
+
+// ReleaseAssertion implements AssertionManager as a no-op.
+func (r *ReplayBackend) ReleaseAssertion(AssertionType) {}
+
+// ReleaseAllAssertions implements AssertionManager as a no-op.
+func (r *ReplayBackend) ReleaseAllAssertions() {}
+
+// ActiveAssertion implements AssertionManager.
+func (r *ReplayBackend) ActiveAssertion(assertionType AssertionType) (AssertionID, bool) {
+	sleep := r.current().Sleep
+	var id AssertionID
+	switch assertionType {
+	case AssertionTypePreventSystemSleep:
+		id = sleep.SystemAssertionID
+	case AssertionTypePreventDisplaySleep:
+		id = sleep.DisplayAssertionID
+	}
+	return id, id != 0
+}
+
+// GlobalSleepStatus implements AssertionManager.
+func (r *ReplayBackend) GlobalSleepStatus() (systemAllowed bool, displayAllowed bool, err error) {
+	sleep := r.current().Sleep
+	return sleep.GlobalSystemAllowed, sleep.GlobalDisplayAllowed, snapshotError(sleep.GlobalError)
+}
+
+// GetLowPowerModeEnabled implements LowPowerModeController.
+func (r *ReplayBackend) GetLowPowerModeEnabled() (enabled bool, available bool, err error) {
+	lpm := r.current().LowPowerMode
+	return lpm.Enabled, lpm.Available, snapshotError(lpm.Error)
+}
+
+// SetLowPowerMode implements LowPowerModeController. Replayed data is read-only.
+func (r *ReplayBackend) SetLowPowerMode(bool) error {
+	return fmt.Errorf("%w: cannot set low power mode during snapshot replay", ErrNotSupported)
+}
+
+// GetPowerSettings implements PowerSettingsController.
+func (r *ReplayBackend) GetPowerSettings() (*PowerSettingsBySource, error) {
+	settings := r.current().PowerSettings
+	if settings == nil {
+		return nil, fmt.Errorf("%w: snapshot has no power settings", ErrNotSupported)
+	}
+	return settings, nil
+}
+
+// SetPowerSetting implements PowerSettingsController. Replayed data is read-only.
+func (r *ReplayBackend) SetPowerSetting(PowerSource, string, string) error {
+	return fmt.Errorf("%w: cannot change power settings during snapshot replay", ErrNotSupported)
+}
+
+// FirmwareInfo implements FirmwareReader using the first frame.
+func (r *ReplayBackend) FirmwareInfo() FirmwareInfo {
+	return r.frames[0].Firmware
+}
+
+// SubscribeEvents implements EventSource. Only one subscription may be
+// active at a time; the stream starts again from the first frame.
+func (r *ReplayBackend) SubscribeEvents(func()) (<-chan EventType, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.streaming {
+		return nil, errSystemEventStreamActive
+	}
+	r.streaming = true
+	r.consumed = make(chan struct{}, 1)
+
+	out := make(chan EventType)
+	go r.replayFrames(out, r.consumed)
+	return out, nil
+}
+
+func (r *ReplayBackend) replayFrames(out chan<- EventType, consumed <-chan struct{}) {
+	defer close(out)
+	defer func() {
+		r.mu.Lock()
+		r.streaming = false
+		r.consumed = nil
+		r.mu.Unlock()
+	}()
+
+	for i := range r.frames {
+		r.mu.Lock()
+		r.index = i
+		r.mu.Unlock()
+
+		out <- EventTypeBatteryUpdate
+		<-consumed
+	}
+}
+
+func (r *ReplayBackend) markConsumed() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.consumed == nil {
+		return
+	}
+	select {
+	case r.consumed <- struct{}{}:
+	default:
+	}
+}
+
+func (r *ReplayBackend) missing(recorded, what string) error {
+	if err := snapshotError(recorded); err != nil {
+		return fmt.Errorf("recorded %s failed: %w", what, err)
+	}
+	return fmt.Errorf("%w: snapshot has no %s", ErrNotSupported, what)
+}
+
+func frameFetchRawData(frame *Snapshot, keys []string) (map[string]RawSMCValue, error) {
+	if frame.SMC == nil {
+		if err := snapshotError(frame.SMCError); err != nil {
+			return nil, fmt.Errorf("recorded SMC read failed: %w", err)
+		}
+		return nil, fmt.Errorf("%w: snapshot has no SMC values", ErrNotSupported)
+	}
+
+	results := make(map[string]RawSMCValue, len(keys))
+	for _, key := range keys {
+		if val, ok := frame.SMC[key]; ok {
+			val.Data = append([]byte(nil), val.Data...)
+			results[key] = val
+		}
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("SMC raw read failed for all requested keys")
+	}
+	return results, nil
+}
+
+func frameFetchData(frame *Snapshot, keys []string) (map[string]float64, error) {
+	rawResults, err := frameFetchRawData(frame, keys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch raw SMC data: %w", err)
+	}
+	internal := make(map[string]smc.RawSMCValue, len(rawResults))
+	for key, val := range rawResults {
+		internal[key] = smc.RawSMCValue{DataType: val.DataType, DataSize: val.DataSize, Data: val.Data}
+	}
+	return smc.DecodeRawData(internal)
+}
+
+func copyRawBatteryData(b *RawBatteryData) *RawBatteryData {
+	c := *b
+	c.CellVoltages = append([]int(nil), b.CellVoltages...)
+	return &c
+}
+
+// newIOKitRawData converts a public battery reading back into the internal
+// IOKit form so it can be evaluated like a live reading.
+func newIOKitRawData(b *RawBatteryData) *iokit.RawData {
+	return &iokit.RawData{
+		CurrentCharge:      b.CurrentCharge,
+		CurrentChargeRaw:   b.CurrentChargeRaw,
+		CurrentCapacityRaw: b.CurrentCapacityRaw,
+		IsCharging:         b.IsCharging,
+		IsConnected:        b.IsConnected,
+		IsFullyCharged:     b.IsFullyCharged,
+		CycleCount:         b.CycleCount,
+		DesignCapacity:     b.DesignCapacity,
+		MaxCapacity:        b.MaxCapacity,
+		NominalCapacity:    b.NominalCapacity,
+		TimeToEmpty:        b.TimeToEmpty,
+		TimeToFull:         b.TimeToFull,
+		Temperature:        b.Temperature,
+		Voltage:            b.Voltage,
+		Amperage:           b.Amperage,
+		SerialNumber:       b.SerialNumber,
+		DeviceName:         b.DeviceName,
+		AdapterWatts:       b.AdapterWatts,
+		AdapterVoltage:     b.AdapterVoltage,
+		AdapterAmperage:    b.AdapterAmperage,
+		AdapterDesc:        b.AdapterDesc,
+		SourceVoltage:      b.SourceVoltage,
+		SourceAmperage:     b.SourceAmperage,
+		CellVoltages:       append([]int(nil), b.CellVoltages...),
+		HasPowerTelemetry:  b.HasPowerTelemetry,
+		TelemetrySource:    iokit.AdapterTelemetrySourceUnavailable,
+		TelemetryReason:    iokit.AdapterTelemetryReasonMissingIOKit,
+	}
+}
@@ -0,0 +1,162 @@
+package powerkit
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/peterneutron/powerkit-go/internal/sysfs"
+)
+
+// DefaultSysfsRoot is the power_supply directory read by SysfsBatteryReader
+// when no root is configured.
+const DefaultSysfsRoot = sysfs.DefaultRoot
+
+// Adapter telemetry provenance values reported by the sysfs backend.
+const (
+	telemetrySourceSysfs       = "sysfs"
+	telemetrySourceUnavailable = "unavailable"
+	telemetryReasonNone        = "none"
+	telemetryReasonNoAdapter   = "no_adapter"
+	telemetryReasonMissing     = "missing_sysfs"
+	telemetryReasonForced      = "forced"
+)
+
+// SysfsBatteryReader is a BatteryReader backed by the Linux power_supply
+// class. Readings are converted to the units IOKit reports so the same
+// SystemInfo calculations and JSON contract apply on Linux.
+type SysfsBatteryReader struct {
+	// Root is the power_supply directory. Empty means DefaultSysfsRoot.
+	Root string
+}
+
+var _ BatteryReader = SysfsBatteryReader{}
+
+// NewSysfsBackend returns a Backend that reads battery and adapter state
+// from root (DefaultSysfsRoot when empty). It has no SMC, assertion or Low
+// Power Mode support.
+func NewSysfsBackend(root string) *CompositeBackend {
+	return &CompositeBackend{Battery: SysfsBatteryReader{Root: root}}
+}
+
+// FetchBatteryData implements BatteryReader. There is no fallback telemetry
+// source on Linux, so forcing the fallback reports adapter input telemetry as
+// unavailable.
+func (r SysfsBatteryReader) FetchBatteryData(forceTelemetryFallback bool) (*RawBatteryData, error) {
+	reading, err := sysfs.Read(r.Root)
+	if err != nil {
+		if errors.Is(err, sysfs.ErrNoBattery) {
+			return nil, fmt.Errorf("%w: %v", ErrNotSupported, err)
+		}
+		return nil, err
+	}
+	return newSysfsBatteryData(reading, forceTelemetryFallback), nil
+}
+
+func newSysfsBatteryData(reading *sysfs.Reading, forceTelemetryFallback bool) *RawBatteryData {
+	b := reading.Battery
+	designVoltage := b.VoltageMinDesign
+	if designVoltage <= 0 {
+		designVoltage = b.VoltageNow
+	}
+
+	raw := &RawBatteryData{
+		IsCharging:         b.Status == "Charging",
+		IsFullyCharged:     b.Status == "Full",
+		CycleCount:         b.CycleCount,
+		DesignCapacity:     sysfsCapacityMAh(b.ChargeFullDesign, b.EnergyFullDesign, designVoltage),
+		MaxCapacity:        sysfsCapacityMAh(b.ChargeFull, b.EnergyFull, designVoltage),
+		CurrentCapacityRaw: sysfsCapacityMAh(b.ChargeNow, b.EnergyNow, designVoltage),
+		TimeToEmpty:        int(b.TimeToEmptyNow / 60),
+		TimeToFull:         int(b.TimeToFullNow / 60),
+		Temperature:        b.Temp * 10,
+		Voltage:            int(b.VoltageNow / 1000),
+		Amperage:           sysfsAmperageMA(b),
+		SerialNumber:       b.SerialNumber,
+		DeviceName:         b.ModelName,
+		ForceFallback:      forceTelemetryFallback,
+	}
+	raw.NominalCapacity = raw.MaxCapacity
+
+	raw.CurrentCharge = b.Capacity
+	if raw.CurrentCharge == 0 && raw.MaxCapacity > 0 {
+		raw.CurrentCharge = int(math.Round(float64(raw.CurrentCapacityRaw) / float64(raw.MaxCapacity) * 100))
+	}
+	raw.CurrentChargeRaw = raw.CurrentCharge
+
+	applySysfsAdapter(raw, reading)
+	return raw
+}
+
+// applySysfsAdapter fills adapter ratings and input telemetry from the first
+// online external supply. Without any external supply the connection state is
+// inferred from the battery status.
+func applySysfsAdapter(raw *RawBatteryData, reading *sysfs.Reading) {
+	adapter, online := reading.OnlineAdapter()
+	raw.IsConnected = online
+	if len(reading.Adapters) == 0 {
+		status := reading.Battery.Status
+		raw.IsConnected = status == "Charging" || status == "Full" || status == "Not charging"
+	}
+
+	raw.TelemetrySource = telemetrySourceUnavailable
+	switch {
+	case !raw.IsConnected:
+		raw.TelemetryReason = telemetryReasonNoAdapter
+		return
+	case !online:
+		raw.TelemetryReason = telemetryReasonMissing
+		return
+	}
+
+	raw.AdapterDesc = adapter.ModelName
+	if raw.AdapterDesc == "" {
+		raw.AdapterDesc = adapter.Name
+	}
+	raw.AdapterVoltage = int(adapter.VoltageMax / 1000)
+	raw.AdapterAmperage = int(adapter.CurrentMax / 1000)
+	raw.AdapterWatts = int(math.Round(float64(adapter.VoltageMax) * float64(adapter.CurrentMax) / 1e12))
+
+	switch {
+	case raw.ForceFallback:
+		raw.TelemetryReason = telemetryReasonForced
+	case adapter.VoltageNow <= 0 || adapter.CurrentNow <= 0:
+		raw.TelemetryReason = telemetryReasonMissing
+	default:
+		raw.SourceVoltage = int(adapter.VoltageNow / 1000)
+		raw.SourceAmperage = int(adapter.CurrentNow / 1000)
+		raw.TelemetryAvailable = true
+		raw.TelemetrySource = telemetrySourceSysfs
+		raw.TelemetryReason = telemetryReasonNone
+	}
+}
+
+// sysfsCapacityMAh converts a charge (µAh) or, failing that, an energy (µWh)
+// reading to mAh. Energy is converted using the given voltage in µV.
+func sysfsCapacityMAh(chargeMicroAh, energyMicroWh, voltageMicroV int64) int {
+	if chargeMicroAh > 0 {
+		return int(chargeMicroAh / 1000)
+	}
+	if energyMicroWh > 0 && voltageMicroV > 0 {
+		return int(math.Round(float64(energyMicroWh) * 1000 / float64(voltageMicroV)))
+	}
+	return 0
+}
+
+// sysfsAmperageMA returns the battery current in mA, negative while
+// discharging as IOKit reports it. Drivers disagree on the sign of
+// current_now, so the sign is taken from the status. Drivers that only report
+// power_now are converted using voltage_now.
+func sysfsAmperageMA(b sysfs.Battery) int {
+	current := b.CurrentNow
+	if current == 0 && b.PowerNow != 0 && b.VoltageNow > 0 {
+		current = int64(math.Round(float64(b.PowerNow) * 1e6 / float64(b.VoltageNow)))
+	}
+	if current < 0 {
+		current = -current
+	}
+	if b.Status == "Discharging" {
+		current = -current
+	}
+	return int(current / 1000)
+}
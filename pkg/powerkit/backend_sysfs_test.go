@@ -0,0 +1,120 @@
+package powerkit
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeSysfsSupply(t *testing.T, root, name string, attrs map[string]string) {
+	t.Helper()
+	dir := filepath.Join(root, name)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("mkdir %s: %v", dir, err)
+	}
+	for attr, value := range attrs {
+		if err := os.WriteFile(filepath.Join(dir, attr), []byte(value+"\n"), 0o644); err != nil {
+			t.Fatalf("write %s/%s: %v", name, attr, err)
+		}
+	}
+}
+
+func TestSysfsBackendGetSystemInfo(t *testing.T) {
+	root := t.TempDir()
+	writeSysfsSupply(t, root, "BAT0", map[string]string{
+		"type":               "Battery",
+		"status":             "Discharging",
+		"capacity":           "80",
+		"energy_now":         "40000000",
+		"energy_full":        "50000000",
+		"energy_full_design": "62500000",
+		"voltage_min_design": "12500000",
+		"voltage_now":        "12000000",
+		"current_now":        "1500000",
+		"cycle_count":        "321",
+		"temp":               "305",
+		"model_name":         "5B10W13975",
+		"serial_number":      "1234",
+	})
+	writeSysfsSupply(t, root, "ucsi-source-psy-USBC000:001", map[string]string{
+		"type":        "USB",
+		"online":      "0",
+		"voltage_max": "20000000",
+		"current_max": "3250000",
+	})
+
+	client := New(WithBackend(NewSysfsBackend(root)))
+	info, err := client.GetSystemInfo(FetchOptions{QueryIOKit: true})
+	if err != nil {
+		t.Fatalf("GetSystemInfo returned error: %v", err)
+	}
+
+	b := info.IOKit.Battery
+	if b.DesignCapacity != 5000 || b.MaxCapacity != 4000 || b.CurrentCapacityRaw != 3200 {
+		t.Fatalf("unexpected capacities: design=%d max=%d now=%d", b.DesignCapacity, b.MaxCapacity, b.CurrentCapacityRaw)
+	}
+	if b.CurrentCharge != 80 || b.CycleCount != 321 || b.Temperature != 30.5 || b.Voltage != 12 || b.Amperage != -1.5 {
+		t.Fatalf("unexpected battery values: %+v", b)
+	}
+	if info.IOKit.State.IsCharging || info.IOKit.State.IsConnected {
+		t.Fatalf("expected discharging without adapter, got %+v", info.IOKit.State)
+	}
+	if got := info.IOKit.Calculations.HealthByMaxCapacity; got != 80 {
+		t.Fatalf("expected health 80, got %d", got)
+	}
+	if got := info.IOKit.Calculations.SystemPower; got != 18 {
+		t.Fatalf("expected system power 18, got %.2f", got)
+	}
+	if j := info.ToJSON(); j.Sources.AdapterTelemetry.Reason != telemetryReasonNoAdapter {
+		t.Fatalf("expected no_adapter reason, got %+v", j.Sources.AdapterTelemetry)
+	}
+}
+
+func TestSysfsAdapterTelemetry(t *testing.T) {
+	root := t.TempDir()
+	writeSysfsSupply(t, root, "BAT0", map[string]string{
+		"type":        "Battery",
+		"status":      "Charging",
+		"capacity":    "50",
+		"charge_full": "4000000",
+		"voltage_now": "12000000",
+		"current_now": "-1000000",
+	})
+	writeSysfsSupply(t, root, "ucsi-source-psy-USBC000:001", map[string]string{
+		"type":        "USB",
+		"online":      "1",
+		"voltage_now": "20000000",
+		"current_now": "2000000",
+		"voltage_max": "20000000",
+		"current_max": "3250000",
+	})
+
+	raw, err := SysfsBatteryReader{Root: root}.FetchBatteryData(false)
+	if err != nil {
+		t.Fatalf("FetchBatteryData returned error: %v", err)
+	}
+	if !raw.IsConnected || raw.Amperage != 1000 || raw.MaxCapacity != 4000 || raw.AdapterWatts != 65 {
+		t.Fatalf("unexpected adapter reading: %+v", raw)
+	}
+	if !raw.TelemetryAvailable || raw.SourceVoltage != 20000 || raw.SourceAmperage != 2000 || raw.TelemetrySource != telemetrySourceSysfs {
+		t.Fatalf("expected sysfs input telemetry, got %+v", raw)
+	}
+
+	forced, err := SysfsBatteryReader{Root: root}.FetchBatteryData(true)
+	if err != nil {
+		t.Fatalf("FetchBatteryData returned error: %v", err)
+	}
+	if forced.TelemetryAvailable || forced.TelemetryReason != telemetryReasonForced {
+		t.Fatalf("expected forced fallback to report unavailable telemetry, got %+v", forced)
+	}
+}
+
+func TestSysfsBackendWithoutBattery(t *testing.T) {
+	root := t.TempDir()
+	writeSysfsSupply(t, root, "AC", map[string]string{"type": "Mains", "online": "1"})
+
+	if _, err := (SysfsBatteryReader{Root: root}).FetchBatteryData(false); !errors.Is(err, ErrNotSupported) {
+		t.Fatalf("expected ErrNotSupported, got %v", err)
+	}
+}
@@ -0,0 +1,234 @@
+package powerkit
+
+import (
+	"sync"
+
+	"github.com/peterneutron/powerkit-go/internal/iokit"
+	sysos "github.com/peterneutron/powerkit-go/internal/os"
+	"github.com/peterneutron/powerkit-go/internal/powerd"
+	"github.com/peterneutron/powerkit-go/internal/smc"
+)
+
+// systemBackend is the default Backend. It talks to IOKit, the SMC, IOPMLib
+// and pmset; on platforms other than macOS every hardware call fails.
+type systemBackend struct{}
+
+var _ Backend = systemBackend{}
+
+func (systemBackend) FetchBatteryData(forceTelemetryFallback bool) (*RawBatteryData, error) {
+	raw, err := iokit.FetchData(forceTelemetryFallback)
+	if err != nil {
+		return nil, err
+	}
+	return newRawBatteryData(raw), nil
+}
+
+// fetchBatteryReading returns the IOKit reading before adapter telemetry is
+// evaluated, so snapshots can replay the evaluation.
+func (systemBackend) fetchBatteryReading() (*RawBatteryData, error) {
+	raw, err := iokit.FetchReading()
+	if err != nil {
+		return nil, err
+	}
+	return newRawBatteryData(raw), nil
+}
+
+func (systemBackend) FetchData(keys []string) (map[string]float64, error) {
+	return smc.FetchData(keys)
+}
+
+func (systemBackend) FetchRawData(keys []string) (map[string]RawSMCValue, error) {
+	rawResults, err := smc.FetchRawData(keys)
+	if err != nil {
+		return nil, err
+	}
+
+	// The internal smc.RawSMCValue struct is identical to our public one,
+	// but it's a best practice to convert between them to keep the packages decoupled.
+	results := make(map[string]RawSMCValue, len(rawResults))
+	for key := range rawResults {
+		val := rawResults[key]
+		results[key] = RawSMCValue{
+			DataType: val.DataType,
+			DataSize: val.DataSize,
+			Data:     val.Data,
+		}
+	}
+	return results, nil
+}
+
+func (systemBackend) ListSMCKeys() ([]SMCKeyInfo, error) {
+	keys, err := smc.ListKeys()
+	if err != nil {
+		return nil, err
+	}
+	results := make([]SMCKeyInfo, len(keys))
+	for i, k := range keys {
+		results[i] = newSMCKeyInfo(k)
+	}
+	return results, nil
+}
+
+func (systemBackend) FetchKeyInfo(keys []string) (map[string]SMCKeyInfo, error) {
+	infos, err := smc.ReadKeyInfo(keys)
+	if err != nil {
+		return nil, err
+	}
+	results := make(map[string]SMCKeyInfo, len(infos))
+	for key, k := range infos {
+		results[key] = newSMCKeyInfo(k)
+	}
+	return results, nil
+}
+
+func newSMCKeyInfo(k smc.KeyInfo) SMCKeyInfo {
+	return SMCKeyInfo{Key: k.Key, DataType: k.DataType, DataSize: k.DataSize, Attributes: k.Attributes}
+}
+
+func (systemBackend) WriteData(key string, data []byte) error {
+	return smc.WriteData(key, data)
+}
+
+func (systemBackend) CreateAssertion(assertionType AssertionType, reason string) (AssertionID, error) {
+	id, err := powerd.PreventSleep(powerd.AssertionType(assertionType), reason)
+	return AssertionID(id), err
+}
+
+func (systemBackend) ReleaseAssertion(assertionType AssertionType) {
+	powerd.AllowSleep(powerd.AssertionType(assertionType))
+}
+
+func (systemBackend) ReleaseAllAssertions() {
+	powerd.AllowAllSleep()
+}
+
+func (systemBackend) ActiveAssertion(assertionType AssertionType) (AssertionID, bool) {
+	id, ok := powerd.GetAssertionID(powerd.AssertionType(assertionType))
+	return AssertionID(id), ok
+}
+
+func (systemBackend) GlobalSleepStatus() (systemAllowed bool, displayAllowed bool, err error) {
+	return powerd.GlobalSleepStatus()
+}
+
+func (systemBackend) GetLowPowerModeEnabled() (enabled bool, available bool, err error) {
+	return sysos.GetLowPowerModeEnabled()
+}
+
+func (systemBackend) SetLowPowerMode(enable bool) error {
+	return sysos.SetLowPowerMode(enable)
+}
+
+func (systemBackend) GetPowerSettings() (*PowerSettingsBySource, error) {
+	settings, err := sysos.GetCustomSettings()
+	if err != nil {
+		return nil, err
+	}
+	return newPowerSettingsBySource(settings), nil
+}
+
+func (systemBackend) SetPowerSetting(source PowerSource, key, value string) error {
+	return sysos.SetPowerSetting(sysos.PowerSource(source), key, value)
+}
+
+func (systemBackend) FirmwareInfo() FirmwareInfo {
+	info := sysos.GetFirmwareInfo()
+	return FirmwareInfo{
+		Major:   info.Major,
+		Version: info.Version,
+		Source:  info.Source,
+	}
+}
+
+// The IOKit notification port is process-wide, so only one subscription to
+// the system event source may be active at a time.
+var (
+	systemEventsMu              sync.Mutex
+	systemEventsActive          bool
+	startMonitorFn              = iokit.StartMonitor
+	setBeforeSleepHookFn        = iokit.SetBeforeSleepHook
+	internalEventSource         = func() <-chan iokit.InternalEvent { return iokit.Events }
+	enqueueInitialBatteryUpdate = func() {
+		select {
+		case iokit.Events <- iokit.InternalEvent{Type: iokit.BatteryUpdate}:
+		default:
+		}
+	}
+)
+
+func (systemBackend) SubscribeEvents(beforeSleep func()) (<-chan EventType, error) {
+	systemEventsMu.Lock()
+	defer systemEventsMu.Unlock()
+
+	if systemEventsActive {
+		return nil, errSystemEventStreamActive
+	}
+	systemEventsActive = true
+	setBeforeSleepHookFn(beforeSleep)
+
+	out := make(chan EventType)
+	go func(source <-chan iokit.InternalEvent) {
+		defer close(out)
+		defer releaseSystemEvents()
+
+		for internalEvent := range source {
+			switch internalEvent.Type {
+			case iokit.BatteryUpdate:
+				out <- EventTypeBatteryUpdate
+			case iokit.SystemWillSleep:
+				out <- EventTypeSystemWillSleep
+			case iokit.SystemDidWake:
+				out <- EventTypeSystemDidWake
+			default:
+				out <- EventType(internalEvent.Type)
+			}
+		}
+	}(internalEventSource())
+
+	startMonitorFn()
+	enqueueInitialBatteryUpdate()
+
+	return out, nil
+}
+
+func releaseSystemEvents() {
+	setBeforeSleepHookFn(nil)
+	systemEventsMu.Lock()
+	defer systemEventsMu.Unlock()
+	systemEventsActive = false
+}
+
+// newRawBatteryData converts the internal IOKit reading into its public form.
+func newRawBatteryData(raw *iokit.RawData) *RawBatteryData {
+	return &RawBatteryData{
+		CurrentCharge:      raw.CurrentCharge,
+		CurrentChargeRaw:   raw.CurrentChargeRaw,
+		CurrentCapacityRaw: raw.CurrentCapacityRaw,
+		IsCharging:         raw.IsCharging,
+		IsConnected:        raw.IsConnected,
+		IsFullyCharged:     raw.IsFullyCharged,
+		CycleCount:         raw.CycleCount,
+		DesignCapacity:     raw.DesignCapacity,
+		MaxCapacity:        raw.MaxCapacity,
+		NominalCapacity:    raw.NominalCapacity,
+		TimeToEmpty:        raw.TimeToEmpty,
+		TimeToFull:         raw.TimeToFull,
+		Temperature:        raw.Temperature,
+		Voltage:            raw.Voltage,
+		Amperage:           raw.Amperage,
+		SerialNumber:       raw.SerialNumber,
+		DeviceName:         raw.DeviceName,
+		CellVoltages:       raw.CellVoltages,
+		AdapterWatts:       raw.AdapterWatts,
+		AdapterVoltage:     raw.AdapterVoltage,
+		AdapterAmperage:    raw.AdapterAmperage,
+		AdapterDesc:        raw.AdapterDesc,
+		SourceVoltage:      raw.SourceVoltage,
+		SourceAmperage:     raw.SourceAmperage,
+		HasPowerTelemetry:  raw.HasPowerTelemetry,
+		TelemetryAvailable: raw.TelemetryAvailable,
+		TelemetrySource:    string(raw.TelemetrySource),
+		TelemetryReason:    string(raw.TelemetryReason),
+		ForceFallback:      raw.ForceFallback,
+	}
+}
@@ -0,0 +1,176 @@
+package powerkit
+
+import (
+	"errors"
+	"testing"
+)
+
+// stubBackend is a Backend whose behaviour is supplied per test. Unset
+// functions fail with ErrNotSupported.
+type stubBackend struct {
+	fetchBattery    func(bool) (*RawBatteryData, error)
+	fetchSMC        func([]string) (map[string]float64, error)
+	fetchSMCRaw     func([]string) (map[string]RawSMCValue, error)
+	listSMCKeys     func() ([]SMCKeyInfo, error)
+	fetchKeyInfo    func([]string) (map[string]SMCKeyInfo, error)
+	writeSMC        func(string, []byte) error
+	activeAssertion func(AssertionType) (AssertionID, bool)
+	globalSleep     func() (bool, bool, error)
+	lowPowerMode    func() (bool, bool, error)
+	setLowPowerMode func(bool) error
+	powerSettings   func() (*PowerSettingsBySource, error)
+	setPowerSetting func(PowerSource, string, string) error
+	firmware        FirmwareInfo
+	events          func(func()) (<-chan EventType, error)
+}
+
+func (s *stubBackend) FetchBatteryData(force bool) (*RawBatteryData, error) {
+	if s.fetchBattery == nil {
+		return nil, ErrNotSupported
+	}
+	return s.fetchBattery(force)
+}
+
+func (s *stubBackend) FetchData(keys []string) (map[string]float64, error) {
+	if s.fetchSMC == nil {
+		return nil, ErrNotSupported
+	}
+	return s.fetchSMC(keys)
+}
+
+func (s *stubBackend) FetchRawData(keys []string) (map[string]RawSMCValue, error) {
+	if s.fetchSMCRaw == nil {
+		return nil, ErrNotSupported
+	}
+	return s.fetchSMCRaw(keys)
+}
+
+func (s *stubBackend) ListSMCKeys() ([]SMCKeyInfo, error) {
+	if s.listSMCKeys == nil {
+		return nil, ErrNotSupported
+	}
+	return s.listSMCKeys()
+}
+
+func (s *stubBackend) FetchKeyInfo(keys []string) (map[string]SMCKeyInfo, error) {
+	if s.fetchKeyInfo == nil {
+		return nil, ErrNotSupported
+	}
+	return s.fetchKeyInfo(keys)
+}
+
+func (s *stubBackend) WriteData(key string, data []byte) error {
+	if s.writeSMC == nil {
+		return ErrNotSupported
+	}
+	return s.writeSMC(key, data)
+}
+
+func (s *stubBackend) CreateAssertion(AssertionType, string) (AssertionID, error) {
+	return 0, ErrNotSupported
+}
+
+func (s *stubBackend) ReleaseAssertion(AssertionType) {}
+
+func (s *stubBackend) ReleaseAllAssertions() {}
+
+func (s *stubBackend) ActiveAssertion(a AssertionType) (AssertionID, bool) {
+	if s.activeAssertion == nil {
+		return 0, false
+	}
+	return s.activeAssertion(a)
+}
+
+func (s *stubBackend) GlobalSleepStatus() (bool, bool, error) {
+	if s.globalSleep == nil {
+		return true, true, ErrNotSupported
+	}
+	return s.globalSleep()
+}
+
+func (s *stubBackend) GetLowPowerModeEnabled() (bool, bool, error) {
+	if s.lowPowerMode == nil {
+		return false, false, nil
+	}
+	return s.lowPowerMode()
+}
+
+func (s *stubBackend) SetLowPowerMode(enable bool) error {
+	if s.setLowPowerMode == nil {
+		return ErrNotSupported
+	}
+	return s.setLowPowerMode(enable)
+}
+
+func (s *stubBackend) GetPowerSettings() (*PowerSettingsBySource, error) {
+	if s.powerSettings == nil {
+		return nil, ErrNotSupported
+	}
+	return s.powerSettings()
+}
+
+func (s *stubBackend) SetPowerSetting(source PowerSource, key, value string) error {
+	if s.setPowerSetting == nil {
+		return ErrNotSupported
+	}
+	return s.setPowerSetting(source, key, value)
+}
+
+func (s *stubBackend) FirmwareInfo() FirmwareInfo {
+	return s.firmware
+}
+
+func (s *stubBackend) SubscribeEvents(beforeSleep func()) (<-chan EventType, error) {
+	if s.events == nil {
+		return nil, ErrNotSupported
+	}
+	return s.events(beforeSleep)
+}
+
+func TestCompositeBackendMissingComponentsNotSupported(t *testing.T) {
+	b := &CompositeBackend{}
+
+	if _, err := b.FetchBatteryData(false); !errors.Is(err, ErrNotSupported) {
+		t.Fatalf("expected ErrNotSupported from battery reader, got %v", err)
+	}
+	if _, err := b.FetchRawData([]string{"CHTE"}); !errors.Is(err, ErrNotSupported) {
+		t.Fatalf("expected ErrNotSupported from SMC reader, got %v", err)
+	}
+	if err := b.WriteData("CHTE", []byte{0x00}); !errors.Is(err, ErrNotSupported) {
+		t.Fatalf("expected ErrNotSupported from SMC writer, got %v", err)
+	}
+	if _, err := b.ListSMCKeys(); !errors.Is(err, ErrNotSupported) {
+		t.Fatalf("expected ErrNotSupported from SMC catalog, got %v", err)
+	}
+	if _, err := b.FetchKeyInfo([]string{"CHTE"}); !errors.Is(err, ErrNotSupported) {
+		t.Fatalf("expected ErrNotSupported from SMC key info, got %v", err)
+	}
+	if _, ok := b.ActiveAssertion(AssertionTypePreventSystemSleep); ok {
+		t.Fatalf("expected no active assertion without an assertion manager")
+	}
+	if _, available, err := b.GetLowPowerModeEnabled(); available || err != nil {
+		t.Fatalf("expected unavailable low power mode, got available=%v err=%v", available, err)
+	}
+}
+
+func TestGetSystemInfoUsesCompositeBatteryOnly(t *testing.T) {
+	client := New(WithBackend(&CompositeBackend{Battery: &stubBackend{
+		fetchBattery: func(bool) (*RawBatteryData, error) {
+			return &RawBatteryData{CurrentCharge: 55, DesignCapacity: 5000, MaxCapacity: 4500, NominalCapacity: 4600}, nil
+		},
+	}}))
+
+	info, err := client.GetSystemInfo()
+	if err != nil {
+		t.Fatalf("GetSystemInfo returned error: %v", err)
+	}
+	if info.SMC != nil {
+		t.Fatalf("expected SMC data to be absent without an SMC component")
+	}
+	if info.IOKit.Battery.CurrentCharge != 55 || info.IOKit.Calculations.HealthByMaxCapacity != 90 {
+		t.Fatalf("unexpected battery projection: %+v", info.IOKit)
+	}
+	if j := info.ToJSON(); !j.Sources.SMC.Queried || j.Sources.SMC.Available {
+		t.Fatalf("expected SMC queried but unavailable, got %+v", j.Sources.SMC)
+	}
+}
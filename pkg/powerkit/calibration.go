@@ -0,0 +1,334 @@
+package powerkit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// DefaultCalibrationHold is how long a calibration holds the battery at
+	// 100% before discharging it.
+	DefaultCalibrationHold = 2 * time.Hour
+	// DefaultCalibrationFloor is the charge percentage a calibration
+	// discharges to.
+	DefaultCalibrationFloor = 5
+	// CalibrationPollInterval is how often a running calibration re-reads
+	// the battery.
+	CalibrationPollInterval = 30 * time.Second
+)
+
+// CalibrationPhase is a step of the calibration routine.
+type CalibrationPhase string
+
+// Calibration phases, in order.
+const (
+	// CalibrationCharge charges the battery to 100%.
+	CalibrationCharge CalibrationPhase = "charge"
+	// CalibrationHold keeps the battery at 100% for CalibrationConfig.Hold.
+	CalibrationHold CalibrationPhase = "hold"
+	// CalibrationDischarge disables the adapter until the charge falls to
+	// CalibrationConfig.Floor.
+	CalibrationDischarge CalibrationPhase = "discharge"
+	// CalibrationRecharge charges the battery to 100% again.
+	CalibrationRecharge CalibrationPhase = "recharge"
+	// CalibrationDone marks a finished calibration.
+	CalibrationDone CalibrationPhase = "done"
+)
+
+// CalibrationConfig sets the hold time and the discharge floor. Zero values
+// mean DefaultCalibrationHold and DefaultCalibrationFloor.
+type CalibrationConfig struct {
+	Hold  time.Duration `json:"hold"`
+	Floor int           `json:"floor"`
+}
+
+// withDefaults fills zero values and validates the result.
+func (c CalibrationConfig) withDefaults() (CalibrationConfig, error) {
+	if c.Hold == 0 {
+		c.Hold = DefaultCalibrationHold
+	}
+	if c.Floor == 0 {
+		c.Floor = DefaultCalibrationFloor
+	}
+	if c.Hold < 0 || c.Floor < 1 || c.Floor > 50 {
+		return c, fmt.Errorf("invalid calibration config: need hold >= 0 and 1 <= floor (%d) <= 50", c.Floor)
+	}
+	return c, nil
+}
+
+// CalibrationControls are the SMC charging and adapter states a calibration
+// found when it started and restores when it stops.
+type CalibrationControls struct {
+	ChargingEnabled bool `json:"charging_enabled"`
+	AdapterEnabled  bool `json:"adapter_enabled"`
+}
+
+// CalibrationProgress is the persisted state of a calibration, also passed
+// to the progress callback of Calibration.Run.
+type CalibrationProgress struct {
+	Config         CalibrationConfig   `json:"config"`
+	Phase          CalibrationPhase    `json:"phase"`
+	StartedAt      time.Time           `json:"started_at"`
+	PhaseStartedAt time.Time           `json:"phase_started_at"`
+	Charge         int                 `json:"charge"`
+	Original       CalibrationControls `json:"original"`
+	// Waiting explains why the routine is not advancing, for example a
+	// disconnected adapter.
+	Waiting   string    `json:"waiting,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Calibration runs the guided calibration routine: charge to 100%, hold,
+// discharge to a floor by disabling the adapter while plugged in, then
+// recharge to 100%. Progress is persisted to a state file after every
+// change, so a calibration interrupted by a reboot resumes where it stopped
+// when Run is called again.
+type Calibration struct {
+	client *Client
+	path   string
+	config CalibrationConfig
+	now    func() time.Time
+	poll   time.Duration
+}
+
+// NewCalibration creates a calibration that controls the battery through
+// client, or the default client when client is nil, and persists its
+// progress at path. config applies when a new calibration starts; a resumed
+// one keeps the config it started with.
+func NewCalibration(client *Client, path string, config CalibrationConfig) (*Calibration, error) {
+	config, err := config.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+	if client == nil {
+		client = Default()
+	}
+	return &Calibration{client: client, path: path, config: config, now: time.Now, poll: CalibrationPollInterval}, nil
+}
+
+// LoadCalibrationProgress reads the progress persisted at path. It returns
+// nil and no error when no calibration was started.
+func LoadCalibrationProgress(path string) (*CalibrationProgress, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var p CalibrationProgress
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("could not read calibration progress %s: %w", path, err)
+	}
+	return &p, nil
+}
+
+// Run starts a calibration, or resumes the unfinished one persisted at the
+// state path, and advances it every CalibrationPollInterval until it is
+// done or ctx is done. progress, when not nil, is called with every change
+// of phase, charge or waiting reason.
+//
+// Whenever Run returns, the charging and adapter states found when the
+// calibration started are restored, so the adapter is never left disabled.
+// The progress stays persisted after ctx is done; call Run again to resume
+// or Cancel to discard it. Run returns nil once the calibration is done.
+func (c *Calibration) Run(ctx context.Context, progress func(CalibrationProgress)) (err error) {
+	p, err := c.start()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if restoreErr := c.restore(p.Original); restoreErr != nil {
+			err = errors.Join(err, restoreErr)
+		}
+	}()
+	if progress == nil {
+		progress = func(CalibrationProgress) {}
+	}
+	progress(*p)
+
+	ticker := time.NewTicker(c.poll)
+	defer ticker.Stop()
+	for {
+		changed, stepErr := c.step(p)
+		if errors.Is(stepErr, ErrPermissionRequired) || errors.Is(stepErr, ErrNotSupported) || errors.Is(stepErr, ErrWriteDenied) {
+			return stepErr
+		}
+		if stepErr != nil {
+			c.client.logger.Printf("Warning: calibration: %v", stepErr)
+		}
+		if changed {
+			if err := c.save(p); err != nil {
+				return err
+			}
+			progress(*p)
+		}
+		if p.Phase == CalibrationDone {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Cancel discards the persisted calibration and restores the charging and
+// adapter states found when it started. It does nothing when no
+// calibration was started. Stop a running Run before cancelling.
+func (c *Calibration) Cancel() error {
+	p, err := LoadCalibrationProgress(c.path)
+	if err != nil || p == nil {
+		return err
+	}
+	if p.Phase != CalibrationDone {
+		if err := c.restore(p.Original); err != nil {
+			return err
+		}
+	}
+	return os.Remove(c.path)
+}
+
+// start loads an unfinished calibration or begins a new one, recording the
+// current SMC controls to restore later.
+func (c *Calibration) start() (*CalibrationProgress, error) {
+	p, err := LoadCalibrationProgress(c.path)
+	if err != nil {
+		return nil, err
+	}
+	if p != nil && p.Phase != CalibrationDone {
+		return p, nil
+	}
+
+	info, err := c.client.GetSystemInfo(FetchOptions{QuerySMC: true})
+	if err == nil && info.SMC == nil {
+		err = fmt.Errorf("%w: calibration needs SMC state", ErrNotSupported)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not start calibration: %w", err)
+	}
+	now := c.now()
+	p = &CalibrationProgress{
+		Config:         c.config,
+		Phase:          CalibrationCharge,
+		StartedAt:      now,
+		PhaseStartedAt: now,
+		Original: CalibrationControls{
+			ChargingEnabled: info.SMC.State.IsChargingEnabled,
+			AdapterEnabled:  info.SMC.State.IsAdapterEnabled,
+		},
+		UpdatedAt: now,
+	}
+	return p, c.save(p)
+}
+
+// step reads the battery, advances the phase and applies the controls the
+// phase needs. It reports whether the progress changed.
+func (c *Calibration) step(p *CalibrationProgress) (bool, error) {
+	info, err := c.client.GetSystemInfo(FetchOptions{QueryIOKit: true, QuerySMC: true})
+	if err == nil && (info.IOKit == nil || info.SMC == nil) {
+		err = fmt.Errorf("%w: calibration needs battery and SMC state", ErrNotSupported)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	now := c.now()
+	before := *p
+	p.Charge = info.IOKit.Battery.CurrentCharge
+	p.Waiting = ""
+	if !info.IOKit.State.IsConnected && p.Phase != CalibrationDischarge {
+		p.Waiting = "adapter disconnected"
+	} else {
+		c.advance(p, info.IOKit, now)
+	}
+	changed := p.Phase != before.Phase || p.Charge != before.Charge || p.Waiting != before.Waiting
+	if changed {
+		p.UpdatedAt = now
+	}
+	if p.Waiting != "" || p.Phase == CalibrationDone {
+		return changed, nil
+	}
+	return changed, c.apply(p.Phase, info.SMC.State)
+}
+
+// advance moves p to the next phase once the current one is complete.
+func (c *Calibration) advance(p *CalibrationProgress, data *IOKitData, now time.Time) {
+	full := data.Battery.CurrentCharge >= 100 || data.State.FullyCharged
+	next := p.Phase
+	switch p.Phase {
+	case CalibrationCharge:
+		if full {
+			next = CalibrationHold
+		}
+	case CalibrationHold:
+		if !now.Before(p.PhaseStartedAt.Add(p.Config.Hold)) {
+			next = CalibrationDischarge
+		}
+	case CalibrationDischarge:
+		if data.Battery.CurrentCharge <= p.Config.Floor {
+			next = CalibrationRecharge
+		}
+	case CalibrationRecharge:
+		if full {
+			next = CalibrationDone
+		}
+	}
+	if next != p.Phase {
+		p.Phase, p.PhaseStartedAt = next, now
+	}
+}
+
+// apply enables charging and sets the adapter off during the discharge
+// phase and on otherwise, writing only what differs from state.
+func (c *Calibration) apply(phase CalibrationPhase, state SMCState) error {
+	adapter := phase != CalibrationDischarge
+	if state.IsAdapterEnabled != adapter {
+		if err := c.setAdapter(adapter); err != nil {
+			return err
+		}
+	}
+	if !state.IsChargingEnabled {
+		return c.client.SetChargingState(ChargingActionOn)
+	}
+	return nil
+}
+
+// restore writes the charging and adapter states in controls.
+func (c *Calibration) restore(controls CalibrationControls) error {
+	var action ChargingAction = ChargingActionOff
+	if controls.ChargingEnabled {
+		action = ChargingActionOn
+	}
+	return errors.Join(c.setAdapter(controls.AdapterEnabled), c.client.SetChargingState(action))
+}
+
+func (c *Calibration) setAdapter(enable bool) error {
+	var action AdapterAction = AdapterActionOff
+	if enable {
+		action = AdapterActionOn
+	}
+	return c.client.SetAdapterState(action)
+}
+
+// save writes p to the state path, replacing it atomically.
+func (c *Calibration) save(p *CalibrationProgress) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return err
+	}
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, c.path)
+}
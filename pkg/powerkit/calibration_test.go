@@ -0,0 +1,117 @@
+package powerkit
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCalibrationPhases(t *testing.T) {
+	rig := newLimiterRig(60)
+	rig.smc.values["CHTE"] = chteDisabled
+	path := filepath.Join(t.TempDir(), "calibration.json")
+	cal, err := NewCalibration(New(WithBackend(rig.backend), WithRootCheck(false)), path, CalibrationConfig{Hold: time.Hour, Floor: 10})
+	if err != nil {
+		t.Fatalf("NewCalibration returned error: %v", err)
+	}
+	start := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	now := start
+	cal.now = func() time.Time { return now }
+
+	p, err := cal.start()
+	if err != nil {
+		t.Fatalf("start returned error: %v", err)
+	}
+	if p.Original.ChargingEnabled || !p.Original.AdapterEnabled {
+		t.Fatalf("unexpected original controls: %+v", p.Original)
+	}
+
+	steps := []struct {
+		name    string
+		after   time.Duration
+		charge  int
+		unplug  bool
+		phase   CalibrationPhase
+		adapter bool
+	}{
+		{name: "charging is enabled", charge: 60, phase: CalibrationCharge, adapter: true},
+		{name: "unplugged waits", charge: 70, unplug: true, phase: CalibrationCharge, adapter: true},
+		{name: "full starts the hold", after: 2 * time.Hour, charge: 100, phase: CalibrationHold, adapter: true},
+		{name: "hold lasts its duration", after: 150 * time.Minute, charge: 100, phase: CalibrationHold, adapter: true},
+		{name: "hold ends and the adapter is disabled", after: 3 * time.Hour, charge: 100, phase: CalibrationDischarge},
+		{name: "discharge continues above the floor", after: 5 * time.Hour, charge: 40, phase: CalibrationDischarge},
+		{name: "floor starts the recharge", after: 8 * time.Hour, charge: 10, phase: CalibrationRecharge, adapter: true},
+		{name: "full again is done", after: 10 * time.Hour, charge: 100, phase: CalibrationDone, adapter: true},
+	}
+	for _, step := range steps {
+		now = start.Add(step.after)
+		rig.charge, rig.connected = step.charge, !step.unplug
+		if _, err := cal.step(p); err != nil {
+			t.Fatalf("%s: step returned error: %v", step.name, err)
+		}
+		adapter := !bytes.Equal(rig.smc.values["CHIE"], []byte{0x08})
+		if p.Phase != step.phase || adapter != step.adapter || !rig.chargingEnabled() {
+			t.Fatalf("%s: phase %s, adapter %v, charging %v", step.name, p.Phase, adapter, rig.chargingEnabled())
+		}
+		if step.unplug != (p.Waiting != "") {
+			t.Fatalf("%s: unexpected waiting reason %q", step.name, p.Waiting)
+		}
+	}
+
+	if _, err := NewCalibration(nil, path, CalibrationConfig{Floor: 80}); err == nil {
+		t.Fatalf("expected a floor above 50 to be rejected")
+	}
+}
+
+func TestCalibrationResumeAndCancel(t *testing.T) {
+	rig := newLimiterRig(100)
+	client := New(WithBackend(rig.backend), WithRootCheck(false))
+	path := filepath.Join(t.TempDir(), "calibration.json")
+	cal, err := NewCalibration(client, path, CalibrationConfig{Hold: time.Nanosecond, Floor: 20})
+	if err != nil {
+		t.Fatalf("NewCalibration returned error: %v", err)
+	}
+	cal.poll = time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events := make(chan CalibrationProgress, 16)
+	done := make(chan error, 1)
+	go func() { done <- cal.Run(ctx, func(p CalibrationProgress) { events <- p }) }()
+	for p := range events {
+		if p.Phase == CalibrationDischarge {
+			break
+		}
+	}
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected Run to stop with context.Canceled, got %v", err)
+	}
+	if !bytes.Equal(rig.smc.values["CHIE"], []byte{0x00}) {
+		t.Fatalf("expected the adapter restored on cancellation, got %x", rig.smc.values["CHIE"])
+	}
+
+	saved, err := LoadCalibrationProgress(path)
+	if err != nil || saved == nil || saved.Phase != CalibrationDischarge || saved.Config.Floor != 20 {
+		t.Fatalf("unexpected persisted progress %+v (err %v)", saved, err)
+	}
+
+	// A new calibration resumes the persisted discharge, not its own config.
+	resumed, err := NewCalibration(client, path, CalibrationConfig{Floor: 5})
+	if err != nil {
+		t.Fatalf("NewCalibration returned error: %v", err)
+	}
+	p, err := resumed.start()
+	if err != nil || p.Phase != CalibrationDischarge || p.Config.Floor != 20 {
+		t.Fatalf("expected the discharge to resume, got %+v (err %v)", p, err)
+	}
+
+	if err := resumed.Cancel(); err != nil {
+		t.Fatalf("Cancel returned error: %v", err)
+	}
+	if saved, err := LoadCalibrationProgress(path); err != nil || saved != nil {
+		t.Fatalf("expected the progress discarded, got %+v (err %v)", saved, err)
+	}
+}
@@ -0,0 +1,445 @@
+package powerkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ChargeLimiterPollInterval is how often a running ChargeLimiter re-reads
+// the battery when no event arrives, because battery updates may be dropped.
+const ChargeLimiterPollInterval = time.Minute
+
+// ChargeLimit keeps the battery between two charge percentages: charging
+// stops once the charge reaches Upper and resumes once it falls to Lower.
+type ChargeLimit struct {
+	Upper int `json:"upper"`
+	Lower int `json:"lower"`
+}
+
+// Validate checks 0 < Lower < Upper <= 100.
+func (l ChargeLimit) Validate() error {
+	if l.Lower <= 0 || l.Upper > 100 || l.Lower >= l.Upper {
+		return fmt.Errorf("invalid charge limit: need 0 < lower (%d) < upper (%d) <= 100", l.Lower, l.Upper)
+	}
+	return nil
+}
+
+// wantCharging applies the hysteresis: inside the band the previous
+// decision stands, so the battery sails down to Lower before charging again.
+func (l ChargeLimit) wantCharging(charge int, charging bool) bool {
+	switch {
+	case charge >= l.Upper:
+		return false
+	case charge <= l.Lower:
+		return true
+	default:
+		return charging
+	}
+}
+
+// ChargeLimiterStatus is the state a ChargeLimiter observed at its last
+// evaluation.
+type ChargeLimiterStatus struct {
+	Limit            ChargeLimit `json:"limit"`
+	Charge           int         `json:"charge"`
+	AdapterConnected bool        `json:"adapter_connected"`
+	// ChargingEnabled is the SMC charging state; Holding is true while the
+	// limiter keeps charging disabled.
+	ChargingEnabled bool `json:"charging_enabled"`
+	Holding         bool `json:"holding"`
+	// TopUp is the schedule set with SetTopUp. ToppingUp is true while the
+	// limiter charges to 100% for TopUpDeadline, having started at
+	// TopUpStart; otherwise TopUpStart is when the next top-up is expected
+	// to begin.
+	TopUp         *TopUpSchedule `json:"top_up,omitempty"`
+	ToppingUp     bool           `json:"topping_up"`
+	TopUpStart    *time.Time     `json:"top_up_start,omitempty"`
+	TopUpDeadline *time.Time     `json:"top_up_deadline,omitempty"`
+	// Thermal is the policy set with SetThermalPolicy. ThermalPaused is
+	// true while it holds charging at Temperature, in degrees Celsius.
+	Thermal       *ThermalPolicy `json:"thermal,omitempty"`
+	ThermalPaused bool           `json:"thermal_paused"`
+	Temperature   float64        `json:"temperature"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	LastError     string         `json:"last_error,omitempty"`
+}
+
+// ChargeLimiter keeps the battery within a ChargeLimit by toggling
+// SetChargingState as the charge moves. It decides from
+// IOKitBattery.CurrentCharge and re-reads SMCState.IsChargingEnabled on every
+// evaluation, so a charging state the firmware reset on wake or adapter
+// reconnect is applied again. It writes nothing while the adapter is
+// disconnected.
+//
+// With a top-up schedule the limiter lifts the limit ahead of each deadline
+// so the battery is full by then; see SetTopUp. With a thermal policy it
+// also holds charging while the battery is hot; see SetThermalPolicy.
+type ChargeLimiter struct {
+	client *Client
+	now    func() time.Time
+
+	mu      sync.Mutex
+	limit   ChargeLimit
+	decided bool
+	// charging is the limiter's decision, which persists between
+	// evaluations inside the band.
+	charging bool
+	status   ChargeLimiterStatus
+
+	topUp *TopUpSchedule
+	// topUpUntil is the deadline of the top-up in progress, kept so the
+	// top-up does not stop when the estimate shrinks while charging.
+	topUpUntil time.Time
+	// chargePower is the smoothed charging power observed, in watts.
+	chargePower float64
+
+	thermal       *ThermalPolicy
+	thermalPaused bool
+	// thermalSince is when the thermal policy last paused or resumed
+	// charging.
+	thermalSince time.Time
+
+	// streamDone is closed when the client's event stream ends. The stream
+	// is drained until then, even while Run is not running.
+	streamDone chan struct{}
+	wake       chan struct{}
+}
+
+// NewChargeLimiter creates a limiter that controls charging through client,
+// or the default client when client is nil.
+//
+// The limiter takes the client's event stream and its BeforeSleep hook when
+// Run is first called and holds them until the stream ends; there is no way
+// to give them back. Afterwards StreamSystemEvents on that client, and Run
+// of any other limiter on it, fail with an "already active" error. Use one
+// limiter per client, and a separate client for other event consumers
+// (with the system backend, a separate process).
+func NewChargeLimiter(client *Client, limit ChargeLimit) (*ChargeLimiter, error) {
+	if err := limit.Validate(); err != nil {
+		return nil, err
+	}
+	if client == nil {
+		client = Default()
+	}
+	return &ChargeLimiter{
+		client: client,
+		now:    time.Now,
+		limit:  limit,
+		status: ChargeLimiterStatus{Limit: limit},
+		wake:   make(chan struct{}, 1),
+	}, nil
+}
+
+// SetLimit changes the limit of a running limiter and re-evaluates it.
+func (l *ChargeLimiter) SetLimit(limit ChargeLimit) error {
+	if err := limit.Validate(); err != nil {
+		return err
+	}
+	l.mu.Lock()
+	l.limit = limit
+	l.status.Limit = limit
+	l.mu.Unlock()
+	l.trigger()
+	return nil
+}
+
+// Limit returns the current limit.
+func (l *ChargeLimiter) Limit() ChargeLimit {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.limit
+}
+
+// SetTopUp makes the limiter charge to 100% by each deadline of schedule,
+// or stops topping up when schedule is nil. The top-up starts when the time
+// left before the deadline falls to the estimated charging time: the energy
+// missing to full over the charging power last observed from
+// IOKitCalculations.BatteryPower or IOKitBattery.TimeToFull (or
+// DefaultTopUpChargePower before any), plus TopUpMargin. The Mac must be
+// awake for the top-up to start; schedule a wake, for example with
+// pmset repeat, when it usually sleeps on the adapter.
+func (l *ChargeLimiter) SetTopUp(schedule *TopUpSchedule) error {
+	if schedule != nil {
+		if err := schedule.Validate(); err != nil {
+			return err
+		}
+		sched := *schedule
+		schedule = &sched
+	}
+	l.mu.Lock()
+	l.topUp = schedule
+	l.topUpUntil = time.Time{}
+	l.status.TopUp = schedule
+	l.mu.Unlock()
+	l.trigger()
+	return nil
+}
+
+// TopUp returns the top-up schedule, or nil when none is set.
+func (l *ChargeLimiter) TopUp() *TopUpSchedule {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.topUp == nil {
+		return nil
+	}
+	sched := *l.topUp
+	return &sched
+}
+
+// SetThermalPolicy holds charging while the battery is hot, or stops doing
+// so when policy is nil. The thermal pause overrides the limit and a
+// top-up; to apply it without limiting, use ChargeLimit{Upper: 100, Lower:
+// 99}.
+func (l *ChargeLimiter) SetThermalPolicy(policy *ThermalPolicy) error {
+	if policy != nil {
+		if err := policy.Validate(); err != nil {
+			return err
+		}
+		p := *policy
+		policy = &p
+	}
+	l.mu.Lock()
+	l.thermal = policy
+	l.thermalPaused = false
+	l.thermalSince = time.Time{}
+	l.status.Thermal = policy
+	l.mu.Unlock()
+	l.trigger()
+	return nil
+}
+
+// ThermalPolicy returns the thermal policy, or nil when none is set.
+func (l *ChargeLimiter) ThermalPolicy() *ThermalPolicy {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.thermal == nil {
+		return nil
+	}
+	p := *l.thermal
+	return &p
+}
+
+// Status returns the state observed at the last evaluation.
+func (l *ChargeLimiter) Status() ChargeLimiterStatus {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.status
+}
+
+// trigger asks a running limiter to re-evaluate.
+func (l *ChargeLimiter) trigger() {
+	select {
+	case l.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run evaluates the limit on every system event, on SetLimit and at
+// ChargeLimiterPollInterval until ctx is done, then re-enables charging so
+// the battery is not left held. Before sleep it disables charging once the
+// charge has reached Lower, since the limit cannot be observed while
+// asleep. Evaluation errors are logged and kept in Status; Run stops only
+// when ctx is done, the event stream ends or root is required.
+//
+// The first Run subscribes to the client's event stream. The limiter keeps
+// draining it after Run returns, so queued events never block the sleep
+// notification path, and Run may be called again.
+func (l *ChargeLimiter) Run(ctx context.Context) error {
+	streamDone, err := l.subscribe()
+	if err != nil {
+		return err
+	}
+	ticker := time.NewTicker(ChargeLimiterPollInterval)
+	defer ticker.Stop()
+	defer l.release()
+
+	for {
+		if err := l.evaluate(); errors.Is(err, ErrPermissionRequired) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-streamDone:
+			return errors.New("system event stream closed")
+		case <-ticker.C:
+		case <-l.wake:
+		}
+	}
+}
+
+// subscribe starts the event stream on first use, or again once the
+// previous one ended, and returns a channel closed when the stream ends.
+func (l *ChargeLimiter) subscribe() (<-chan struct{}, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.streamDone != nil {
+		select {
+		case <-l.streamDone:
+			l.streamDone = nil
+		default:
+		}
+	}
+	if l.streamDone == nil {
+		events, err := l.client.StreamSystemEventsWithHooks(StreamHooks{BeforeSleep: l.beforeSleep})
+		if err != nil {
+			return nil, fmt.Errorf("could not start charge limiter: %w", err)
+		}
+		done := make(chan struct{})
+		l.streamDone = done
+		go l.drain(events, done)
+	}
+	return l.streamDone, nil
+}
+
+// drain turns every event into a coalesced re-evaluation request, which a
+// stopped limiter simply never reads.
+func (l *ChargeLimiter) drain(events <-chan SystemEvent, done chan<- struct{}) {
+	defer close(done)
+	for range events {
+		l.trigger()
+	}
+}
+
+// evaluate reads the charge and charging state and applies the limit.
+func (l *ChargeLimiter) evaluate() error {
+	info, err := l.client.GetSystemInfo(FetchOptions{QueryIOKit: true, QuerySMC: true})
+	if err == nil && (info.IOKit == nil || info.SMC == nil) {
+		err = fmt.Errorf("%w: charge limiting needs battery and SMC state", ErrNotSupported)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	l.status.UpdatedAt = now
+	if err != nil {
+		l.fail(err)
+		return err
+	}
+
+	charge, enabled := info.IOKit.Battery.CurrentCharge, info.SMC.State.IsChargingEnabled
+	if !l.decided {
+		l.charging, l.decided = enabled, true
+	}
+	l.charging = l.effectiveLimit(now, info.IOKit).wantCharging(charge, l.charging)
+	want := l.charging && !l.thermalHold(now, info.IOKit.Battery.Temperature)
+	l.status.Charge = charge
+	l.status.AdapterConnected = info.IOKit.State.IsConnected
+	l.status.ChargingEnabled = enabled
+	l.status.Holding = !want
+	l.status.LastError = ""
+
+	if !l.status.AdapterConnected || enabled == want {
+		return nil
+	}
+	if err := l.setCharging(want); err != nil {
+		l.fail(err)
+		return err
+	}
+	l.status.ChargingEnabled = want
+	return nil
+}
+
+// thermalHold applies the thermal policy to temp and reports whether it
+// holds charging. The caller holds l.mu.
+func (l *ChargeLimiter) thermalHold(now time.Time, temp float64) bool {
+	l.status.Temperature = temp
+	if l.thermal == nil {
+		l.status.ThermalPaused = false
+		return false
+	}
+	if paused := l.thermal.paused(temp, l.thermalPaused, l.thermalSince, now); paused != l.thermalPaused {
+		l.thermalPaused, l.thermalSince = paused, now
+	}
+	l.status.ThermalPaused = l.thermalPaused
+	return l.thermalPaused
+}
+
+// topUpLimit keeps charging until the battery is full.
+var topUpLimit = ChargeLimit{Upper: 100, Lower: 99}
+
+// effectiveLimit returns the limit to apply at now: the configured limit,
+// or topUpLimit from the estimated start of a top-up until its deadline.
+// The caller holds l.mu.
+func (l *ChargeLimiter) effectiveLimit(now time.Time, data *IOKitData) ChargeLimit {
+	if power := observedChargePower(data); power > 0 {
+		l.chargePower = smoothPower(l.chargePower, power)
+	}
+	if l.topUp == nil {
+		l.status.ToppingUp, l.status.TopUpStart, l.status.TopUpDeadline = false, nil, nil
+		return l.limit
+	}
+
+	if !now.Before(l.topUpUntil) {
+		l.topUpUntil = time.Time{}
+	}
+	deadline := l.topUp.Next(now)
+	start := deadline.Add(-topUpDuration(data.Battery, l.chargePower))
+	if l.topUpUntil.IsZero() && !now.Before(start) {
+		l.topUpUntil = deadline
+	}
+	l.status.ToppingUp = !l.topUpUntil.IsZero()
+	l.status.TopUpStart, l.status.TopUpDeadline = &start, &deadline
+	if l.status.ToppingUp {
+		return topUpLimit
+	}
+	return l.limit
+}
+
+// smoothPower folds a charging power sample into the running average, so
+// one reading at the taper or right after plugging in does not dominate.
+func smoothPower(avg, sample float64) float64 {
+	if avg <= 0 {
+		return sample
+	}
+	return 0.8*avg + 0.2*sample
+}
+
+// beforeSleep holds charging once the last observed charge reached Lower,
+// unless a top-up is in progress.
+func (l *ChargeLimiter) beforeSleep() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.decided || !l.charging || l.status.ToppingUp || l.status.Charge < l.limit.Lower {
+		return
+	}
+	if err := l.setCharging(false); err != nil {
+		l.fail(err)
+		return
+	}
+	l.charging = false
+	l.status.ChargingEnabled = false
+	l.status.Holding = true
+}
+
+// release re-enables charging when Run returns. Clearing decided first
+// keeps the still-registered beforeSleep hook from holding charging while
+// the limiter is stopped.
+func (l *ChargeLimiter) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.decided = false
+	if err := l.setCharging(true); err != nil {
+		l.fail(err)
+		return
+	}
+	l.status.ChargingEnabled = true
+	l.status.Holding = false
+}
+
+func (l *ChargeLimiter) setCharging(enable bool) error {
+	var action ChargingAction = ChargingActionOff
+	if enable {
+		action = ChargingActionOn
+	}
+	return l.client.SetChargingState(action)
+}
+
+// fail records err in the status and logs it. The caller holds l.mu.
+func (l *ChargeLimiter) fail(err error) {
+	l.status.LastError = err.Error()
+	l.client.logger.Printf("Warning: charge limiter: %v", err)
+}
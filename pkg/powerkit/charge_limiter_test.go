@@ -0,0 +1,227 @@
+package powerkit
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+var (
+	chteEnabled  = []byte{0x00, 0x00, 0x00, 0x00}
+	chteDisabled = []byte{0x01, 0x00, 0x00, 0x00}
+)
+
+// limiterRig is a modern-profile machine whose charge and adapter state the
+// test moves by hand.
+type limiterRig struct {
+	smc       *memorySMC
+	charge    int
+	connected bool
+	backend   *stubBackend
+}
+
+func newLimiterRig(charge int) *limiterRig {
+	r := &limiterRig{
+		smc:       &memorySMC{values: map[string][]byte{"CHTE": chteEnabled, "CHIE": {0x00}}},
+		charge:    charge,
+		connected: true,
+	}
+	r.backend = r.smc.backend(FirmwareInfo{Major: FirmwareMajorVersionThreshold})
+	r.backend.fetchSMC = func([]string) (map[string]float64, error) { return map[string]float64{}, nil }
+	r.backend.fetchBattery = func(bool) (*RawBatteryData, error) {
+		return &RawBatteryData{CurrentCharge: r.charge, IsConnected: r.connected}, nil
+	}
+	return r
+}
+
+func (r *limiterRig) chargingEnabled() bool {
+	return !bytes.Equal(r.smc.values["CHTE"], chteDisabled)
+}
+
+func TestChargeLimitWantCharging(t *testing.T) {
+	limit := ChargeLimit{Upper: 80, Lower: 75}
+	tests := []struct {
+		charge   int
+		charging bool
+		want     bool
+	}{
+		{charge: 50, charging: false, want: true},
+		{charge: 75, charging: false, want: true},
+		{charge: 78, charging: true, want: true},
+		{charge: 78, charging: false, want: false},
+		{charge: 80, charging: true, want: false},
+		{charge: 95, charging: true, want: false},
+	}
+	for _, tc := range tests {
+		if got := limit.wantCharging(tc.charge, tc.charging); got != tc.want {
+			t.Fatalf("wantCharging(%d, %v) = %v, want %v", tc.charge, tc.charging, got, tc.want)
+		}
+	}
+
+	for _, invalid := range []ChargeLimit{{Upper: 80, Lower: 80}, {Upper: 101, Lower: 75}, {Upper: 80, Lower: 0}} {
+		if err := invalid.Validate(); err == nil {
+			t.Fatalf("expected %+v to be invalid", invalid)
+		}
+	}
+}
+
+func TestChargeLimiterHysteresis(t *testing.T) {
+	rig := newLimiterRig(79)
+	limiter, err := NewChargeLimiter(New(WithBackend(rig.backend), WithRootCheck(false)), ChargeLimit{Upper: 80, Lower: 75})
+	if err != nil {
+		t.Fatalf("NewChargeLimiter returned error: %v", err)
+	}
+
+	steps := []struct {
+		name     string
+		charge   int
+		reset    bool
+		unplug   bool
+		charging bool
+		writes   int
+	}{
+		{name: "below upper keeps charging", charge: 79, charging: true},
+		{name: "upper stops charging", charge: 80, writes: 1},
+		{name: "band keeps holding", charge: 77, writes: 1},
+		{name: "firmware reset on wake is reapplied", charge: 77, reset: true, writes: 2},
+		{name: "unplugged writes nothing", charge: 74, unplug: true, writes: 2},
+		{name: "reconnect at lower resumes", charge: 74, charging: true, writes: 3},
+		{name: "band keeps charging", charge: 78, charging: true, writes: 3},
+	}
+	for _, step := range steps {
+		rig.charge, rig.connected = step.charge, !step.unplug
+		if step.reset {
+			rig.smc.values["CHTE"] = chteEnabled
+		}
+		if err := limiter.evaluate(); err != nil {
+			t.Fatalf("%s: evaluate returned error: %v", step.name, err)
+		}
+		if !step.unplug && rig.chargingEnabled() != step.charging {
+			t.Fatalf("%s: charging enabled = %v, want %v", step.name, rig.chargingEnabled(), step.charging)
+		}
+		if len(rig.smc.writes) != step.writes {
+			t.Fatalf("%s: expected %d writes, got %v", step.name, step.writes, rig.smc.writes)
+		}
+	}
+
+	status := limiter.Status()
+	if status.Charge != 78 || !status.ChargingEnabled || status.Holding || status.Limit.Upper != 80 {
+		t.Fatalf("unexpected status: %+v", status)
+	}
+}
+
+func TestChargeLimiterRun(t *testing.T) {
+	rig := newLimiterRig(85)
+	source := make(chan EventType, 4)
+	var beforeSleep func()
+	rig.backend.events = func(hook func()) (<-chan EventType, error) {
+		beforeSleep = hook
+		return source, nil
+	}
+	limiter, err := NewChargeLimiter(New(WithBackend(rig.backend), WithRootCheck(false)), ChargeLimit{Upper: 80, Lower: 75})
+	if err != nil {
+		t.Fatalf("NewChargeLimiter returned error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- limiter.Run(ctx) }()
+
+	waitFor(t, "charging to stop above the limit", func() bool { return limiter.Status().Holding })
+	if rig.chargingEnabled() {
+		t.Fatalf("expected charging disabled above the upper limit")
+	}
+
+	if err := limiter.SetLimit(ChargeLimit{Upper: 90, Lower: 86}); err != nil {
+		t.Fatalf("SetLimit returned error: %v", err)
+	}
+	waitFor(t, "the new limit to resume charging", func() bool { return !limiter.Status().Holding })
+
+	rig.charge = 88
+	source <- EventTypeBatteryUpdate
+	waitFor(t, "the battery update", func() bool { return limiter.Status().Charge == 88 })
+	beforeSleep()
+	if rig.chargingEnabled() {
+		t.Fatalf("expected charging held before sleep inside the band")
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected Run to stop with context.Canceled, got %v", err)
+	}
+	if !rig.chargingEnabled() {
+		t.Fatalf("expected charging re-enabled when Run returns")
+	}
+
+	// The stopped limiter keeps draining the stream, so sleep and wake
+	// notifications never back up into the event source.
+	for i := 0; i < 64; i++ {
+		select {
+		case source <- EventTypeSystemWillSleep:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("event %d blocked after Run returned", i)
+		}
+	}
+	beforeSleep()
+	if !rig.chargingEnabled() {
+		t.Fatalf("expected a stopped limiter not to hold charging before sleep")
+	}
+
+	rig.charge = 95
+	ctx, cancel = context.WithCancel(context.Background())
+	go func() { done <- limiter.Run(ctx) }()
+	waitFor(t, "the restarted limiter to hold charging", func() bool { return limiter.Status().Holding })
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the restarted Run to stop with context.Canceled, got %v", err)
+	}
+}
+
+func TestChargeLimiterResubscribesAfterStreamEnds(t *testing.T) {
+	rig := newLimiterRig(85)
+	var sources []chan EventType
+	rig.backend.events = func(func()) (<-chan EventType, error) {
+		source := make(chan EventType)
+		sources = append(sources, source)
+		return source, nil
+	}
+	limiter, err := NewChargeLimiter(New(WithBackend(rig.backend), WithRootCheck(false)), ChargeLimit{Upper: 80, Lower: 75})
+	if err != nil {
+		t.Fatalf("NewChargeLimiter returned error: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- limiter.Run(context.Background()) }()
+	waitFor(t, "charging to stop above the limit", func() bool { return limiter.Status().Holding })
+	close(sources[0])
+	if err := <-done; err == nil || errors.Is(err, context.Canceled) {
+		t.Fatalf("expected Run to report the closed stream, got %v", err)
+	}
+	if !rig.chargingEnabled() {
+		t.Fatalf("expected charging re-enabled when Run returns")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() { done <- limiter.Run(ctx) }()
+	waitFor(t, "the restarted limiter to hold charging", func() bool { return limiter.Status().Holding })
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the restarted Run to stop with context.Canceled, got %v", err)
+	}
+	if len(sources) != 2 {
+		t.Fatalf("expected a second subscription after the stream ended, got %d", len(sources))
+	}
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
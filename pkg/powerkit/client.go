@@ -0,0 +1,152 @@
+package powerkit
+
+import (
+	"log"
+	"sync"
+)
+
+// Client is an independently configured handle on the library. It owns its
+// backend, logger, resolved firmware profile and event stream registration,
+// so several clients (for example a real and a simulated one) can coexist in
+// one process. The package-level functions delegate to Default().
+type Client struct {
+	backend      Backend
+	logger       *log.Logger
+	rootCheck    bool
+	verifyWrites bool
+	auditSink    AuditSink
+	auditActor   string
+
+	profiles        *ProfileRegistry
+	profileOverride string
+	writePolicy     *WritePolicy
+	allowDangerous  bool
+
+	profileOnce sync.Once
+	firmware    FirmwareInfo
+	smcConfig   smcControlConfig
+	// profileMatch records why smcConfig was chosen; profileErr is set when
+	// the pinned profile is not registered.
+	profileMatch profileMatch
+	profileErr   error
+
+	keyNamesMu sync.Mutex
+	keyNames   []string
+
+	streamMu          sync.Mutex
+	streamActive      bool
+	activeStreamHooks StreamHooks
+}
+
+// Option configures a Client created by New.
+type Option func(*Client)
+
+// WithBackend sets the backend the client reads from and writes to.
+// The default is the platform backend: IOKit and the SMC on macOS, sysfs on Linux.
+func WithBackend(b Backend) Option {
+	return func(c *Client) {
+		if b != nil {
+			c.backend = b
+		}
+	}
+}
+
+// WithLogger sets the logger used for non-fatal warnings.
+// The default is log.Default().
+func WithLogger(l *log.Logger) Option {
+	return func(c *Client) {
+		if l != nil {
+			c.logger = l
+		}
+	}
+}
+
+// WithRootCheck controls whether mutating calls fail with
+// ErrPermissionRequired when the process is not running as root. It is on by
+// default; turn it off only for backends that do not touch hardware, such as
+// the smcsim simulated SMC.
+func WithRootCheck(enabled bool) Option {
+	return func(c *Client) {
+		c.rootCheck = enabled
+	}
+}
+
+// WithWriteVerification makes SMC writes read each key back and fail with
+// ErrWriteVerification when it does not hold the written value. Multi-key
+// writes (legacy BCLM+BCDS charging) restore the keys already written when
+// any key fails. It is off by default.
+func WithWriteVerification(enabled bool) Option {
+	return func(c *Client) {
+		c.verifyWrites = enabled
+	}
+}
+
+// New creates a Client. Firmware detection and profile resolution are
+// deferred until the first call that needs them.
+func New(opts ...Option) *Client {
+	c := &Client{
+		backend:   defaultBackend(),
+		logger:    log.Default(),
+		rootCheck: true,
+		profiles:  defaultProfiles,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Backend returns the backend the client was configured with.
+func (c *Client) Backend() Backend {
+	return c.backend
+}
+
+// profile resolves the firmware information and SMC control profile once.
+func (c *Client) profile() (FirmwareInfo, smcControlConfig) {
+	c.profileOnce.Do(func() {
+		c.firmware = c.backend.FirmwareInfo()
+		c.profileMatch, c.profileErr = c.resolveProfile(c.firmware.Major)
+		c.smcConfig = c.profileMatch.config()
+	})
+	return c.firmware, c.smcConfig
+}
+
+// config returns the resolved SMC control profile.
+func (c *Client) config() smcControlConfig {
+	_, cfg := c.profile()
+	return cfg
+}
+
+var (
+	defaultMu     sync.Mutex
+	defaultClient *Client
+)
+
+// Default returns the client used by the package-level functions, creating
+// it with the default backend on first use.
+func Default() *Client {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	if defaultClient == nil {
+		defaultClient = New()
+	}
+	return defaultClient
+}
+
+// SetDefault replaces the client used by the package-level functions.
+// Passing nil resets it to a fresh client on the default backend.
+func SetDefault(c *Client) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultClient = c
+}
+
+// SetBackend replaces the default client with one using b. Passing nil
+// restores the platform default backend.
+func SetBackend(b Backend) {
+	if b == nil {
+		SetDefault(nil)
+		return
+	}
+	SetDefault(New(WithBackend(b)))
+}
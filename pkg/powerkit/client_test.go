@@ -0,0 +1,74 @@
+package powerkit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClientsStreamIndependently(t *testing.T) {
+	newStreamingClient := func() (*Client, chan EventType) {
+		source := make(chan EventType, 1)
+		backend := &stubBackend{
+			events: func(func()) (<-chan EventType, error) { return source, nil },
+		}
+		return New(WithBackend(backend)), source
+	}
+
+	first, firstSource := newStreamingClient()
+	second, secondSource := newStreamingClient()
+
+	firstStream, err := first.StreamSystemEvents()
+	if err != nil {
+		t.Fatalf("first client stream failed: %v", err)
+	}
+	secondStream, err := second.StreamSystemEvents()
+	if err != nil {
+		t.Fatalf("second client stream failed: %v", err)
+	}
+	if _, err := first.StreamSystemEvents(); err != errSystemEventStreamActive {
+		t.Fatalf("expected repeated registration on one client to fail, got %v", err)
+	}
+
+	secondSource <- EventTypeSystemWillSleep
+	select {
+	case event := <-secondStream:
+		if event.Type != EventTypeSystemWillSleep {
+			t.Fatalf("expected SystemWillSleep on second client, got %v", event.Type)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("timed out waiting for second client event")
+	}
+
+	close(firstSource)
+	close(secondSource)
+	for range firstStream {
+	}
+	for range secondStream {
+	}
+}
+
+func TestNewDefersFirmwareDetection(t *testing.T) {
+	calls := 0
+	backend := &countingFirmwareBackend{stubBackend: &stubBackend{}, calls: &calls}
+
+	client := New(WithBackend(backend))
+	if calls != 0 {
+		t.Fatalf("expected New to skip firmware detection, got %d calls", calls)
+	}
+
+	_ = client.collectOSInfo()
+	_ = client.collectOSInfo()
+	if calls != 1 {
+		t.Fatalf("expected firmware to be detected once, got %d calls", calls)
+	}
+}
+
+type countingFirmwareBackend struct {
+	*stubBackend
+	calls *int
+}
+
+func (b *countingFirmwareBackend) FirmwareInfo() FirmwareInfo {
+	*b.calls++
+	return FirmwareInfo{Source: firmwareSourceUnknown}
+}
@@ -1,5 +1,3 @@
-//go:build darwin
-
 package powerkit
 
 import "context"
@@ -17,11 +15,11 @@ func checkContext(ctx context.Context) error {
 }
 
 // GetSystemInfoContext is the context-aware variant of GetSystemInfo.
-func GetSystemInfoContext(ctx context.Context, opts ...FetchOptions) (*SystemInfo, error) {
+func (c *Client) GetSystemInfoContext(ctx context.Context, opts ...FetchOptions) (*SystemInfo, error) {
 	if err := checkContext(ctx); err != nil {
 		return nil, err
 	}
-	info, err := GetSystemInfo(opts...)
+	info, err := c.GetSystemInfo(opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -32,41 +30,73 @@ func GetSystemInfoContext(ctx context.Context, opts ...FetchOptions) (*SystemInf
 }
 
 // SetAdapterStateContext is the context-aware variant of SetAdapterState.
-func SetAdapterStateContext(ctx context.Context, action AdapterAction) error {
+func (c *Client) SetAdapterStateContext(ctx context.Context, action AdapterAction) error {
 	if err := checkContext(ctx); err != nil {
 		return err
 	}
-	return SetAdapterState(action)
+	return c.SetAdapterState(action)
 }
 
 // SetChargingStateContext is the context-aware variant of SetChargingState.
-func SetChargingStateContext(ctx context.Context, action ChargingAction) error {
+func (c *Client) SetChargingStateContext(ctx context.Context, action ChargingAction) error {
 	if err := checkContext(ctx); err != nil {
 		return err
 	}
-	return SetChargingState(action)
+	return c.SetChargingState(action)
 }
 
 // SetMagsafeLEDStateContext is the context-aware variant of SetMagsafeLEDState.
-func SetMagsafeLEDStateContext(ctx context.Context, state MagsafeLEDState) error {
+func (c *Client) SetMagsafeLEDStateContext(ctx context.Context, state MagsafeLEDState) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+	return c.SetMagsafeLEDState(state)
+}
+
+// WriteSMCValueContext is the context-aware variant of WriteSMCValue.
+func (c *Client) WriteSMCValueContext(ctx context.Context, key string, value float64) error {
 	if err := checkContext(ctx); err != nil {
 		return err
 	}
-	return SetMagsafeLEDState(state)
+	return c.WriteSMCValue(key, value)
 }
 
 // SetLowPowerModeContext is the context-aware variant of SetLowPowerMode.
-func SetLowPowerModeContext(ctx context.Context, enable bool) error {
+func (c *Client) SetLowPowerModeContext(ctx context.Context, enable bool) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+	return c.SetLowPowerMode(enable)
+}
+
+// SetLowPowerModeForContext is the context-aware variant of SetLowPowerModeFor.
+func (c *Client) SetLowPowerModeForContext(ctx context.Context, source PowerSource, enable bool) error {
 	if err := checkContext(ctx); err != nil {
 		return err
 	}
-	return SetLowPowerMode(enable)
+	return c.SetLowPowerModeFor(source, enable)
 }
 
 // ToggleLowPowerModeContext is the context-aware variant of ToggleLowPowerMode.
-func ToggleLowPowerModeContext(ctx context.Context) error {
+func (c *Client) ToggleLowPowerModeContext(ctx context.Context) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+	return c.ToggleLowPowerMode()
+}
+
+// SetFanSpeedContext is the context-aware variant of SetFanSpeed.
+func (c *Client) SetFanSpeedContext(ctx context.Context, index int, rpm float64) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+	return c.SetFanSpeed(index, rpm)
+}
+
+// SetFanAutoContext is the context-aware variant of SetFanAuto.
+func (c *Client) SetFanAutoContext(ctx context.Context, index int) error {
 	if err := checkContext(ctx); err != nil {
 		return err
 	}
-	return ToggleLowPowerMode()
+	return c.SetFanAuto(index)
 }
@@ -1,17 +1,14 @@
-//go:build darwin
-
 package powerkit
 
 import (
 	"bytes"
 
-	"github.com/peterneutron/powerkit-go/internal/iokit"
 	"github.com/peterneutron/powerkit-go/internal/smc"
 )
 
 // newSMCData is a private helper that transforms raw SMC key-value data
 // into the public SMCData struct.
-func newSMCData(floatResults map[string]float64, rawResults map[string]smc.RawSMCValue) *SMCData {
+func newSMCData(cfg smcControlConfig, floatResults map[string]float64, rawResults map[string]RawSMCValue) *SMCData {
 	data := &SMCData{
 		State:   SMCState{},
 		Battery: SMCBattery{},
@@ -33,26 +30,22 @@ func newSMCData(floatResults map[string]float64, rawResults map[string]smc.RawSM
 
 	// --- Populate the State struct from the rawResults, respecting OS version ---
 
-	// Check for IsChargingEnabled state
-	var chargingKeyToCheck string
-	if currentSMCConfig.IsLegacyCharging {
-		chargingKeyToCheck = smc.KeyIsChargingEnabledLegacyBCLM
-	} else {
-		chargingKeyToCheck = smc.KeyIsChargingEnabled
-	}
+	// Check for IsChargingEnabled state; the first charging key of the
+	// profile (BCLM on legacy firmware) is sufficient.
+	chargingKeyToCheck := cfg.controlKeys()[1]
 	if val, ok := rawResults[chargingKeyToCheck]; ok {
 		// Enabled is the default; we check for the disabled bytes.
 		// A value not equal to the 'disabled' state is considered 'enabled'.
-		if !bytes.Equal(val.Data, currentSMCConfig.ChargingDisableBytes) {
+		if !bytes.Equal(val.Data, cfg.ChargingDisableBytes) {
 			data.State.IsChargingEnabled = true
 		}
 	}
 
 	// Check for IsAdapterEnabled state
-	adapterKeyToCheck := currentSMCConfig.AdapterKey
+	adapterKeyToCheck := cfg.AdapterKey
 	if val, ok := rawResults[adapterKeyToCheck]; ok {
 		// Enabled is the default; we check for the disabled bytes.
-		if !bytes.Equal(val.Data, currentSMCConfig.AdapterDisableBytes) {
+		if !bytes.Equal(val.Data, cfg.AdapterDisableBytes) {
 			data.State.IsAdapterEnabled = true
 		}
 	}
@@ -60,9 +53,9 @@ func newSMCData(floatResults map[string]float64, rawResults map[string]smc.RawSM
 	return data
 }
 
-// newIOKitData is a private helper that transforms raw IOKit data
+// newIOKitData is a private helper that transforms raw battery data
 // into the public IOKitData struct. This is its only job.
-func newIOKitData(raw *iokit.RawData) *IOKitData {
+func newIOKitData(raw *RawBatteryData) *IOKitData {
 	return &IOKitData{
 		State: IOKitState{
 			IsCharging:   raw.IsCharging,
@@ -0,0 +1,245 @@
+package powerkit
+
+import "context"
+
+// The functions in this file are the package-level API. Each one delegates
+// to the client returned by Default().
+
+// GetSystemInfo is the main entry point of the library.
+// It fetches and returns a comprehensive snapshot of the system's power state.
+func GetSystemInfo(opts ...FetchOptions) (*SystemInfo, error) {
+	return Default().GetSystemInfo(opts...)
+}
+
+// GetRawSMCValues fetches the raw, undecoded data for a given list of SMC keys.
+func GetRawSMCValues(keys []string) (map[string]RawSMCValue, error) {
+	return Default().GetRawSMCValues(keys)
+}
+
+// StreamSystemEvents starts monitoring for all relevant power and battery
+// events. It returns a single, read-only channel that delivers a unified
+// SystemEvent for any change.
+func StreamSystemEvents() (<-chan SystemEvent, error) {
+	return Default().StreamSystemEvents()
+}
+
+// StreamSystemEventsWithHooks starts the singleton system event stream and
+// installs synchronous lifecycle hooks such as BeforeSleep.
+func StreamSystemEventsWithHooks(hooks StreamHooks) (<-chan SystemEvent, error) {
+	return Default().StreamSystemEventsWithHooks(hooks)
+}
+
+// SetAdapterState provides a high-level API to control the adapter state.
+func SetAdapterState(action AdapterAction) error {
+	return Default().SetAdapterState(action)
+}
+
+// SetChargingState provides a high-level API to control the charging state.
+func SetChargingState(action ChargingAction) error {
+	return Default().SetChargingState(action)
+}
+
+// SetMagsafeLEDState writes the desired LED state to the Magsafe LED SMC key.
+func SetMagsafeLEDState(state MagsafeLEDState) error {
+	return Default().SetMagsafeLEDState(state)
+}
+
+// ListSMCKeys enumerates every key the SMC exposes.
+func ListSMCKeys() ([]SMCKeyInfo, error) {
+	return Default().ListSMCKeys()
+}
+
+// GetSensors reads the catalog sensors in the given categories, or all of them.
+func GetSensors(categories ...SensorCategory) ([]SensorReading, error) {
+	return Default().GetSensors(categories...)
+}
+
+// GetSMCKeyInfo returns the data type, size and attribute flags of the given keys.
+func GetSMCKeyInfo(keys []string) (map[string]SMCKeyInfo, error) {
+	return Default().GetSMCKeyInfo(keys)
+}
+
+// WriteSMCValue encodes value for the key's data type and writes it.
+func WriteSMCValue(key string, value float64) error {
+	return Default().WriteSMCValue(key, value)
+}
+
+// GetFanCount returns the number of fans.
+func GetFanCount() (int, error) {
+	return Default().GetFanCount()
+}
+
+// GetFans returns the speeds and mode of every fan.
+func GetFans() ([]FanInfo, error) {
+	return Default().GetFans()
+}
+
+// SetFanSpeed forces a fan to a target speed in RPM.
+func SetFanSpeed(index int, rpm float64) error {
+	return Default().SetFanSpeed(index, rpm)
+}
+
+// SetFanAuto returns a fan to automatic control.
+func SetFanAuto(index int) error {
+	return Default().SetFanAuto(index)
+}
+
+// GetMagsafeStatus returns the current MagSafe LED status.
+func GetMagsafeStatus() (MagsafeStatus, error) {
+	return Default().GetMagsafeStatus()
+}
+
+// GetMagsafeLEDState reads the single-byte LED state.
+func GetMagsafeLEDState() (state MagsafeLEDState, available bool, err error) {
+	return Default().GetMagsafeLEDState()
+}
+
+// IsMagsafeCharging checks if the Magsafe LED indicates a charging state (Amber).
+func IsMagsafeCharging() (bool, error) {
+	return Default().IsMagsafeCharging()
+}
+
+// IsMagsafeAvailable returns true if the ACLC key is present and has data.
+func IsMagsafeAvailable() bool {
+	return Default().IsMagsafeAvailable()
+}
+
+// CreateAssertion creates a power assertion of the given type.
+func CreateAssertion(assertionType AssertionType, reason string) (AssertionID, error) {
+	return Default().CreateAssertion(assertionType, reason)
+}
+
+// ReleaseAssertion releases the assertion of the given type, if held.
+func ReleaseAssertion(assertionType AssertionType) {
+	Default().ReleaseAssertion(assertionType)
+}
+
+// AllowAllSleep releases every assertion held by this process.
+func AllowAllSleep() {
+	Default().AllowAllSleep()
+}
+
+// IsAssertionActive reports whether this process holds an assertion of the given type.
+func IsAssertionActive(assertionType AssertionType) bool {
+	return Default().IsAssertionActive(assertionType)
+}
+
+// GetAssertionID returns the ID of the assertion of the given type, if held.
+func GetAssertionID(assertionType AssertionType) (AssertionID, bool) {
+	return Default().GetAssertionID(assertionType)
+}
+
+// GetLowPowerModeEnabled reports whether Low Power Mode is enabled and available.
+func GetLowPowerModeEnabled() (bool, bool, error) {
+	return Default().GetLowPowerModeEnabled()
+}
+
+// SetLowPowerMode enables or disables Low Power Mode.
+func SetLowPowerMode(enable bool) error {
+	return Default().SetLowPowerMode(enable)
+}
+
+// SetLowPowerModeFor enables or disables Low Power Mode for one power source.
+func SetLowPowerModeFor(source PowerSource, enable bool) error {
+	return Default().SetLowPowerModeFor(source, enable)
+}
+
+// ToggleLowPowerMode flips the current Low Power Mode setting.
+func ToggleLowPowerMode() error {
+	return Default().ToggleLowPowerMode()
+}
+
+// GetPowerSettings returns the pmset settings of every power source profile.
+func GetPowerSettings() (*PowerSettingsBySource, error) {
+	return Default().GetPowerSettings()
+}
+
+// SetDisplaySleep sets the display sleep timer in minutes for source.
+func SetDisplaySleep(source PowerSource, minutes int) error {
+	return Default().SetDisplaySleep(source, minutes)
+}
+
+// SetSystemSleep sets the system sleep timer in minutes for source.
+func SetSystemSleep(source PowerSource, minutes int) error {
+	return Default().SetSystemSleep(source, minutes)
+}
+
+// SetDiskSleep sets the disk spindown timer in minutes for source.
+func SetDiskSleep(source PowerSource, minutes int) error {
+	return Default().SetDiskSleep(source, minutes)
+}
+
+// SetPowerNap enables or disables Power Nap for source.
+func SetPowerNap(source PowerSource, enable bool) error {
+	return Default().SetPowerNap(source, enable)
+}
+
+// SetTCPKeepAlive enables or disables TCP keepalive during sleep for source.
+func SetTCPKeepAlive(source PowerSource, enable bool) error {
+	return Default().SetTCPKeepAlive(source, enable)
+}
+
+// SetHibernateMode sets the hibernation mode for source.
+func SetHibernateMode(source PowerSource, mode int) error {
+	return Default().SetHibernateMode(source, mode)
+}
+
+// GetSystemInfoContext is a context-aware variant of GetSystemInfo.
+func GetSystemInfoContext(ctx context.Context, opts ...FetchOptions) (*SystemInfo, error) {
+	return Default().GetSystemInfoContext(ctx, opts...)
+}
+
+// SetAdapterStateContext is a context-aware variant of SetAdapterState.
+func SetAdapterStateContext(ctx context.Context, action AdapterAction) error {
+	return Default().SetAdapterStateContext(ctx, action)
+}
+
+// SetChargingStateContext is a context-aware variant of SetChargingState.
+func SetChargingStateContext(ctx context.Context, action ChargingAction) error {
+	return Default().SetChargingStateContext(ctx, action)
+}
+
+// SetMagsafeLEDStateContext is a context-aware variant of SetMagsafeLEDState.
+func SetMagsafeLEDStateContext(ctx context.Context, state MagsafeLEDState) error {
+	return Default().SetMagsafeLEDStateContext(ctx, state)
+}
+
+// WriteSMCValueContext is a context-aware variant of WriteSMCValue.
+func WriteSMCValueContext(ctx context.Context, key string, value float64) error {
+	return Default().WriteSMCValueContext(ctx, key, value)
+}
+
+// SetLowPowerModeContext is a context-aware variant of SetLowPowerMode.
+func SetLowPowerModeContext(ctx context.Context, enable bool) error {
+	return Default().SetLowPowerModeContext(ctx, enable)
+}
+
+// SetLowPowerModeForContext is a context-aware variant of SetLowPowerModeFor.
+func SetLowPowerModeForContext(ctx context.Context, source PowerSource, enable bool) error {
+	return Default().SetLowPowerModeForContext(ctx, source, enable)
+}
+
+// ToggleLowPowerModeContext is a context-aware variant of ToggleLowPowerMode.
+func ToggleLowPowerModeContext(ctx context.Context) error {
+	return Default().ToggleLowPowerModeContext(ctx)
+}
+
+// SetFanSpeedContext is a context-aware variant of SetFanSpeed.
+func SetFanSpeedContext(ctx context.Context, index int, rpm float64) error {
+	return Default().SetFanSpeedContext(ctx, index, rpm)
+}
+
+// SetFanAutoContext is a context-aware variant of SetFanAuto.
+func SetFanAutoContext(ctx context.Context, index int) error {
+	return Default().SetFanAutoContext(ctx, index)
+}
+
+// ResolveProfile reports the default client's firmware profile and why it was chosen.
+func ResolveProfile() (*ProfileResolution, error) {
+	return Default().ResolveProfile()
+}
+
+// CaptureSnapshot records the current state of the default client's backend.
+func CaptureSnapshot(extraSMCKeys ...string) (*Snapshot, error) {
+	return Default().CaptureSnapshot(extraSMCKeys...)
+}
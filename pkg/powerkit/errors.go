@@ -1,5 +1,3 @@
-//go:build darwin
-
 package powerkit
 
 import (
@@ -15,11 +13,36 @@ var (
 	ErrNotSupported = errors.New("not supported")
 	// ErrTransientIO indicates a temporary operating-system I/O failure.
 	ErrTransientIO = errors.New("transient io failure")
+	// ErrWriteVerification indicates an SMC key did not read back the value
+	// just written to it.
+	ErrWriteVerification = errors.New("SMC write verification failed")
+	// ErrWriteDenied indicates the client's write policy rejected an SMC
+	// write.
+	ErrWriteDenied = errors.New("SMC write denied by policy")
+	// ErrUnknownProfile indicates a pinned firmware profile ID is not
+	// registered.
+	ErrUnknownProfile = errors.New("unknown firmware profile")
 )
 
+// geteuid is replaced in tests.
+var geteuid = os.Geteuid
+
 func requireRoot(op string) error {
-	if os.Geteuid() == 0 {
+	if geteuid() == 0 {
 		return nil
 	}
 	return fmt.Errorf("%w: %s requires root", ErrPermissionRequired, op)
 }
+
+// requireRoot applies the root check unless the client was configured
+// WithRootCheck(false). A refused call is audited as denied.
+func (c *Client) requireRoot(op string) error {
+	if !c.rootCheck {
+		return nil
+	}
+	err := requireRoot(op)
+	if err != nil {
+		c.recordAudit(AuditRecord{Operation: op}, err)
+	}
+	return err
+}
@@ -0,0 +1,232 @@
+package powerkit
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/peterneutron/powerkit-go/internal/smc"
+)
+
+// maxFans is the number of fans addressable by single-digit fan keys.
+const maxFans = 10
+
+// FanMode is the control mode of a fan.
+type FanMode string
+
+const (
+	// FanModeAuto means the SMC controls the fan speed.
+	FanModeAuto FanMode = "auto"
+	// FanModeManual means the fan is forced to its target speed.
+	FanModeManual FanMode = "manual"
+	// FanModeUnknown means the mode key is missing or holds an unknown value.
+	FanModeUnknown FanMode = "unknown"
+)
+
+// FanInfo holds the telemetry of one fan. Speeds are in RPM; a speed the
+// SMC does not report is zero.
+type FanInfo struct {
+	Index     int     `json:"Index"`
+	ActualRPM float64 `json:"ActualRPM"`
+	MinRPM    float64 `json:"MinRPM"`
+	MaxRPM    float64 `json:"MaxRPM"`
+	TargetRPM float64 `json:"TargetRPM"`
+	Mode      FanMode `json:"Mode"`
+}
+
+// GetFanCount returns the number of fans (FNum). Machines without fan keys
+// return ErrNotSupported.
+func (c *Client) GetFanCount() (int, error) {
+	values, err := c.backend.FetchRawData([]string{smc.KeyFanCount})
+	if err != nil {
+		return 0, fmt.Errorf("could not read fan count: %w", err)
+	}
+	return fanCount(values)
+}
+
+// GetFans returns the speeds and mode of every fan, in index order.
+func (c *Client) GetFans() ([]FanInfo, error) {
+	count, err := c.GetFanCount()
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return []FanInfo{}, nil
+	}
+
+	cfg := c.config()
+	keys := make([]string, 0, count*5)
+	for i := 0; i < count; i++ {
+		keys = append(keys, fanKeys(cfg, i)...)
+	}
+	values, err := c.backend.FetchRawData(keys)
+	if err != nil {
+		return nil, fmt.Errorf("could not read fan telemetry: %w", err)
+	}
+
+	fans := make([]FanInfo, count)
+	for i := range fans {
+		fans[i] = newFanInfo(cfg, i, values)
+	}
+	return fans, nil
+}
+
+// SetFanSpeed forces fan index to rpm, which must lie within the fan's
+// minimum and maximum speed. The fan stays in manual mode until SetFanAuto
+// is called. This function requires root privileges.
+func (c *Client) SetFanSpeed(index int, rpm float64) error {
+	const op = "set fan speed"
+	if err := c.requireRoot(op); err != nil {
+		return err
+	}
+	fans, err := c.GetFans()
+	if err != nil {
+		return err
+	}
+	if err := checkFanSpeed(fans, index, rpm); err != nil {
+		return err
+	}
+
+	cfg := c.config()
+	if err := c.setFanUnlock(op, cfg, true); err != nil {
+		return err
+	}
+	modeKey := smc.FanKey(cfg.FanModeKeyFormat, index)
+	if err := c.writeSMC(op, modeKey, cfg.FanManualBytes); err != nil {
+		err = fmt.Errorf("could not switch fan %d to manual mode: %w", index, err)
+		return errors.Join(err, c.releaseFanUnlock(op, cfg))
+	}
+	if err := c.writeSMCValue(op, smc.FanKey(smc.KeyFanTargetFormat, index), rpm, true); err != nil {
+		// Hand the fan back to the firmware rather than leave it manual at
+		// an unknown target.
+		err = fmt.Errorf("could not set fan %d target speed: %w", index, err)
+		return errors.Join(err, c.writeSMC(op, modeKey, cfg.FanAutoBytes), c.releaseFanUnlock(op, cfg))
+	}
+	return nil
+}
+
+// SetFanAuto returns fan index to automatic control. This function requires
+// root privileges.
+func (c *Client) SetFanAuto(index int) error {
+	const op = "set fan auto"
+	if err := c.requireRoot(op); err != nil {
+		return err
+	}
+	count, err := c.GetFanCount()
+	if err != nil {
+		return err
+	}
+	if index < 0 || index >= count {
+		return fmt.Errorf("invalid fan index %d: this system has %d fan(s)", index, count)
+	}
+
+	cfg := c.config()
+	if err := c.writeSMC(op, smc.FanKey(cfg.FanModeKeyFormat, index), cfg.FanAutoBytes); err != nil {
+		return fmt.Errorf("could not switch fan %d to automatic mode: %w", index, err)
+	}
+	return c.releaseFanUnlock(op, cfg)
+}
+
+// releaseFanUnlock locks fan control again once no fan is left in manual
+// mode.
+func (c *Client) releaseFanUnlock(op string, cfg smcControlConfig) error {
+	fans, err := c.GetFans()
+	if err != nil {
+		return err
+	}
+	for _, fan := range fans {
+		if fan.Mode == FanModeManual {
+			return nil
+		}
+	}
+	return c.setFanUnlock(op, cfg, false)
+}
+
+// setFanUnlock writes the profile's fan unlock key when the profile has one
+// and the SMC exposes it.
+func (c *Client) setFanUnlock(op string, cfg smcControlConfig, unlock bool) error {
+	if cfg.FanUnlockKey == "" {
+		return nil
+	}
+	values, err := c.backend.FetchRawData([]string{cfg.FanUnlockKey})
+	if _, ok := values[cfg.FanUnlockKey]; err != nil || !ok {
+		// Firmware without the unlock key does not gate fan writes.
+		return nil
+	}
+	data := []byte{0x00}
+	if unlock {
+		data[0] = 0x01
+	}
+	if err := c.writeSMC(op, cfg.FanUnlockKey, data); err != nil {
+		return fmt.Errorf("could not write fan unlock key '%s': %w", cfg.FanUnlockKey, err)
+	}
+	return nil
+}
+
+func checkFanSpeed(fans []FanInfo, index int, rpm float64) error {
+	if index < 0 || index >= len(fans) {
+		return fmt.Errorf("invalid fan index %d: this system has %d fan(s)", index, len(fans))
+	}
+	if fan := fans[index]; rpm < fan.MinRPM || (fan.MaxRPM > 0 && rpm > fan.MaxRPM) {
+		return fmt.Errorf("fan %d speed %.0f rpm is outside %.0f-%.0f rpm", index, rpm, fan.MinRPM, fan.MaxRPM)
+	}
+	return nil
+}
+
+func fanCount(values map[string]RawSMCValue) (int, error) {
+	raw, ok := values[smc.KeyFanCount]
+	if !ok {
+		return 0, fmt.Errorf("%w: no fan count key (%s) on this system", ErrNotSupported, smc.KeyFanCount)
+	}
+	decoded, err := raw.Decode()
+	if err != nil || decoded.Kind != SMCValueNumber {
+		return 0, fmt.Errorf("could not decode fan count (%s): %v", smc.KeyFanCount, err)
+	}
+	count := int(decoded.Number)
+	if count > maxFans {
+		count = maxFans
+	}
+	return count, nil
+}
+
+func fanKeys(cfg smcControlConfig, index int) []string {
+	return []string{
+		smc.FanKey(smc.KeyFanActualFormat, index),
+		smc.FanKey(smc.KeyFanMinFormat, index),
+		smc.FanKey(smc.KeyFanMaxFormat, index),
+		smc.FanKey(smc.KeyFanTargetFormat, index),
+		smc.FanKey(cfg.FanModeKeyFormat, index),
+	}
+}
+
+func newFanInfo(cfg smcControlConfig, index int, values map[string]RawSMCValue) FanInfo {
+	speed := func(format string) float64 {
+		raw, ok := values[smc.FanKey(format, index)]
+		if !ok {
+			return 0
+		}
+		decoded, err := raw.Decode()
+		if err != nil || decoded.Kind != SMCValueNumber {
+			return 0
+		}
+		return truncate(decoded.Number)
+	}
+
+	fan := FanInfo{
+		Index:     index,
+		ActualRPM: speed(smc.KeyFanActualFormat),
+		MinRPM:    speed(smc.KeyFanMinFormat),
+		MaxRPM:    speed(smc.KeyFanMaxFormat),
+		TargetRPM: speed(smc.KeyFanTargetFormat),
+		Mode:      FanModeUnknown,
+	}
+	if mode, ok := values[smc.FanKey(cfg.FanModeKeyFormat, index)]; ok {
+		switch {
+		case bytes.Equal(mode.Data, cfg.FanAutoBytes):
+			fan.Mode = FanModeAuto
+		case bytes.Equal(mode.Data, cfg.FanManualBytes):
+			fan.Mode = FanModeManual
+		}
+	}
+	return fan
+}
@@ -0,0 +1,50 @@
+package powerkit
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFanAPIsWithoutFans(t *testing.T) {
+	var wrote bool
+	stub := &stubBackend{
+		fetchSMCRaw: func([]string) (map[string]RawSMCValue, error) { return map[string]RawSMCValue{}, nil },
+		writeSMC: func(string, []byte) error {
+			wrote = true
+			return nil
+		},
+	}
+	client := New(WithBackend(stub), WithRootCheck(false))
+
+	if _, err := client.GetFans(); !errors.Is(err, ErrNotSupported) {
+		t.Fatalf("expected ErrNotSupported without FNum, got %v", err)
+	}
+	if err := client.SetFanSpeed(0, 2000); !errors.Is(err, ErrNotSupported) {
+		t.Fatalf("expected ErrNotSupported from SetFanSpeed, got %v", err)
+	}
+	if err := client.SetFanAuto(0); !errors.Is(err, ErrNotSupported) {
+		t.Fatalf("expected ErrNotSupported from SetFanAuto, got %v", err)
+	}
+	if wrote {
+		t.Fatalf("nothing should be written on a fanless system")
+	}
+}
+
+func TestGetFansReportsUnknownMode(t *testing.T) {
+	stub := &stubBackend{
+		fetchSMCRaw: func([]string) (map[string]RawSMCValue, error) {
+			return map[string]RawSMCValue{
+				"FNum": {DataType: "ui8 ", DataSize: 1, Data: []byte{0x01}},
+				"F0Ac": {DataType: "flt ", DataSize: 4, Data: []byte{0x00, 0x00, 0x96, 0x44}}, // 1200
+				"F0Md": {DataType: "ui8 ", DataSize: 1, Data: []byte{0x03}},
+			}, nil
+		},
+	}
+	fans, err := New(WithBackend(stub)).GetFans()
+	if err != nil {
+		t.Fatalf("GetFans returned error: %v", err)
+	}
+	if len(fans) != 1 || fans[0].ActualRPM != 1200 || fans[0].MaxRPM != 0 || fans[0].Mode != FanModeUnknown {
+		t.Fatalf("unexpected fans: %+v", fans)
+	}
+}
@@ -4,54 +4,23 @@ import (
 	"errors"
 	"testing"
 
-	"github.com/peterneutron/powerkit-go/internal/iokit"
-	"github.com/peterneutron/powerkit-go/internal/powerd"
 	"github.com/peterneutron/powerkit-go/internal/smc"
 )
 
 func setupSystemInfoFixture(t *testing.T) (*SystemInfo, bool) {
 	t.Helper()
 
-	oldFetchIOKit := fetchIOKitData
-	oldFetchSMCFloat := fetchSMCFloatData
-	oldFetchSMCRaw := fetchSMCRawData
-	oldGlobalSleep := globalSleepStatusFn
-	oldPowerdActive := powerdIsActiveFn
-	oldGetLPM := getLowPowerModeFn
-	oldConfig := currentSMCConfig
-	oldFirmwareInfo := currentFirmwareInfo
-
-	t.Cleanup(func() {
-		fetchIOKitData = oldFetchIOKit
-		fetchSMCFloatData = oldFetchSMCFloat
-		fetchSMCRawData = oldFetchSMCRaw
-		globalSleepStatusFn = oldGlobalSleep
-		powerdIsActiveFn = oldPowerdActive
-		getLowPowerModeFn = oldGetLPM
-		currentSMCConfig = oldConfig
-		currentFirmwareInfo = oldFirmwareInfo
-	})
-
-	currentSMCConfig = smcControlConfig{
-		Firmware:               "Test",
-		FirmwareProfileID:      profileModernID,
-		FirmwareProfileVersion: defaultProfileVersion,
-		AdapterKey:             smc.KeyIsAdapterEnabled,
-		AdapterEnableBytes:     []byte{0x00},
-		AdapterDisableBytes:    []byte{0x08},
-		IsLegacyCharging:       false,
-		ChargingKeyModern:      smc.KeyIsChargingEnabled,
-		ChargingEnableBytes:    []byte{0x00, 0x00, 0x00, 0x00},
-		ChargingDisableBytes:   []byte{0x01, 0x00, 0x00, 0x00},
-	}
-	currentFirmwareInfo.Major = FirmwareMajorVersionThreshold
-	currentFirmwareInfo.Version = "iBoot-13822.81.10"
-	currentFirmwareInfo.Source = "ioreg_device_tree"
-
 	var observedForce bool
-	fetchIOKitData = func(force bool) (*iokit.RawData, error) {
+	backend := &stubBackend{
+		firmware: FirmwareInfo{
+			Major:   FirmwareMajorVersionThreshold,
+			Version: "iBoot-13822.81.10",
+			Source:  "ioreg_device_tree",
+		},
+	}
+	backend.fetchBattery = func(force bool) (*RawBatteryData, error) {
 		observedForce = force
-		return &iokit.RawData{
+		return &RawBatteryData{
 			IsCharging:         true,
 			IsConnected:        true,
 			AdapterWatts:       70,
@@ -79,7 +48,7 @@ func setupSystemInfoFixture(t *testing.T) (*SystemInfo, bool) {
 		}, nil
 	}
 
-	fetchSMCFloatData = func(_ []string) (map[string]float64, error) {
+	backend.fetchSMC = func(_ []string) (map[string]float64, error) {
 		return map[string]float64{
 			smc.KeyAdapterVoltage: 19.8,
 			smc.KeyAdapterCurrent: 2.5,
@@ -88,8 +57,8 @@ func setupSystemInfoFixture(t *testing.T) (*SystemInfo, bool) {
 		}, nil
 	}
 
-	fetchSMCRawData = func(_ []string) (map[string]smc.RawSMCValue, error) {
-		return map[string]smc.RawSMCValue{
+	backend.fetchSMCRaw = func(_ []string) (map[string]RawSMCValue, error) {
+		return map[string]RawSMCValue{
 			smc.KeyIsChargingEnabled: {
 				Data: []byte{0x00, 0x00, 0x00, 0x00},
 			},
@@ -99,19 +68,20 @@ func setupSystemInfoFixture(t *testing.T) (*SystemInfo, bool) {
 		}, nil
 	}
 
-	globalSleepStatusFn = func() (bool, bool, error) {
+	backend.globalSleep = func() (bool, bool, error) {
 		return false, false, errors.New("status unavailable")
 	}
 
-	powerdIsActiveFn = func(a powerd.AssertionType) bool {
-		return a == powerd.PreventDisplaySleep
+	backend.activeAssertion = func(a AssertionType) (AssertionID, bool) {
+		return 1, a == AssertionTypePreventDisplaySleep
 	}
 
-	getLowPowerModeFn = func() (bool, bool, error) {
+	backend.lowPowerMode = func() (bool, bool, error) {
 		return true, true, nil
 	}
+	client := New(WithBackend(backend))
 
-	info, err := GetSystemInfo(FetchOptions{QueryIOKit: true, QuerySMC: true, ForceTelemetryFallback: true})
+	info, err := client.GetSystemInfo(FetchOptions{QueryIOKit: true, QuerySMC: true, ForceTelemetryFallback: true})
 	if err != nil {
 		t.Fatalf("GetSystemInfo returned error: %v", err)
 	}
@@ -7,18 +7,9 @@ import (
 	"math"
 	"time"
 
-	"log"
-
-	"github.com/peterneutron/powerkit-go/internal/iokit"
 	"github.com/peterneutron/powerkit-go/internal/smc"
 )
 
-var (
-	fetchIOKitData    = iokit.FetchData
-	fetchSMCFloatData = smc.FetchData
-	fetchSMCRawData   = smc.FetchRawData
-)
-
 // truncate rounds a float down to two decimal places. This is used
 // consistently across the library for formatting final values.
 func truncate(f float64) float64 {
@@ -45,48 +36,54 @@ func findMinMax(a []int) (minVal int, maxVal int) {
 }
 
 // We'll create a helper function for the "On" and "Off" logic.
-func setCharging(enable bool) error {
+func (c *Client) setCharging(enable bool) error {
+	cfg := c.config()
 	var bytesToWrite []byte
 	if enable {
-		bytesToWrite = currentSMCConfig.ChargingEnableBytes
+		bytesToWrite = cfg.ChargingEnableBytes
 	} else {
-		bytesToWrite = currentSMCConfig.ChargingDisableBytes
+		bytesToWrite = cfg.ChargingDisableBytes
 	}
 
-	if currentSMCConfig.IsLegacyCharging {
-		for _, key := range currentSMCConfig.ChargingKeysLegacy {
-			if err := smc.WriteData(key, bytesToWrite); err != nil {
-				return fmt.Errorf("failed to write to legacy charging key '%s': %w", key, err)
-			}
+	if cfg.IsLegacyCharging {
+		// BCLM and BCDS are written as one unit so a verified write can
+		// roll back a half-applied change.
+		writes := make([]smcWrite, len(cfg.ChargingKeysLegacy))
+		for i, key := range cfg.ChargingKeysLegacy {
+			writes[i] = smcWrite{key: key, data: bytesToWrite}
+		}
+		if err := c.writeSMCKeys(opSetChargingState, writes...); err != nil {
+			return fmt.Errorf("failed to write legacy charging keys: %w", err)
 		}
 		return nil
 	}
-	return smc.WriteData(currentSMCConfig.ChargingKeyModern, bytesToWrite)
+	return c.writeSMC(opSetChargingState, cfg.ChargingKeyModern, bytesToWrite)
 }
 
 // Create a helper for fetching IOKit data
-func getIOKitInfo(info *SystemInfo, options FetchOptions) {
+func (c *Client) getIOKitInfo(info *SystemInfo, options FetchOptions) {
 	info.iokitQueried = true
-	iokitRawData, err := fetchIOKitData(options.ForceTelemetryFallback)
+	iokitRawData, err := c.backend.FetchBatteryData(options.ForceTelemetryFallback)
 	if err != nil {
-		log.Printf("Warning: IOKit data fetch failed, continuing without it: %v", err)
+		c.logger.Printf("Warning: IOKit data fetch failed, continuing without it: %v", err)
 		info.iokitAvailable = false
 		return
 	}
 	info.IOKit = newIOKitData(iokitRawData)
 	info.iokitAvailable = true
 	if iokitRawData.TelemetrySource != "" {
-		info.adapterTelemetrySource = string(iokitRawData.TelemetrySource)
+		info.adapterTelemetrySource = iokitRawData.TelemetrySource
 	}
 	if iokitRawData.TelemetryReason != "" {
-		info.adapterTelemetryReason = string(iokitRawData.TelemetryReason)
+		info.adapterTelemetryReason = iokitRawData.TelemetryReason
 	}
 	info.forceTelemetryFallback = options.ForceTelemetryFallback || iokitRawData.ForceFallback
 }
 
 // Create a helper for fetching SMC data
-func getSMCInfo(info *SystemInfo) {
+func (c *Client) getSMCInfo(info *SystemInfo) {
 	info.smcQueried = true
+	cfg := c.config()
 	// Build SMC key lists: separate numeric sensor keys (for float decode)
 	// from control/state keys that are not decodable to float.
 	floatKeys := []string{
@@ -95,25 +92,19 @@ func getSMCInfo(info *SystemInfo) {
 		smc.KeyBatteryVoltage,
 		smc.KeyBatteryCurrent,
 	}
-	rawKeys := append([]string{}, floatKeys...)
-	// Adapter enable state key depends on firmware
-	rawKeys = append(rawKeys, currentSMCConfig.AdapterKey)
-	// Charging enable state keys depend on legacy vs modern
-	if currentSMCConfig.IsLegacyCharging {
-		rawKeys = append(rawKeys, currentSMCConfig.ChargingKeysLegacy...)
-	} else {
-		rawKeys = append(rawKeys, currentSMCConfig.ChargingKeyModern)
-	}
+	// Adapter and charging state keys depend on the firmware profile.
+	rawKeys := append(append([]string{}, floatKeys...), cfg.controlKeys()...)
 
-	smcFloatResults, err1 := fetchSMCFloatData(floatKeys)
-	smcRawResults, err2 := fetchSMCRawData(rawKeys)
+	smcFloatResults, err1 := c.backend.FetchData(floatKeys)
+	smcRawResults, err2 := c.backend.FetchRawData(rawKeys)
 	if err1 != nil || err2 != nil {
-		log.Printf("Warning: SMC data fetch failed, continuing without it. FltErr: %v, RawErr: %v", err1, err2)
+		c.logger.Printf("Warning: SMC data fetch failed, continuing without it. FltErr: %v, RawErr: %v", err1, err2)
 		info.smcAvailable = false
 		return
 	}
-	info.SMC = newSMCData(smcFloatResults, smcRawResults)
+	info.SMC = newSMCData(cfg, smcFloatResults, smcRawResults)
 	info.smcAvailable = true
+	info.smcWritable = c.controlKeysWritable(cfg)
 }
 
 func initSystemInfoMetadata(info *SystemInfo) {
@@ -1,5 +1,3 @@
-//go:build darwin
-
 package powerkit
 
 import "time"
@@ -33,6 +31,14 @@ type OSJSON struct {
 
 // LowPowerModeJSON models Low Power Mode state and availability.
 type LowPowerModeJSON struct {
+	Enabled   bool                   `json:"enabled"`
+	Available bool                   `json:"available"`
+	Battery   LowPowerModeSourceJSON `json:"battery"`
+	AC        LowPowerModeSourceJSON `json:"ac"`
+}
+
+// LowPowerModeSourceJSON models the Low Power Mode setting of one power source.
+type LowPowerModeSourceJSON struct {
 	Enabled   bool `json:"enabled"`
 	Available bool `json:"available"`
 }
@@ -198,6 +204,8 @@ func (s *SystemInfo) ToJSON() SystemInfoJSON {
 			LowPowerMode: LowPowerModeJSON{
 				Enabled:   s.OS.LowPowerMode.Enabled,
 				Available: s.OS.LowPowerMode.Available,
+				Battery:   LowPowerModeSourceJSON(s.OS.LowPowerMode.Battery),
+				AC:        LowPowerModeSourceJSON(s.OS.LowPowerMode.AC),
 			},
 			SleepAssertions: SleepAssertionsJSON{
 				Global: SleepAssertionStateJSON{
@@ -223,7 +231,7 @@ func (s *SystemInfo) ToJSON() SystemInfoJSON {
 			Capabilities: ControlsCapabilitiesJSON{
 				CanQueryIOKit: true,
 				CanQuerySMC:   true,
-				CanWriteSMC:   true,
+				CanWriteSMC:   s.smcWritable,
 			},
 		},
 	}
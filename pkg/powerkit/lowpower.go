@@ -1,30 +1,56 @@
-//go:build darwin
-
 package powerkit
 
 import (
+	"errors"
+
 	sysos "github.com/peterneutron/powerkit-go/internal/os"
 )
 
 // GetLowPowerModeEnabled reports whether macOS Low Power Mode is enabled.
 // The second return value indicates availability on this system.
-func GetLowPowerModeEnabled() (bool, bool, error) {
-	return sysos.GetLowPowerModeEnabled()
+func (c *Client) GetLowPowerModeEnabled() (bool, bool, error) {
+	return c.backend.GetLowPowerModeEnabled()
 }
 
 // SetLowPowerMode enables or disables macOS Low Power Mode.
 // Requires root privileges; callers should handle privilege escalation at the CLI layer.
-func SetLowPowerMode(enable bool) error {
-	if err := requireRoot("set low power mode"); err != nil {
+func (c *Client) SetLowPowerMode(enable bool) error {
+	const op = "set low power mode"
+	if err := c.requireRoot(op); err != nil {
 		return err
 	}
-	return sysos.SetLowPowerMode(enable)
+	return c.auditedSetPowerSetting(op, PowerSourceAll, sysos.SettingLowPowerMode, sysos.BoolValue(enable), func() error {
+		return c.backend.SetLowPowerMode(enable)
+	})
+}
+
+// SetLowPowerModeFor enables or disables Low Power Mode for one power
+// source profile, e.g. only while on battery. Requires root privileges.
+func (c *Client) SetLowPowerModeFor(source PowerSource, enable bool) error {
+	return c.setPowerSetting("set low power mode", source, sysos.SettingLowPowerMode, sysos.BoolValue(enable))
 }
 
 // ToggleLowPowerMode toggles the current Low Power Mode setting.
-func ToggleLowPowerMode() error {
-	if err := requireRoot("toggle low power mode"); err != nil {
+func (c *Client) ToggleLowPowerMode() error {
+	const op = "toggle low power mode"
+	if err := c.requireRoot(op); err != nil {
+		return err
+	}
+	enabled, available, err := c.backend.GetLowPowerModeEnabled()
+	if err != nil {
 		return err
 	}
-	return sysos.ToggleLowPowerMode()
+	if !available {
+		return errors.New("low power mode not available on this system")
+	}
+	return c.auditedSetPowerSetting(op, PowerSourceAll, sysos.SettingLowPowerMode, sysos.BoolValue(!enabled), func() error {
+		return c.backend.SetLowPowerMode(!enabled)
+	})
+}
+
+func lowPowerModeSource(p *PowerSettings) LowPowerModeSourceInfo {
+	if p == nil || !p.Has(sysos.SettingLowPowerMode) {
+		return LowPowerModeSourceInfo{}
+	}
+	return LowPowerModeSourceInfo{Enabled: p.LowPowerMode, Available: true}
 }
@@ -0,0 +1,155 @@
+package powerkit
+
+import (
+	"strconv"
+
+	sysos "github.com/peterneutron/powerkit-go/internal/os"
+)
+
+// PowerSource selects which pmset settings profile a change applies to.
+type PowerSource string
+
+const (
+	// PowerSourceAll applies a change to every profile (pmset -a).
+	PowerSourceAll PowerSource = "all"
+	// PowerSourceBattery is the battery profile (pmset -b).
+	PowerSourceBattery PowerSource = "battery"
+	// PowerSourceAC is the charger profile (pmset -c).
+	PowerSourceAC PowerSource = "ac"
+	// PowerSourceUPS is the UPS profile (pmset -u).
+	PowerSourceUPS PowerSource = "ups"
+)
+
+// PowerSettings is one power source profile from `pmset -g custom`.
+// Durations are in minutes, with 0 meaning never. Values holds every key
+// as printed, including keys without a typed field.
+type PowerSettings struct {
+	DisplaySleep  int    `json:"display_sleep"`
+	Sleep         int    `json:"sleep"`
+	DiskSleep     int    `json:"disk_sleep"`
+	PowerNap      bool   `json:"power_nap"`
+	HibernateMode int    `json:"hibernate_mode"`
+	HibernateFile string `json:"hibernate_file,omitempty"`
+	Standby       bool   `json:"standby"`
+	TCPKeepAlive  bool   `json:"tcp_keepalive"`
+	LowPowerMode  bool   `json:"low_power_mode"`
+	PowerMode     int    `json:"power_mode"`
+	WakeOnLAN     bool   `json:"wake_on_lan"`
+	LidWake       bool   `json:"lid_wake"`
+	ACWake        bool   `json:"ac_wake"`
+	TTYSKeepAwake bool   `json:"ttys_keep_awake"`
+	ProximityWake bool   `json:"proximity_wake"`
+
+	Values map[string]string `json:"values"`
+}
+
+// Has reports whether the profile lists key.
+func (p *PowerSettings) Has(key string) bool {
+	_, ok := p.Values[key]
+	return ok
+}
+
+// PowerSettingsBySource holds the per-source profiles. A profile is nil when
+// the system has no such power source (e.g. Battery on a desktop).
+type PowerSettingsBySource struct {
+	Battery *PowerSettings `json:"battery,omitempty"`
+	AC      *PowerSettings `json:"ac,omitempty"`
+	UPS     *PowerSettings `json:"ups,omitempty"`
+}
+
+// PowerSettingsController reads and changes pmset power management settings.
+type PowerSettingsController interface {
+	GetPowerSettings() (*PowerSettingsBySource, error)
+	// SetPowerSetting applies a validated pmset key/value to one profile.
+	SetPowerSetting(source PowerSource, key, value string) error
+}
+
+// GetPowerSettings returns the pmset settings of every power source profile.
+func (c *Client) GetPowerSettings() (*PowerSettingsBySource, error) {
+	return c.backend.GetPowerSettings()
+}
+
+// SetDisplaySleep sets the display sleep timer in minutes (0 = never).
+// Requires root privileges.
+func (c *Client) SetDisplaySleep(source PowerSource, minutes int) error {
+	return c.setPowerSetting("set display sleep", source, sysos.SettingDisplaySleep, strconv.Itoa(minutes))
+}
+
+// SetSystemSleep sets the system sleep timer in minutes (0 = never).
+// Requires root privileges.
+func (c *Client) SetSystemSleep(source PowerSource, minutes int) error {
+	return c.setPowerSetting("set system sleep", source, sysos.SettingSleep, strconv.Itoa(minutes))
+}
+
+// SetDiskSleep sets the disk spindown timer in minutes (0 = never).
+// Requires root privileges.
+func (c *Client) SetDiskSleep(source PowerSource, minutes int) error {
+	return c.setPowerSetting("set disk sleep", source, sysos.SettingDiskSleep, strconv.Itoa(minutes))
+}
+
+// SetPowerNap enables or disables Power Nap. Requires root privileges.
+func (c *Client) SetPowerNap(source PowerSource, enable bool) error {
+	return c.setPowerSetting("set power nap", source, sysos.SettingPowerNap, sysos.BoolValue(enable))
+}
+
+// SetTCPKeepAlive enables or disables TCP keepalive during sleep.
+// Requires root privileges.
+func (c *Client) SetTCPKeepAlive(source PowerSource, enable bool) error {
+	return c.setPowerSetting("set tcp keepalive", source, sysos.SettingTCPKeepAlive, sysos.BoolValue(enable))
+}
+
+// SetHibernateMode sets the hibernation mode: 0 (sleep only), 3 (safe
+// sleep) or 25 (hibernate). Requires root privileges.
+func (c *Client) SetHibernateMode(source PowerSource, mode int) error {
+	return c.setPowerSetting("set hibernate mode", source, sysos.SettingHibernateMode, strconv.Itoa(mode))
+}
+
+// setPowerSetting validates before the root check so bad arguments are
+// reported as such even to unprivileged callers.
+func (c *Client) setPowerSetting(op string, source PowerSource, key, value string) error {
+	if err := sysos.ValidatePowerSetting(key, value); err != nil {
+		return err
+	}
+	if err := c.requireRoot(op); err != nil {
+		return err
+	}
+	return c.auditedSetPowerSetting(op, source, key, value, func() error {
+		return c.backend.SetPowerSetting(source, key, value)
+	})
+}
+
+func newPowerSettingsBySource(s *sysos.CustomSettings) *PowerSettingsBySource {
+	return &PowerSettingsBySource{
+		Battery: newPowerSettings(s.Battery),
+		AC:      newPowerSettings(s.AC),
+		UPS:     newPowerSettings(s.UPS),
+	}
+}
+
+func newPowerSettings(p *sysos.PowerSettings) *PowerSettings {
+	if p == nil {
+		return nil
+	}
+	values := make(map[string]string, len(p.Values))
+	for k, v := range p.Values {
+		values[k] = v
+	}
+	return &PowerSettings{
+		DisplaySleep:  p.DisplaySleep,
+		Sleep:         p.Sleep,
+		DiskSleep:     p.DiskSleep,
+		PowerNap:      p.PowerNap,
+		HibernateMode: p.HibernateMode,
+		HibernateFile: p.HibernateFile,
+		Standby:       p.Standby,
+		TCPKeepAlive:  p.TCPKeepAlive,
+		LowPowerMode:  p.LowPowerMode,
+		PowerMode:     p.PowerMode,
+		WakeOnLAN:     p.WakeOnLAN,
+		LidWake:       p.LidWake,
+		ACWake:        p.ACWake,
+		TTYSKeepAwake: p.TTYSKeepAwake,
+		ProximityWake: p.ProximityWake,
+		Values:        values,
+	}
+}
@@ -0,0 +1,97 @@
+package powerkit
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPowerSettingSettersForwardToBackend(t *testing.T) {
+	type call struct {
+		source     PowerSource
+		key, value string
+	}
+	var calls []call
+	stub := &stubBackend{
+		setPowerSetting: func(source PowerSource, key, value string) error {
+			calls = append(calls, call{source, key, value})
+			return nil
+		},
+	}
+	client := New(WithBackend(stub), WithRootCheck(false))
+
+	if err := client.SetDisplaySleep(PowerSourceBattery, 3); err != nil {
+		t.Fatalf("SetDisplaySleep returned error: %v", err)
+	}
+	if err := client.SetPowerNap(PowerSourceAC, false); err != nil {
+		t.Fatalf("SetPowerNap returned error: %v", err)
+	}
+	want := []call{{PowerSourceBattery, "displaysleep", "3"}, {PowerSourceAC, "powernap", "0"}}
+	if len(calls) != len(want) || calls[0] != want[0] || calls[1] != want[1] {
+		t.Fatalf("expected %v, got %v", want, calls)
+	}
+
+	if err := client.SetHibernateMode(PowerSourceAll, 7); err == nil {
+		t.Fatalf("expected invalid hibernate mode to be rejected")
+	}
+	if err := client.SetSystemSleep(PowerSourceAll, -5); err == nil {
+		t.Fatalf("expected negative sleep timer to be rejected")
+	}
+	if len(calls) != 2 {
+		t.Fatalf("invalid settings should not reach the backend, got %v", calls)
+	}
+}
+
+func TestPowerSettingsNotSupportedWithoutController(t *testing.T) {
+	client := New(WithBackend(&CompositeBackend{}), WithRootCheck(false))
+	if _, err := client.GetPowerSettings(); !errors.Is(err, ErrNotSupported) {
+		t.Fatalf("expected ErrNotSupported, got %v", err)
+	}
+	if err := client.SetTCPKeepAlive(PowerSourceAll, true); !errors.Is(err, ErrNotSupported) {
+		t.Fatalf("expected ErrNotSupported, got %v", err)
+	}
+}
+
+func TestSetLowPowerModeForBatteryOnly(t *testing.T) {
+	var gotSource PowerSource
+	var gotKey, gotValue string
+	stub := &stubBackend{
+		setPowerSetting: func(source PowerSource, key, value string) error {
+			gotSource, gotKey, gotValue = source, key, value
+			return nil
+		},
+	}
+	if err := New(WithBackend(stub), WithRootCheck(false)).SetLowPowerModeFor(PowerSourceBattery, true); err != nil {
+		t.Fatalf("SetLowPowerModeFor returned error: %v", err)
+	}
+	if gotSource != PowerSourceBattery || gotKey != "lowpowermode" || gotValue != "1" {
+		t.Fatalf("expected battery lowpowermode 1, got %s %s %s", gotSource, gotKey, gotValue)
+	}
+}
+
+func TestGetSystemInfoReportsLowPowerModePerSource(t *testing.T) {
+	stub := &stubBackend{
+		fetchBattery: func(bool) (*RawBatteryData, error) { return &RawBatteryData{DesignCapacity: 5000}, nil },
+		lowPowerMode: func() (bool, bool, error) { return true, true, nil },
+		powerSettings: func() (*PowerSettingsBySource, error) {
+			return &PowerSettingsBySource{
+				Battery: &PowerSettings{LowPowerMode: true, Values: map[string]string{"lowpowermode": "1"}},
+				AC:      &PowerSettings{Values: map[string]string{"displaysleep": "10"}},
+			}, nil
+		},
+	}
+
+	info, err := New(WithBackend(stub)).GetSystemInfo(FetchOptions{QueryIOKit: true})
+	if err != nil {
+		t.Fatalf("GetSystemInfo returned error: %v", err)
+	}
+	lpm := info.OS.LowPowerMode
+	if !lpm.Battery.Available || !lpm.Battery.Enabled {
+		t.Fatalf("expected battery LPM enabled and available, got %+v", lpm.Battery)
+	}
+	if lpm.AC.Available || lpm.AC.Enabled {
+		t.Fatalf("expected AC LPM unavailable without a lowpowermode key, got %+v", lpm.AC)
+	}
+	if j := info.ToJSON().OS.LowPowerMode; !j.Battery.Enabled || j.AC.Available {
+		t.Fatalf("unexpected per-source JSON: %+v", j)
+	}
+}
@@ -1,24 +1,10 @@
-//go:build darwin
-
 package powerkit
 
-import (
-	"fmt"
-
-	sysos "github.com/peterneutron/powerkit-go/internal/os"
-	"github.com/peterneutron/powerkit-go/internal/powerd"
-	"github.com/peterneutron/powerkit-go/internal/smc"
-)
-
-var (
-	globalSleepStatusFn = powerd.GlobalSleepStatus
-	powerdIsActiveFn    = powerd.IsActive
-	getLowPowerModeFn   = sysos.GetLowPowerModeEnabled
-)
+import "fmt"
 
 // GetSystemInfo is the primary entrypoint to the library.
 // It acts as a high-level coordinator for fetching and processing data.
-func GetSystemInfo(opts ...FetchOptions) (*SystemInfo, error) {
+func (c *Client) GetSystemInfo(opts ...FetchOptions) (*SystemInfo, error) {
 	options := FetchOptions{QueryIOKit: true, QuerySMC: true}
 	if len(opts) > 0 {
 		options = opts[0]
@@ -27,45 +13,14 @@ func GetSystemInfo(opts ...FetchOptions) (*SystemInfo, error) {
 		return nil, fmt.Errorf("FetchOptions must specify at least one data source")
 	}
 
-	// Determine assertion-based sleep allowances (global + app-local)
-	sysAllowedGlobal, dspAllowedGlobal, gErr := globalSleepStatusFn()
-	dspActiveApp := powerdIsActiveFn(powerd.PreventDisplaySleep)
-	sysActiveApp := powerdIsActiveFn(powerd.PreventSystemSleep)
-	dspAllowedApp := !dspActiveApp
-	sysAllowedApp := !sysActiveApp && !dspActiveApp
-	// If global query failed, mirror app-local into global as a fallback
-	if gErr != nil {
-		dspAllowedGlobal = dspAllowedApp
-		sysAllowedGlobal = sysAllowedApp
-	}
-
-	// Read Low Power Mode state (cached)
-	lpmEnabled, lpmAvailable, _ := getLowPowerModeFn()
-
-	info := &SystemInfo{
-		OS: OSInfo{
-			Firmware:               currentSMCConfig.Firmware,
-			FirmwareVersion:        currentFirmwareInfo.Version,
-			FirmwareSource:         currentFirmwareInfo.Source,
-			FirmwareMajor:          currentFirmwareInfo.Major,
-			FirmwareCompatStatus:   firmwareCompatStatus(currentFirmwareInfo.Major),
-			FirmwareProfileID:      currentSMCConfig.FirmwareProfileID,
-			FirmwareProfileVersion: currentSMCConfig.FirmwareProfileVersion,
-			// Back-compat: mirror global values
-			GlobalSystemSleepAllowed:  sysAllowedGlobal,
-			GlobalDisplaySleepAllowed: dspAllowedGlobal,
-			AppSystemSleepAllowed:     sysAllowedApp,
-			AppDisplaySleepAllowed:    dspAllowedApp,
-			LowPowerMode:              LowPowerModeInfo{Enabled: lpmEnabled, Available: lpmAvailable},
-		},
-	}
+	info := &SystemInfo{OS: c.collectOSInfo()}
 	initSystemInfoMetadata(info)
 
 	if options.QueryIOKit {
-		getIOKitInfo(info, options)
+		c.getIOKitInfo(info, options)
 	}
 	if options.QuerySMC {
-		getSMCInfo(info)
+		c.getSMCInfo(info)
 	}
 
 	// Final check: if both failed, we have nothing to return.
@@ -78,27 +33,77 @@ func GetSystemInfo(opts ...FetchOptions) (*SystemInfo, error) {
 }
 
 // GetRawSMCValues allows advanced users to query custom SMC keys.
-// It returns a map of raw, undecoded values. The caller is responsible for
-// interpreting the bytes in the 'Data' field based on the 'DataType'.
-func GetRawSMCValues(keys []string) (map[string]RawSMCValue, error) {
-	// Call the new raw fetcher in our internal smc package
-	rawResults, err := smc.FetchRawData(keys)
+// It returns a map of raw values; Decoded is filled for known data types,
+// assuming Apple Silicon byte order. For other types the caller interprets
+// the bytes in the 'Data' field based on the 'DataType'.
+//
+// Keys may be glob patterns such as "T???", "TC*P" or "F[0-9]Ac", which
+// expand against the enumerated key set (see ListSMCKeys).
+func (c *Client) GetRawSMCValues(keys []string) (map[string]RawSMCValue, error) {
+	keys, err := c.expandSMCKeys(keys)
 	if err != nil {
 		return nil, err
 	}
+	values, err := c.backend.FetchRawData(keys)
+	if err != nil {
+		return nil, err
+	}
+	return withDecoded(values), nil
+}
 
-	// The internal smc.RawSMCValue struct is identical to our public one,
-	// but it's a best practice to convert between them to keep the packages decoupled.
-	// This also makes it easy to change the public API later without breaking the internal code.
-	results := make(map[string]RawSMCValue, len(rawResults))
-	for key := range rawResults {
-		val := rawResults[key]
-		results[key] = RawSMCValue{
-			DataType: val.DataType,
-			DataSize: val.DataSize,
-			Data:     val.Data,
-		}
+// ListSMCKeys enumerates every key the SMC exposes with its data type, size
+// and attribute byte. Enumeration reads each key's info and can take a few
+// seconds on hardware with thousands of keys.
+func (c *Client) ListSMCKeys() ([]SMCKeyInfo, error) {
+	keys, err := c.backend.ListSMCKeys()
+	if err != nil {
+		return nil, err
+	}
+	for i := range keys {
+		keys[i].Flags = DecodeSMCKeyAttributes(keys[i].Attributes)
+	}
+	return keys, nil
+}
+
+// collectOSInfo assembles the firmware, sleep assertion and Low Power Mode
+// view shared by GetSystemInfo and the event stream.
+func (c *Client) collectOSInfo() OSInfo {
+	backend := c.backend
+	firmware, cfg := c.profile()
+
+	// Determine assertion-based sleep allowances (global + app-local)
+	sysAllowedGlobal, dspAllowedGlobal, gErr := backend.GlobalSleepStatus()
+	_, dspActiveApp := backend.ActiveAssertion(AssertionTypePreventDisplaySleep)
+	_, sysActiveApp := backend.ActiveAssertion(AssertionTypePreventSystemSleep)
+	dspAllowedApp := !dspActiveApp
+	sysAllowedApp := !sysActiveApp && !dspActiveApp
+	// If global query failed, mirror app-local into global as a fallback
+	if gErr != nil {
+		dspAllowedGlobal = dspAllowedApp
+		sysAllowedGlobal = sysAllowedApp
 	}
 
-	return results, nil
+	// Read Low Power Mode state (cached)
+	lpmEnabled, lpmAvailable, _ := backend.GetLowPowerModeEnabled()
+	lpm := LowPowerModeInfo{Enabled: lpmEnabled, Available: lpmAvailable}
+	if settings, err := backend.GetPowerSettings(); err == nil {
+		lpm.Battery = lowPowerModeSource(settings.Battery)
+		lpm.AC = lowPowerModeSource(settings.AC)
+	}
+
+	return OSInfo{
+		Firmware:               cfg.Firmware,
+		FirmwareVersion:        firmware.Version,
+		FirmwareSource:         firmware.Source,
+		FirmwareMajor:          firmware.Major,
+		FirmwareCompatStatus:   c.profiles.compatStatus(firmware.Major),
+		FirmwareProfileID:      cfg.FirmwareProfileID,
+		FirmwareProfileVersion: cfg.FirmwareProfileVersion,
+		// Back-compat: mirror global values
+		GlobalSystemSleepAllowed:  sysAllowedGlobal,
+		GlobalDisplaySleepAllowed: dspAllowedGlobal,
+		AppSystemSleepAllowed:     sysAllowedApp,
+		AppDisplaySleepAllowed:    dspAllowedApp,
+		LowPowerMode:              lpm,
+	}
 }
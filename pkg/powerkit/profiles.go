@@ -0,0 +1,288 @@
+package powerkit
+
+import (
+	_ "embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/peterneutron/powerkit-go/internal/smc"
+)
+
+//go:embed profiles/builtin.json
+var builtinProfiles []byte
+
+// HexBytes is a byte string written in JSON as lowercase hex, for example
+// "01000000".
+type HexBytes []byte
+
+// MarshalJSON implements json.Marshaler.
+func (b HexBytes) MarshalJSON() ([]byte, error) {
+	return json.Marshal(hex.EncodeToString(b))
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (b *HexBytes) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	decoded, err := hex.DecodeString(s)
+	if err != nil {
+		return fmt.Errorf("invalid hex bytes %q: %w", s, err)
+	}
+	*b = decoded
+	return nil
+}
+
+// FirmwareRange is an inclusive range of firmware major versions. A zero Max
+// leaves the range open-ended.
+type FirmwareRange struct {
+	Min int `json:"min"`
+	Max int `json:"max,omitempty"`
+}
+
+func (r FirmwareRange) contains(major int) bool {
+	return major >= r.Min && (r.Max == 0 || major <= r.Max)
+}
+
+func (r FirmwareRange) span() int {
+	if r.Max == 0 {
+		return math.MaxInt
+	}
+	return r.Max - r.Min
+}
+
+// ProfileControl names the SMC keys of one control and the values that
+// enable and disable it.
+type ProfileControl struct {
+	Keys    []string `json:"keys"`
+	Enable  HexBytes `json:"enable"`
+	Disable HexBytes `json:"disable"`
+}
+
+// ProfileFan describes fan control: the per-fan mode key format, its manual
+// and automatic values, and an optional unlock key.
+type ProfileFan struct {
+	ModeKeyFormat string   `json:"mode_key_format"`
+	Manual        HexBytes `json:"manual"`
+	Auto          HexBytes `json:"auto"`
+	UnlockKey     string   `json:"unlock_key,omitempty"`
+}
+
+// FirmwareProfile defines the SMC keys and values used to control a range of
+// firmware versions.
+type FirmwareProfile struct {
+	ID      string `json:"id"`
+	Version int    `json:"version"`
+	// Mode is reported as os.firmware when the profile matches.
+	Mode           string          `json:"mode"`
+	FirmwareMajors []FirmwareRange `json:"firmware_majors"`
+	TestedMajors   []int           `json:"tested_majors,omitempty"`
+	// Fallback marks the profile used when no range matches.
+	Fallback bool `json:"fallback,omitempty"`
+
+	Adapter  ProfileControl `json:"adapter"`
+	Charging ProfileControl `json:"charging"`
+	LEDKey   string         `json:"led_key"`
+	Fan      ProfileFan     `json:"fan"`
+}
+
+// profileFile is the JSON layout of embedded and runtime profile files.
+type profileFile struct {
+	Profiles []FirmwareProfile `json:"profiles"`
+}
+
+// ProfileRegistry holds the firmware profiles a client resolves against.
+// It is safe for concurrent use.
+type ProfileRegistry struct {
+	mu       sync.RWMutex
+	profiles []FirmwareProfile
+}
+
+// NewProfileRegistry returns a registry holding the built-in profiles.
+func NewProfileRegistry() *ProfileRegistry {
+	var file profileFile
+	if err := json.Unmarshal(builtinProfiles, &file); err != nil {
+		panic(fmt.Sprintf("powerkit: invalid built-in profiles: %v", err))
+	}
+	return &ProfileRegistry{profiles: file.Profiles}
+}
+
+// defaultProfiles is shared by clients created without WithProfileRegistry.
+var defaultProfiles = NewProfileRegistry()
+
+// Load adds the profiles of a JSON profile file. A profile with the ID of
+// one already registered replaces it. The whole file is rejected if any
+// profile is invalid or no fallback profile would remain.
+func (r *ProfileRegistry) Load(rd io.Reader) error {
+	var file profileFile
+	dec := json.NewDecoder(rd)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&file); err != nil {
+		return fmt.Errorf("could not parse profile file: %w", err)
+	}
+	for _, p := range file.Profiles {
+		if err := p.validate(); err != nil {
+			return err
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	merged := append([]FirmwareProfile(nil), r.profiles...)
+	for _, p := range file.Profiles {
+		merged = mergeProfile(merged, p)
+	}
+	if !hasFallback(merged) {
+		return errors.New("profile file leaves no fallback profile")
+	}
+	r.profiles = merged
+	return nil
+}
+
+// LoadFile adds the profiles of the JSON file at path; see Load.
+func (r *ProfileRegistry) LoadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	if err := r.Load(f); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// Profiles returns the registered profiles in load order.
+func (r *ProfileRegistry) Profiles() []FirmwareProfile {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]FirmwareProfile(nil), r.profiles...)
+}
+
+// WritePolicy returns a write policy allowing exactly what the control APIs
+// write under each registered profile; see DefaultWritePolicy.
+func (r *ProfileRegistry) WritePolicy() *WritePolicy {
+	p := &WritePolicy{Profiles: map[string]map[string]KeyRule{
+		AnyProfile: {fanKeyPattern(smc.KeyFanTargetFormat): {Dangerous: true}},
+	}}
+	for _, profile := range r.Profiles() {
+		p.Profiles[profile.ID] = profileWriteRules(profileMatch{profile: profile}.config())
+	}
+	return p
+}
+
+// fallbackIndex returns the last registered fallback profile. Load keeps at
+// least one in the registry.
+func (r *ProfileRegistry) fallbackIndex() int {
+	for i := len(r.profiles) - 1; i >= 0; i-- {
+		if r.profiles[i].Fallback {
+			return i
+		}
+	}
+	return 0
+}
+
+func mergeProfile(profiles []FirmwareProfile, p FirmwareProfile) []FirmwareProfile {
+	for i := range profiles {
+		if profiles[i].ID == p.ID {
+			// Re-append so a replaced profile counts as loaded last.
+			profiles = append(profiles[:i], profiles[i+1:]...)
+			break
+		}
+	}
+	return append(profiles, p)
+}
+
+func hasFallback(profiles []FirmwareProfile) bool {
+	for _, p := range profiles {
+		if p.Fallback {
+			return true
+		}
+	}
+	return false
+}
+
+// validate checks that the profile can drive every control API.
+func (p FirmwareProfile) validate() error {
+	if p.ID == "" {
+		return errors.New("profile without id")
+	}
+	if err := p.validateControls(); err != nil {
+		return fmt.Errorf("profile %s: %w", p.ID, err)
+	}
+	return nil
+}
+
+func (p FirmwareProfile) validateControls() error {
+	if p.Version <= 0 {
+		return errors.New("version must be positive")
+	}
+	for _, rng := range p.FirmwareMajors {
+		if rng.Min <= 0 || (rng.Max != 0 && rng.Max < rng.Min) {
+			return fmt.Errorf("invalid firmware major range %d-%d", rng.Min, rng.Max)
+		}
+	}
+	if len(p.Adapter.Keys) != 1 {
+		return errors.New("adapter control needs exactly one key")
+	}
+	if err := p.Adapter.validate("adapter"); err != nil {
+		return err
+	}
+	if err := p.Charging.validate("charging"); err != nil {
+		return err
+	}
+	if !isSMCKeyName(p.LEDKey) {
+		return fmt.Errorf("invalid led_key %q", p.LEDKey)
+	}
+	return p.Fan.validate()
+}
+
+func (c ProfileControl) validate(name string) error {
+	if len(c.Keys) == 0 {
+		return fmt.Errorf("%s control has no keys", name)
+	}
+	for _, key := range c.Keys {
+		if !isSMCKeyName(key) {
+			return fmt.Errorf("invalid %s key %q", name, key)
+		}
+	}
+	if len(c.Enable) == 0 || len(c.Disable) == 0 {
+		return fmt.Errorf("%s control needs enable and disable values", name)
+	}
+	return nil
+}
+
+func (f ProfileFan) validate() error {
+	if strings.Count(f.ModeKeyFormat, "%d") != 1 || !isSMCKeyName(fmt.Sprintf(f.ModeKeyFormat, 0)) {
+		return fmt.Errorf("invalid fan mode_key_format %q", f.ModeKeyFormat)
+	}
+	if len(f.Manual) == 0 || len(f.Auto) == 0 {
+		return errors.New("fan control needs manual and auto values")
+	}
+	if f.UnlockKey != "" && !isSMCKeyName(f.UnlockKey) {
+		return fmt.Errorf("invalid fan unlock_key %q", f.UnlockKey)
+	}
+	return nil
+}
+
+// isSMCKeyName reports whether key is a four-character SMC key.
+func isSMCKeyName(key string) bool {
+	return len(key) == 4
+}
+
+// WithProfileRegistry resolves the client's firmware profile against
+// registry instead of the built-in profiles. Load profile files before the
+// client first resolves its profile.
+func WithProfileRegistry(registry *ProfileRegistry) Option {
+	return func(c *Client) {
+		c.profiles = registry
+	}
+}
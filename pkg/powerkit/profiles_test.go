@@ -0,0 +1,103 @@
+package powerkit
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const hotfixProfile = `{"profiles": [{
+	"id": "smc_profile_next",
+	"version": 2,
+	"mode": "Supported",
+	"firmware_majors": [{"min": 14000}],
+	"tested_majors": [14000],
+	"adapter": {"keys": ["CHIE"], "enable": "00", "disable": "08"},
+	"charging": {"keys": ["CHTE"], "enable": "00000000", "disable": "02000000"},
+	"led_key": "ACLC",
+	"fan": {"mode_key_format": "F%dMd", "manual": "01", "auto": "00", "unlock_key": "Ftst"}
+}]}`
+
+func TestProfileRegistryLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hotfix.json")
+	if err := os.WriteFile(path, []byte(hotfixProfile), 0o600); err != nil {
+		t.Fatalf("WriteFile returned error: %v", err)
+	}
+	registry := NewProfileRegistry()
+	if err := registry.LoadFile(path); err != nil {
+		t.Fatalf("LoadFile returned error: %v", err)
+	}
+
+	match := registry.resolve(14001)
+	if match.profile.ID != "smc_profile_next" || match.selection != ProfileSelectionMatch {
+		t.Fatalf("expected the hotfix profile, got %+v", match)
+	}
+	if !strings.Contains(match.rule, "smc_profile_next") {
+		t.Fatalf("expected the match rule to name the profile, got %q", match.rule)
+	}
+	if got := match.config().ChargingDisableBytes; !bytes.Equal(got, []byte{0x02, 0x00, 0x00, 0x00}) {
+		t.Fatalf("expected hotfix charging bytes, got % x", got)
+	}
+	if got := registry.compatStatus(14000); got != firmwareCompatTested {
+		t.Fatalf("compatStatus(14000) = %q, want %q", got, firmwareCompatTested)
+	}
+	if got := registry.resolve(FirmwareMajorVersionThreshold).profile.ID; got != profileModernID {
+		t.Fatalf("expected the narrower modern range to win, got %s", got)
+	}
+	if err := registry.WritePolicy().Check("smc_profile_next", "CHTE", []byte{0x02, 0x00, 0x00, 0x00}, false); err != nil {
+		t.Fatalf("expected the registry policy to cover the hotfix profile, got %v", err)
+	}
+}
+
+func TestProfileRegistryReplacesByID(t *testing.T) {
+	registry := NewProfileRegistry()
+	replacement := strings.Replace(hotfixProfile, `"smc_profile_next"`, `"smc_profile_legacy"`, 1)
+	if err := registry.Load(strings.NewReader(replacement)); err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if got := len(registry.Profiles()); got != 2 {
+		t.Fatalf("expected 2 profiles after replacement, got %d", got)
+	}
+	if got := registry.resolve(FirmwareMajorVersionThreshold - 1); got.selection != ProfileSelectionFallback {
+		t.Fatalf("expected legacy firmware to fall back once its profile moved, got %+v", got)
+	}
+}
+
+func TestProfileRegistryRejectsInvalidFiles(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+	}{
+		{name: "bad hex", file: strings.Replace(hotfixProfile, `"08"`, `"zz"`, 1)},
+		{name: "unknown field", file: strings.Replace(hotfixProfile, `"mode"`, `"modes"`, 1)},
+		{name: "bad key", file: strings.Replace(hotfixProfile, `"led_key": "ACLC"`, `"led_key": "ACL"`, 1)},
+		{name: "no version", file: strings.Replace(hotfixProfile, `"version": 2`, `"version": 0`, 1)},
+		{name: "bad fan format", file: strings.Replace(hotfixProfile, `"F%dMd"`, `"FMd"`, 1)},
+		{name: "drops fallback", file: strings.Replace(hotfixProfile, `"smc_profile_next"`, `"smc_profile_modern"`, 1)},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			registry := NewProfileRegistry()
+			if err := registry.Load(strings.NewReader(tc.file)); err == nil {
+				t.Fatalf("expected Load to fail")
+			}
+			if got := len(registry.Profiles()); got != 2 {
+				t.Fatalf("expected a rejected file to leave 2 profiles, got %d", got)
+			}
+		})
+	}
+}
+
+func TestClientUsesProfileRegistry(t *testing.T) {
+	registry := NewProfileRegistry()
+	if err := registry.Load(strings.NewReader(hotfixProfile)); err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	client := New(WithBackend(&stubBackend{firmware: FirmwareInfo{Major: 14000}}), WithProfileRegistry(registry))
+	if got := client.config().FirmwareProfileID; got != "smc_profile_next" {
+		t.Fatalf("expected client to resolve the hotfix profile, got %s", got)
+	}
+}
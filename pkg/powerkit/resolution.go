@@ -0,0 +1,78 @@
+package powerkit
+
+import (
+	"fmt"
+	"os"
+)
+
+// EnvFirmwareProfile names the environment variable that pins the firmware
+// profile by ID when no WithFirmwareProfile option is given.
+const EnvFirmwareProfile = "POWERKIT_FIRMWARE_PROFILE"
+
+// How a firmware profile was selected.
+const (
+	ProfileSelectionMatch    = "match"
+	ProfileSelectionFallback = "fallback"
+	ProfileSelectionOption   = "option"
+	ProfileSelectionEnv      = "environment"
+)
+
+// ProfileResolution explains which firmware profile a client uses and why.
+type ProfileResolution struct {
+	FirmwareVersion string `json:"firmware_version"`
+	FirmwareSource  string `json:"firmware_source"`
+	FirmwareMajor   int    `json:"firmware_major"`
+	CompatStatus    string `json:"compat_status"`
+	// Mode is the resolver mode reported as os.firmware.
+	Mode string `json:"mode"`
+	// Selection is one of the ProfileSelection constants and Rule describes
+	// the decision in words.
+	Selection string          `json:"selection"`
+	Rule      string          `json:"rule"`
+	Profile   FirmwareProfile `json:"profile"`
+}
+
+// WithFirmwareProfile pins the client to the registered profile with id
+// instead of resolving one from the firmware version. It takes precedence
+// over POWERKIT_FIRMWARE_PROFILE.
+func WithFirmwareProfile(id string) Option {
+	return func(c *Client) {
+		c.profileOverride = id
+	}
+}
+
+// resolveProfile applies a pinned profile or resolves one from major. An
+// unknown pinned ID resolves normally but returns ErrUnknownProfile, which
+// then fails every SMC write.
+func (c *Client) resolveProfile(major int) (profileMatch, error) {
+	id, selection := c.profileOverride, ProfileSelectionOption
+	if id == "" {
+		id, selection = os.Getenv(EnvFirmwareProfile), ProfileSelectionEnv
+	}
+	match := c.profiles.resolve(major)
+	if id == "" {
+		return match, nil
+	}
+	if pinned, ok := c.profiles.pinned(id, selection); ok {
+		return pinned, nil
+	}
+	return match, fmt.Errorf("%w: %s pinned by %s", ErrUnknownProfile, id, selection)
+}
+
+// ResolveProfile reports the firmware profile the client uses together with
+// the detected firmware and the rule that selected it. It returns the
+// automatic resolution and an error wrapping ErrUnknownProfile when the
+// pinned profile is not registered.
+func (c *Client) ResolveProfile() (*ProfileResolution, error) {
+	firmware, cfg := c.profile()
+	return &ProfileResolution{
+		FirmwareVersion: firmware.Version,
+		FirmwareSource:  firmware.Source,
+		FirmwareMajor:   firmware.Major,
+		CompatStatus:    c.profiles.compatStatus(firmware.Major),
+		Mode:            cfg.Firmware,
+		Selection:       c.profileMatch.selection,
+		Rule:            c.profileMatch.rule,
+		Profile:         c.profileMatch.profile,
+	}, c.profileErr
+}
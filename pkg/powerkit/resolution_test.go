@@ -0,0 +1,96 @@
+package powerkit
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestResolveProfileTrace(t *testing.T) {
+	firmware := FirmwareInfo{Version: "iBoot-13822.81.10", Source: "ioreg_device_tree", Major: FirmwareMajorVersionThreshold}
+	client := New(WithBackend(&stubBackend{firmware: firmware}))
+
+	got, err := client.ResolveProfile()
+	if err != nil {
+		t.Fatalf("ResolveProfile returned error: %v", err)
+	}
+	if got.FirmwareVersion != firmware.Version || got.FirmwareSource != firmware.Source || got.FirmwareMajor != firmware.Major {
+		t.Fatalf("unexpected firmware in resolution: %+v", got)
+	}
+	if got.Profile.ID != profileModernID || got.Selection != ProfileSelectionMatch || got.Mode != "Supported" {
+		t.Fatalf("unexpected profile selection: %+v", got)
+	}
+	if got.CompatStatus != firmwareCompatTested || got.Rule == "" {
+		t.Fatalf("expected a tested compat status and a rule, got %+v", got)
+	}
+}
+
+func TestResolveProfileOverride(t *testing.T) {
+	newer := FirmwareInfo{Major: FirmwareMajorVersionThreshold + 1}
+	tests := []struct {
+		name      string
+		env       string
+		opts      []Option
+		id        string
+		selection string
+	}{
+		{name: "automatic", id: profileModernID, selection: ProfileSelectionFallback},
+		{name: "option", opts: []Option{WithFirmwareProfile(profileLegacyID)}, id: profileLegacyID, selection: ProfileSelectionOption},
+		{name: "environment", env: profileLegacyID, id: profileLegacyID, selection: ProfileSelectionEnv},
+		{name: "option beats environment", env: profileLegacyID, opts: []Option{WithFirmwareProfile(profileModernID)}, id: profileModernID, selection: ProfileSelectionOption},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(EnvFirmwareProfile, tc.env)
+			client := New(append([]Option{WithBackend(&stubBackend{firmware: newer})}, tc.opts...)...)
+			got, err := client.ResolveProfile()
+			if err != nil {
+				t.Fatalf("ResolveProfile returned error: %v", err)
+			}
+			if got.Profile.ID != tc.id || got.Selection != tc.selection {
+				t.Fatalf("resolved %s by %s, want %s by %s", got.Profile.ID, got.Selection, tc.id, tc.selection)
+			}
+			if got.CompatStatus != firmwareCompatUntestedNew {
+				t.Fatalf("expected the compat status of the detected firmware, got %q", got.CompatStatus)
+			}
+		})
+	}
+}
+
+func TestPinnedLegacyProfileWritesLegacyKeys(t *testing.T) {
+	m := &memorySMC{values: map[string][]byte{"BCLM": {0x00}, "BCDS": {0x00}}}
+	client := New(
+		WithBackend(m.backend(FirmwareInfo{Major: FirmwareMajorVersionThreshold + 1})),
+		WithRootCheck(false),
+		WithFirmwareProfile(profileLegacyID),
+	)
+	if err := client.SetChargingState(ChargingActionOff); err != nil {
+		t.Fatalf("SetChargingState returned error: %v", err)
+	}
+	if len(m.writes) != 2 || m.writes[0] != "BCLM" || m.writes[1] != "BCDS" {
+		t.Fatalf("expected legacy charging writes, got %v", m.writes)
+	}
+}
+
+func TestUnknownPinnedProfileBlocksWrites(t *testing.T) {
+	m := &memorySMC{values: map[string][]byte{"CHTE": {0x00, 0x00, 0x00, 0x00}}}
+	client := New(
+		WithBackend(m.backend(FirmwareInfo{Major: FirmwareMajorVersionThreshold})),
+		WithRootCheck(false),
+		WithFirmwareProfile("smc_profile_missing"),
+	)
+
+	got, err := client.ResolveProfile()
+	if !errors.Is(err, ErrUnknownProfile) {
+		t.Fatalf("expected ErrUnknownProfile, got %v", err)
+	}
+	if got.Profile.ID != profileModernID || got.Selection != ProfileSelectionMatch {
+		t.Fatalf("expected the automatic resolution alongside the error, got %+v", got)
+	}
+	if err := client.SetChargingState(ChargingActionOff); !errors.Is(err, ErrUnknownProfile) {
+		t.Fatalf("expected writes to fail with ErrUnknownProfile, got %v", err)
+	}
+	if len(m.writes) != 0 {
+		t.Fatalf("expected no SMC writes, got %v", m.writes)
+	}
+}
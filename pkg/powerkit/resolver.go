@@ -1,11 +1,6 @@
-//go:build darwin
-
 package powerkit
 
-import (
-	"github.com/peterneutron/powerkit-go/internal/os"
-	"github.com/peterneutron/powerkit-go/internal/smc"
-)
+import "fmt"
 
 // FirmwareMajorVersionThreshold is the major version of the System Firmware
 // where the new SMC keys were introduced. As of August 2025, this is 13822.
@@ -30,11 +25,25 @@ type smcControlConfig struct {
 	ChargingKeysLegacy   []string
 	ChargingEnableBytes  []byte
 	ChargingDisableBytes []byte
+
+	// MagSafe LED Control
+	LEDKey string
+
+	// Fan Control. FanUnlockKey, when set, is written 1 before forcing a
+	// fan and 0 once every fan is back in automatic mode.
+	FanModeKeyFormat string
+	FanManualBytes   []byte
+	FanAutoBytes     []byte
+	FanUnlockKey     string
 }
 
-// currentSMCConfig is a package-level variable holding the correct configuration for the running OS.
-var currentSMCConfig smcControlConfig
-var currentFirmwareInfo os.FirmwareInfo
+// controlKeys returns the adapter and charging keys the profile writes.
+func (cfg smcControlConfig) controlKeys() []string {
+	if cfg.IsLegacyCharging {
+		return append([]string{cfg.AdapterKey}, cfg.ChargingKeysLegacy...)
+	}
+	return []string{cfg.AdapterKey, cfg.ChargingKeyModern}
+}
 
 const (
 	firmwareCompatTested      = "tested"
@@ -43,75 +52,117 @@ const (
 	firmwareCompatUnknown     = "unknown"
 	profileModernID           = "smc_profile_modern"
 	profileLegacyID           = "smc_profile_legacy"
-	defaultProfileVersion     = 1
+	firmwareSourceUnknown     = "unknown"
+	firmwareModeUnknown       = "Unknown (using latest known behavior)"
 )
 
-// The init function runs once when the package is imported.
-// It resolves which set of SMC keys and values to use based on the firmware version.
-func init() {
-	currentFirmwareInfo = os.GetFirmwareInfo()
-	firmwareVersion := currentFirmwareInfo.Major
-
-	switch {
-	case firmwareVersion == FirmwareMajorVersionThreshold:
-		// --- Supported Configuration ---
-		// This is the specific firmware version we have tested and know works.
-		currentSMCConfig = smcControlConfig{
-			Firmware:               "Supported",
-			FirmwareProfileID:      profileModernID,
-			FirmwareProfileVersion: defaultProfileVersion,
-			AdapterKey:             smc.KeyIsAdapterEnabled,
-			AdapterEnableBytes:     []byte{0x00},
-			AdapterDisableBytes:    []byte{0x08},
-			IsLegacyCharging:       false,
-			ChargingKeyModern:      smc.KeyIsChargingEnabled,
-			ChargingEnableBytes:    []byte{0x00, 0x00, 0x00, 0x00},
-			ChargingDisableBytes:   []byte{0x01, 0x00, 0x00, 0x00},
-		}
+// profileMatch is the outcome of resolving a firmware profile: the chosen
+// profile, how it was selected and the rule that selected it.
+type profileMatch struct {
+	profile   FirmwareProfile
+	selection string
+	rule      string
+}
+
+// resolve picks the profile for a firmware major. Among profiles whose
+// ranges contain major the narrowest range wins, and on a tie the profile
+// loaded last. Without a match the fallback profile is used.
+func (r *ProfileRegistry) resolve(major int) profileMatch {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 
-	case firmwareVersion > 0 && firmwareVersion < FirmwareMajorVersionThreshold:
-		// --- Legacy Configuration ---
-		// This applies to all known firmwares before the threshold.
-		currentSMCConfig = smcControlConfig{
-			Firmware:               "Legacy",
-			FirmwareProfileID:      profileLegacyID,
-			FirmwareProfileVersion: defaultProfileVersion,
-			AdapterKey:             smc.KeyIsAdapterEnabledLegacy,
-			AdapterEnableBytes:     []byte{0x00},
-			AdapterDisableBytes:    []byte{0x01},
-			IsLegacyCharging:       true,
-			ChargingKeysLegacy:     []string{smc.KeyIsChargingEnabledLegacyBCLM, smc.KeyIsChargingEnabledLegacyBCDS},
-			ChargingEnableBytes:    []byte{0x00},
-			ChargingDisableBytes:   []byte{0x02},
+	best, bestSpan := -1, 0
+	for i, p := range r.profiles {
+		for _, rng := range p.FirmwareMajors {
+			if !rng.contains(major) {
+				continue
+			}
+			if span := rng.span(); best < 0 || span <= bestSpan {
+				best, bestSpan = i, span
+			}
+		}
+	}
+	if best >= 0 {
+		p := r.profiles[best]
+		return profileMatch{
+			profile:   p,
+			selection: ProfileSelectionMatch,
+			rule:      fmt.Sprintf("firmware major %d is in the range of profile %s", major, p.ID),
 		}
+	}
 
-	default:
-		// --- Unknown Configuration ---
-		// We set the Mode to "Unknown" but use the modern keys as a safe, forward-looking guess.
-		currentSMCConfig = smcControlConfig{
-			Firmware:               "Unknown (using latest known behavior)",
-			FirmwareProfileID:      profileModernID,
-			FirmwareProfileVersion: defaultProfileVersion,
-			AdapterKey:             smc.KeyIsAdapterEnabled,
-			AdapterEnableBytes:     []byte{0x00},
-			AdapterDisableBytes:    []byte{0x08},
-			IsLegacyCharging:       false,
-			ChargingKeyModern:      smc.KeyIsChargingEnabled,
-			ChargingEnableBytes:    []byte{0x00, 0x00, 0x00, 0x00},
-			ChargingDisableBytes:   []byte{0x01, 0x00, 0x00, 0x00},
+	p := r.profiles[r.fallbackIndex()]
+	return profileMatch{
+		profile:   p,
+		selection: ProfileSelectionFallback,
+		rule:      fmt.Sprintf("no profile covers firmware major %d; using fallback profile %s", major, p.ID),
+	}
+}
+
+// pinned returns the profile with id, selected by an override.
+func (r *ProfileRegistry) pinned(id, selection string) (profileMatch, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, p := range r.profiles {
+		if p.ID == id {
+			return profileMatch{profile: p, selection: selection, rule: fmt.Sprintf("profile %s pinned by %s", id, selection)}, true
 		}
 	}
+	return profileMatch{}, false
 }
 
-func firmwareCompatStatus(major int) string {
-	switch {
-	case major == FirmwareMajorVersionThreshold:
-		return firmwareCompatTested
-	case major > FirmwareMajorVersionThreshold:
-		return firmwareCompatUntestedNew
-	case major > 0 && major < FirmwareMajorVersionThreshold:
-		return firmwareCompatUntestedOld
-	default:
+// config converts the match into the control configuration used by writes.
+func (m profileMatch) config() smcControlConfig {
+	p := m.profile
+	cfg := smcControlConfig{
+		Firmware:               p.Mode,
+		FirmwareProfileID:      p.ID,
+		FirmwareProfileVersion: p.Version,
+		AdapterKey:             p.Adapter.Keys[0],
+		AdapterEnableBytes:     p.Adapter.Enable,
+		AdapterDisableBytes:    p.Adapter.Disable,
+		ChargingEnableBytes:    p.Charging.Enable,
+		ChargingDisableBytes:   p.Charging.Disable,
+		LEDKey:                 p.LEDKey,
+		FanModeKeyFormat:       p.Fan.ModeKeyFormat,
+		FanManualBytes:         p.Fan.Manual,
+		FanAutoBytes:           p.Fan.Auto,
+		FanUnlockKey:           p.Fan.UnlockKey,
+	}
+	if m.selection == ProfileSelectionFallback {
+		cfg.Firmware = firmwareModeUnknown
+	}
+	// Multi-key charging control is written as one unit.
+	if len(p.Charging.Keys) > 1 {
+		cfg.IsLegacyCharging = true
+		cfg.ChargingKeysLegacy = p.Charging.Keys
+	} else {
+		cfg.ChargingKeyModern = p.Charging.Keys[0]
+	}
+	return cfg
+}
+
+// compatStatus reports whether major was tested by any profile, or is newer
+// or older than every tested major.
+func (r *ProfileRegistry) compatStatus(major int) string {
+	if major <= 0 {
 		return firmwareCompatUnknown
 	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	newestTested := 0
+	for _, p := range r.profiles {
+		for _, tested := range p.TestedMajors {
+			if tested == major {
+				return firmwareCompatTested
+			}
+			newestTested = max(newestTested, tested)
+		}
+	}
+	if newestTested > 0 && major > newestTested {
+		return firmwareCompatUntestedNew
+	}
+	return firmwareCompatUntestedOld
 }
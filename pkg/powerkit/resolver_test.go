@@ -1,5 +1,3 @@
-//go:build darwin
-
 package powerkit
 
 import "testing"
@@ -19,8 +17,36 @@ func TestFirmwareCompatStatus(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			if got := firmwareCompatStatus(tc.major); got != tc.want {
-				t.Fatalf("firmwareCompatStatus(%d) = %q, want %q", tc.major, got, tc.want)
+			if got := NewProfileRegistry().compatStatus(tc.major); got != tc.want {
+				t.Fatalf("compatStatus(%d) = %q, want %q", tc.major, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestResolveBuiltinProfiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		major   int
+		id      string
+		mode    string
+		legacy  bool
+		adapter string
+	}{
+		{name: "tested", major: FirmwareMajorVersionThreshold, id: profileModernID, mode: "Supported", adapter: "CHIE"},
+		{name: "older", major: FirmwareMajorVersionThreshold - 1, id: profileLegacyID, mode: "Legacy", legacy: true, adapter: "CH0B"},
+		{name: "newer", major: FirmwareMajorVersionThreshold + 1, id: profileModernID, mode: firmwareModeUnknown, adapter: "CHIE"},
+		{name: "unknown", major: 0, id: profileModernID, mode: firmwareModeUnknown, adapter: "CHIE"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := NewProfileRegistry().resolve(tc.major).config()
+			if cfg.FirmwareProfileID != tc.id || cfg.Firmware != tc.mode {
+				t.Fatalf("resolved %s (%s), want %s (%s)", cfg.FirmwareProfileID, cfg.Firmware, tc.id, tc.mode)
+			}
+			if cfg.IsLegacyCharging != tc.legacy || cfg.AdapterKey != tc.adapter {
+				t.Fatalf("unexpected control keys: %+v", cfg)
 			}
 		})
 	}
@@ -0,0 +1,107 @@
+package powerkit
+
+import (
+	"fmt"
+
+	"github.com/peterneutron/powerkit-go/internal/smc"
+)
+
+// SensorCategory groups sensors by what they measure.
+type SensorCategory string
+
+// Sensor categories.
+const (
+	SensorTemperature SensorCategory = SensorCategory(smc.CategoryTemperature)
+	SensorFan         SensorCategory = SensorCategory(smc.CategoryFan)
+	SensorVoltage     SensorCategory = SensorCategory(smc.CategoryVoltage)
+	SensorCurrent     SensorCategory = SensorCategory(smc.CategoryCurrent)
+	SensorPower       SensorCategory = SensorCategory(smc.CategoryPower)
+)
+
+// SensorInfo describes a catalog sensor.
+type SensorInfo struct {
+	Key      string         `json:"Key"`
+	Name     string         `json:"Name"`
+	Category SensorCategory `json:"Category"`
+	Unit     string         `json:"Unit"`
+}
+
+// SensorReading is a labelled, scaled sensor value.
+type SensorReading struct {
+	SensorInfo
+	Value float64 `json:"Value"`
+}
+
+// SensorCatalog returns the curated sensors in the given categories, or all
+// of them when none are given. Not every sensor exists on every model.
+func SensorCatalog(categories ...SensorCategory) ([]SensorInfo, error) {
+	sensors, err := catalogSensors(categories)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]SensorInfo, len(sensors))
+	for i, s := range sensors {
+		out[i] = newSensorInfo(s)
+	}
+	return out, nil
+}
+
+// GetSensors reads the catalog sensors in the given categories, or all of
+// them when none are given. Sensors missing on this machine, and values that
+// are not numeric, are omitted. Readings follow catalog order.
+func (c *Client) GetSensors(categories ...SensorCategory) ([]SensorReading, error) {
+	sensors, err := catalogSensors(categories)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, len(sensors))
+	for i, s := range sensors {
+		keys[i] = s.Key
+	}
+
+	values, err := c.backend.FetchRawData(keys)
+	if err != nil {
+		return nil, err
+	}
+
+	readings := make([]SensorReading, 0, len(values))
+	for _, s := range sensors {
+		raw, ok := values[s.Key]
+		if !ok {
+			continue
+		}
+		decoded, err := raw.Decode()
+		if err != nil || decoded.Kind != SMCValueNumber {
+			continue
+		}
+		readings = append(readings, SensorReading{SensorInfo: newSensorInfo(s), Value: truncate(decoded.Number * s.Scale)})
+	}
+	return readings, nil
+}
+
+// catalogSensors filters the catalog by category, rejecting unknown ones.
+func catalogSensors(categories []SensorCategory) ([]smc.Sensor, error) {
+	if len(categories) == 0 {
+		return smc.Sensors, nil
+	}
+	wanted := make(map[smc.SensorCategory]bool, len(categories))
+	for _, category := range categories {
+		switch category {
+		case SensorTemperature, SensorFan, SensorVoltage, SensorCurrent, SensorPower:
+			wanted[smc.SensorCategory(category)] = true
+		default:
+			return nil, fmt.Errorf("unknown sensor category %q", category)
+		}
+	}
+	var sensors []smc.Sensor
+	for _, s := range smc.Sensors {
+		if wanted[s.Category] {
+			sensors = append(sensors, s)
+		}
+	}
+	return sensors, nil
+}
+
+func newSensorInfo(s smc.Sensor) SensorInfo {
+	return SensorInfo{Key: s.Key, Name: s.Name, Category: SensorCategory(s.Category), Unit: s.Unit}
+}
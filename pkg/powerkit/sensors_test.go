@@ -0,0 +1,64 @@
+package powerkit
+
+import (
+	"testing"
+
+	"github.com/peterneutron/powerkit-go/internal/smc"
+)
+
+func TestGetSensorsScalesAndFilters(t *testing.T) {
+	var requested []string
+	stub := &stubBackend{
+		fetchSMCRaw: func(keys []string) (map[string]RawSMCValue, error) {
+			requested = keys
+			return map[string]RawSMCValue{
+				"TB0T":                {DataType: "flt ", DataSize: 4, Data: []byte{0x00, 0x00, 0xf4, 0x41}}, // 30.5
+				smc.KeyBatteryVoltage: {DataType: "si16", DataSize: 2, Data: []byte{0xd4, 0x30}},             // 12500 mV
+				"Tp01":                {DataType: "hex_", DataSize: 1, Data: []byte{0x01}},
+			}, nil
+		},
+	}
+	client := New(WithBackend(stub))
+
+	readings, err := client.GetSensors(SensorTemperature, SensorVoltage)
+	if err != nil {
+		t.Fatalf("GetSensors returned error: %v", err)
+	}
+	for _, key := range requested {
+		if key == smc.KeyAdapterCurrent || key == "F0Ac" {
+			t.Fatalf("requested sensor %s outside the selected categories", key)
+		}
+	}
+	if len(readings) != 2 {
+		t.Fatalf("expected TB0T and B0AV readings, got %+v", readings)
+	}
+	if r := readings[0]; r.Key != "TB0T" || r.Value != 30.5 || r.Unit != "°C" || r.Category != SensorTemperature {
+		t.Fatalf("unexpected battery temperature reading: %+v", r)
+	}
+	if r := readings[1]; r.Key != smc.KeyBatteryVoltage || r.Value != 12.5 || r.Unit != "V" {
+		t.Fatalf("unexpected battery voltage reading: %+v", r)
+	}
+
+	if _, err := client.GetSensors("humidity"); err == nil {
+		t.Fatalf("expected error for an unknown category")
+	}
+}
+
+func TestSensorCatalogFiltersByCategory(t *testing.T) {
+	all, err := SensorCatalog()
+	if err != nil {
+		t.Fatalf("SensorCatalog returned error: %v", err)
+	}
+	fans, err := SensorCatalog(SensorFan)
+	if err != nil {
+		t.Fatalf("SensorCatalog returned error: %v", err)
+	}
+	if len(fans) == 0 || len(fans) >= len(all) {
+		t.Fatalf("expected a strict subset of fan sensors, got %d of %d", len(fans), len(all))
+	}
+	for _, s := range fans {
+		if s.Category != SensorFan {
+			t.Fatalf("unexpected category in fan catalog: %+v", s)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package powerkit
+
+// SMC key attribute bits, as reported in SMCKeyInfo.Attributes.
+const (
+	SMCAttrPrivateWrite uint8 = 0x01
+	SMCAttrPrivateRead  uint8 = 0x02
+	SMCAttrAtomic       uint8 = 0x04
+	SMCAttrConst        uint8 = 0x08
+	SMCAttrFunction     uint8 = 0x10
+	SMCAttrWrite        uint8 = 0x40
+	SMCAttrRead         uint8 = 0x80
+)
+
+// SMCKeyFlags is the decoded form of an SMC key's attribute byte.
+type SMCKeyFlags struct {
+	Readable     bool `json:"Readable"`
+	Writable     bool `json:"Writable"`
+	Function     bool `json:"Function"`
+	Const        bool `json:"Const"`
+	Atomic       bool `json:"Atomic"`
+	PrivateRead  bool `json:"PrivateRead"`
+	PrivateWrite bool `json:"PrivateWrite"`
+}
+
+// DecodeSMCKeyAttributes decodes an SMC attribute byte into flags.
+func DecodeSMCKeyAttributes(attrs uint8) SMCKeyFlags {
+	return SMCKeyFlags{
+		Readable:     attrs&SMCAttrRead != 0,
+		Writable:     attrs&SMCAttrWrite != 0,
+		Function:     attrs&SMCAttrFunction != 0,
+		Const:        attrs&SMCAttrConst != 0,
+		Atomic:       attrs&SMCAttrAtomic != 0,
+		PrivateRead:  attrs&SMCAttrPrivateRead != 0,
+		PrivateWrite: attrs&SMCAttrPrivateWrite != 0,
+	}
+}
+
+// GetSMCKeyInfo returns the data type, size and attribute flags of the given
+// keys without reading their values. Missing keys are omitted. Use it to
+// check Flags.Writable before writing a key.
+func (c *Client) GetSMCKeyInfo(keys []string) (map[string]SMCKeyInfo, error) {
+	infos, err := c.backend.FetchKeyInfo(keys)
+	if err != nil {
+		return nil, err
+	}
+	for key, info := range infos {
+		info.Flags = DecodeSMCKeyAttributes(info.Attributes)
+		infos[key] = info
+	}
+	return infos, nil
+}
+
+// controlKeysWritable reports whether every SMC key the control profile
+// writes exists and carries the write attribute.
+func (c *Client) controlKeysWritable(cfg smcControlConfig) bool {
+	keys := cfg.controlKeys()
+	infos, err := c.backend.FetchKeyInfo(keys)
+	if err != nil {
+		return false
+	}
+	for _, key := range keys {
+		info, ok := infos[key]
+		if !ok || info.Attributes&SMCAttrWrite == 0 {
+			return false
+		}
+	}
+	return true
+}
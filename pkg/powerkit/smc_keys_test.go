@@ -0,0 +1,69 @@
+package powerkit
+
+import "testing"
+
+func TestDecodeSMCKeyAttributes(t *testing.T) {
+	flags := DecodeSMCKeyAttributes(0xd4)
+	want := SMCKeyFlags{Readable: true, Writable: true, Function: true, Atomic: true}
+	if flags != want {
+		t.Fatalf("expected %+v, got %+v", want, flags)
+	}
+	if flags := DecodeSMCKeyAttributes(0); flags != (SMCKeyFlags{}) {
+		t.Fatalf("expected no flags for zero attributes, got %+v", flags)
+	}
+}
+
+func TestGetSMCKeyInfoDecodesFlags(t *testing.T) {
+	stub := &stubBackend{
+		fetchKeyInfo: func([]string) (map[string]SMCKeyInfo, error) {
+			return map[string]SMCKeyInfo{
+				"CHTE": {Key: "CHTE", DataType: "ui32", DataSize: 4, Attributes: SMCAttrRead | SMCAttrWrite},
+			}, nil
+		},
+	}
+	infos, err := New(WithBackend(stub)).GetSMCKeyInfo([]string{"CHTE"})
+	if err != nil {
+		t.Fatalf("GetSMCKeyInfo returned error: %v", err)
+	}
+	if got := infos["CHTE"].Flags; !got.Readable || !got.Writable || got.Function {
+		t.Fatalf("unexpected CHTE flags: %+v", got)
+	}
+}
+
+func TestCanWriteSMCFollowsControlKeyAttributes(t *testing.T) {
+	tests := []struct {
+		name  string
+		attrs map[string]uint8
+		want  bool
+	}{
+		{"all writable", map[string]uint8{"CHTE": SMCAttrRead | SMCAttrWrite, "CHIE": SMCAttrWrite}, true},
+		{"read-only key", map[string]uint8{"CHTE": SMCAttrRead, "CHIE": SMCAttrWrite}, false},
+		{"missing key", map[string]uint8{"CHTE": SMCAttrWrite}, false},
+		{"no key info", nil, false},
+	}
+	for _, tt := range tests {
+		stub := &stubBackend{
+			firmware:    FirmwareInfo{Major: FirmwareMajorVersionThreshold},
+			fetchSMC:    func([]string) (map[string]float64, error) { return map[string]float64{}, nil },
+			fetchSMCRaw: func([]string) (map[string]RawSMCValue, error) { return map[string]RawSMCValue{}, nil },
+		}
+		if tt.attrs != nil {
+			stub.fetchKeyInfo = func(keys []string) (map[string]SMCKeyInfo, error) {
+				infos := map[string]SMCKeyInfo{}
+				for _, key := range keys {
+					if attrs, ok := tt.attrs[key]; ok {
+						infos[key] = SMCKeyInfo{Key: key, Attributes: attrs}
+					}
+				}
+				return infos, nil
+			}
+		}
+		info, err := New(WithBackend(stub)).GetSystemInfo(FetchOptions{QuerySMC: true})
+		if err != nil {
+			t.Fatalf("%s: GetSystemInfo returned error: %v", tt.name, err)
+		}
+		if got := info.ToJSON().Controls.Capabilities.CanWriteSMC; got != tt.want {
+			t.Fatalf("%s: expected can_write_smc=%v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
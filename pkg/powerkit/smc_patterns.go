@@ -0,0 +1,81 @@
+package powerkit
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// isSMCKeyPattern reports whether key contains glob metacharacters.
+func isSMCKeyPattern(key string) bool {
+	return strings.ContainsAny(key, "*?[")
+}
+
+// expandSMCKeys replaces glob patterns (T???, TC*P, F[0-9]Ac) with the
+// matching keys from the SMC key table, in table order. Literal keys are
+// passed through unchanged and duplicates are dropped.
+func (c *Client) expandSMCKeys(keys []string) ([]string, error) {
+	hasPattern := false
+	for _, key := range keys {
+		if isSMCKeyPattern(key) {
+			if _, err := path.Match(key, ""); err != nil {
+				return nil, fmt.Errorf("invalid SMC key pattern %q: %w", key, err)
+			}
+			hasPattern = true
+		}
+	}
+	if !hasPattern {
+		return keys, nil
+	}
+
+	names, err := c.smcKeyNames()
+	if err != nil {
+		return nil, fmt.Errorf("failed to enumerate SMC keys for pattern expansion: %w", err)
+	}
+
+	seen := make(map[string]bool, len(keys))
+	expanded := make([]string, 0, len(keys))
+	add := func(key string) {
+		if !seen[key] {
+			seen[key] = true
+			expanded = append(expanded, key)
+		}
+	}
+	for _, key := range keys {
+		if !isSMCKeyPattern(key) {
+			add(key)
+			continue
+		}
+		for _, name := range names {
+			if ok, _ := path.Match(key, name); ok {
+				add(name)
+			}
+		}
+	}
+	if len(expanded) == 0 {
+		return nil, fmt.Errorf("no SMC keys match %s", strings.Join(keys, " "))
+	}
+	return expanded, nil
+}
+
+// smcKeyNames returns the enumerated key names. The key table is fixed by
+// the SMC firmware, so a successful enumeration is cached for the life of
+// the client.
+func (c *Client) smcKeyNames() ([]string, error) {
+	c.keyNamesMu.Lock()
+	defer c.keyNamesMu.Unlock()
+	if c.keyNames != nil {
+		return c.keyNames, nil
+	}
+
+	keys, err := c.backend.ListSMCKeys()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(keys))
+	for i, k := range keys {
+		names[i] = k.Key
+	}
+	c.keyNames = names
+	return names, nil
+}
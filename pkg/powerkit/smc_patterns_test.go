@@ -0,0 +1,58 @@
+package powerkit
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestGetRawSMCValuesExpandsPatterns(t *testing.T) {
+	listCalls := 0
+	var requested []string
+	stub := &stubBackend{
+		listSMCKeys: func() ([]SMCKeyInfo, error) {
+			listCalls++
+			return []SMCKeyInfo{{Key: "F0Ac"}, {Key: "F1Ac"}, {Key: "F0Tg"}, {Key: "TC0P"}, {Key: "TC1C"}, {Key: "TB0T"}}, nil
+		},
+		fetchSMCRaw: func(keys []string) (map[string]RawSMCValue, error) {
+			requested = keys
+			return map[string]RawSMCValue{}, nil
+		},
+	}
+	client := New(WithBackend(stub))
+
+	tests := []struct {
+		keys []string
+		want []string
+	}{
+		{[]string{"F[0-9]Ac"}, []string{"F0Ac", "F1Ac"}},
+		{[]string{"TC*P", "B0AV"}, []string{"TC0P", "B0AV"}},
+		{[]string{"T???", "TC0P"}, []string{"TC0P", "TC1C", "TB0T"}},
+		{[]string{"B0AV"}, []string{"B0AV"}},
+	}
+	for _, tt := range tests {
+		if _, err := client.GetRawSMCValues(tt.keys); err != nil {
+			t.Fatalf("%v: unexpected error: %v", tt.keys, err)
+		}
+		if !reflect.DeepEqual(requested, tt.want) {
+			t.Fatalf("%v: expected %v, got %v", tt.keys, tt.want, requested)
+		}
+	}
+	if listCalls != 1 {
+		t.Fatalf("expected the key table to be enumerated once, got %d", listCalls)
+	}
+
+	if _, err := client.GetRawSMCValues([]string{"Z*"}); err == nil {
+		t.Fatalf("expected error when no key matches")
+	}
+	if _, err := client.GetRawSMCValues([]string{"T[0-"}); err == nil {
+		t.Fatalf("expected error for a malformed pattern")
+	}
+}
+
+func TestGetRawSMCValuesPatternNeedsCatalog(t *testing.T) {
+	stub := &stubBackend{fetchSMCRaw: func([]string) (map[string]RawSMCValue, error) { return map[string]RawSMCValue{}, nil }}
+	if _, err := New(WithBackend(stub)).GetRawSMCValues([]string{"T*"}); !errors.Is(err, ErrNotSupported) {
+		t.Fatalf("expected ErrNotSupported without a key catalog, got %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+package powerkit
+
+import (
+	"encoding/binary"
+
+	"github.com/peterneutron/powerkit-go/internal/smc"
+)
+
+// SMCValueKind classifies a decoded SMC value.
+type SMCValueKind string
+
+const (
+	// SMCValueNumber values carry their value in Number.
+	SMCValueNumber SMCValueKind = "number"
+	// SMCValueBool values carry the raw flag byte in Number; String is "true"
+	// for any non-zero byte, which is the boolean reading.
+	SMCValueBool SMCValueKind = "bool"
+	// SMCValueString values are text (ch8*).
+	SMCValueString SMCValueKind = "string"
+	// SMCValueBytes values are opaque (hex_).
+	SMCValueBytes SMCValueKind = "bytes"
+	// SMCValueStruct values carry their members in Fields ({fds, {pwm).
+	SMCValueStruct SMCValueKind = "struct"
+)
+
+// DecodedSMCValue is a typed SMC value. String is set for every kind: the
+// exact decimal form for numbers (so 64-bit integers survive), the text for
+// strings and lowercase hex for bytes.
+type DecodedSMCValue struct {
+	Kind   SMCValueKind               `json:"Kind"`
+	Number float64                    `json:"Number"`
+	String string                     `json:"String"`
+	Fields map[string]DecodedSMCValue `json:"Fields,omitempty"`
+}
+
+// Decode decodes the value using the little-endian layout of Apple Silicon
+// SMCs.
+func (v RawSMCValue) Decode() (DecodedSMCValue, error) {
+	return v.DecodeWithOrder(binary.LittleEndian)
+}
+
+// DecodeWithOrder decodes the value using the given byte order for
+// multi-byte fields; Intel SMCs use binary.BigEndian.
+func (v RawSMCValue) DecodeWithOrder(order binary.ByteOrder) (DecodedSMCValue, error) {
+	decoded, err := smc.DecodeValue(v.DataType, v.Data, order)
+	if err != nil {
+		return DecodedSMCValue{}, err
+	}
+	return newDecodedSMCValue(decoded), nil
+}
+
+// withDecoded fills Decoded for every value whose type is known.
+func withDecoded(values map[string]RawSMCValue) map[string]RawSMCValue {
+	for key, val := range values {
+		if decoded, err := val.Decode(); err == nil {
+			val.Decoded = &decoded
+			values[key] = val
+		}
+	}
+	return values
+}
+
+func newDecodedSMCValue(v smc.DecodedValue) DecodedSMCValue {
+	out := DecodedSMCValue{Kind: SMCValueKind(v.Kind), Number: v.Number, String: v.String}
+	if len(v.Fields) > 0 {
+		out.Fields = make(map[string]DecodedSMCValue, len(v.Fields))
+		for name, field := range v.Fields {
+			out.Fields[name] = newDecodedSMCValue(field)
+		}
+	}
+	return out
+}
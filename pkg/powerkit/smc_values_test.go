@@ -0,0 +1,40 @@
+package powerkit
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+func TestGetRawSMCValuesFillsDecoded(t *testing.T) {
+	stub := &stubBackend{
+		fetchSMCRaw: func([]string) (map[string]RawSMCValue, error) {
+			return map[string]RawSMCValue{
+				"F0Ac": fltValue(1200),
+				"RPlt": {DataType: "ch8*", DataSize: 8, Data: []byte("j314\x00\x00\x00\x00")},
+				"XXXX": {DataType: "????", DataSize: 1, Data: []byte{0x01}},
+			}, nil
+		},
+	}
+
+	values, err := New(WithBackend(stub)).GetRawSMCValues([]string{"F0Ac", "RPlt", "XXXX"})
+	if err != nil {
+		t.Fatalf("GetRawSMCValues returned error: %v", err)
+	}
+	if d := values["F0Ac"].Decoded; d == nil || d.Kind != SMCValueNumber || d.Number != 1200 {
+		t.Fatalf("expected decoded number 1200, got %+v", d)
+	}
+	if d := values["RPlt"].Decoded; d == nil || d.Kind != SMCValueString || d.String != "j314" {
+		t.Fatalf("expected decoded string j314, got %+v", d)
+	}
+	if values["XXXX"].Decoded != nil {
+		t.Fatalf("expected unknown type to stay undecoded")
+	}
+}
+
+func TestRawSMCValueDecodeWithOrder(t *testing.T) {
+	v := RawSMCValue{DataType: "fpe2", DataSize: 2, Data: []byte{0x12, 0xc1}}
+	decoded, err := v.DecodeWithOrder(binary.BigEndian)
+	if err != nil || decoded.Number != 1200.25 {
+		t.Fatalf("expected big-endian fpe2 1200.25, got %+v (%v)", decoded, err)
+	}
+}
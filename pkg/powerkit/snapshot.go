@@ -0,0 +1,163 @@
+package powerkit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/peterneutron/powerkit-go/internal/smc"
+)
+
+// SnapshotVersion is the snapshot file format version written by this
+// library. ReadSnapshot rejects files with a newer version.
+const SnapshotVersion = 1
+
+// Snapshot is a capture of everything a backend reported at one moment:
+// the battery reading before adapter telemetry evaluation, raw SMC values,
+// sleep assertion status, Low Power Mode and pmset settings. Replaying it through
+// NewReplayBackend reproduces GetSystemInfo, including the telemetry
+// fallback decision, on any platform.
+type Snapshot struct {
+	Version    int          `json:"version"`
+	CapturedAt time.Time    `json:"captured_at"`
+	Firmware   FirmwareInfo `json:"firmware"`
+
+	// Battery is nil when the reading failed; BatteryError then holds the
+	// error text. A reading with an empty TelemetrySource has not been
+	// evaluated yet and is evaluated against SMC on replay.
+	Battery      *RawBatteryData `json:"battery,omitempty"`
+	BatteryError string          `json:"battery_error,omitempty"`
+
+	SMC      map[string]RawSMCValue `json:"smc,omitempty"`
+	SMCError string                 `json:"smc_error,omitempty"`
+
+	Sleep        SnapshotSleep        `json:"sleep"`
+	LowPowerMode SnapshotLowPowerMode `json:"low_power_mode"`
+
+	// PowerSettings is nil when the backend could not report pmset settings.
+	PowerSettings *PowerSettingsBySource `json:"power_settings,omitempty"`
+}
+
+// SnapshotSleep records sleep assertion state.
+type SnapshotSleep struct {
+	GlobalSystemAllowed  bool        `json:"global_system_allowed"`
+	GlobalDisplayAllowed bool        `json:"global_display_allowed"`
+	GlobalError          string      `json:"global_error,omitempty"`
+	SystemAssertionID    AssertionID `json:"system_assertion_id,omitempty"`
+	DisplayAssertionID   AssertionID `json:"display_assertion_id,omitempty"`
+}
+
+// SnapshotLowPowerMode records Low Power Mode state.
+type SnapshotLowPowerMode struct {
+	Enabled   bool   `json:"enabled"`
+	Available bool   `json:"available"`
+	Error     string `json:"error,omitempty"`
+}
+
+// batteryReadingFetcher is implemented by backends that can return the
+// battery reading before adapter telemetry evaluation.
+type batteryReadingFetcher interface {
+	fetchBatteryReading() (*RawBatteryData, error)
+}
+
+// snapshotSMCKeys are captured in every snapshot: the keys GetSystemInfo and
+// the adapter telemetry fallback read, plus the MagSafe LED.
+var snapshotSMCKeys = append(append([]string{}, smc.KeysToRead...), smc.KeyMagsafeLED)
+
+// CaptureSnapshot records the current state of the client's backend.
+// extraSMCKeys are captured in addition to the keys GetSystemInfo reads.
+// Failures of individual sources are recorded in the snapshot; an error is
+// returned only when neither the battery nor the SMC could be read.
+func (c *Client) CaptureSnapshot(extraSMCKeys ...string) (*Snapshot, error) {
+	firmware, _ := c.profile()
+	s := &Snapshot{
+		Version:    SnapshotVersion,
+		CapturedAt: time.Now().UTC(),
+		Firmware:   firmware,
+	}
+
+	if battery, err := c.fetchBatteryReading(); err != nil {
+		s.BatteryError = err.Error()
+	} else {
+		s.Battery = battery
+	}
+
+	keys := append(append([]string{}, snapshotSMCKeys...), extraSMCKeys...)
+	if values, err := c.backend.FetchRawData(keys); err != nil {
+		s.SMCError = err.Error()
+	} else {
+		s.SMC = values
+	}
+
+	if s.Battery == nil && s.SMC == nil {
+		return nil, fmt.Errorf("snapshot captured no data: battery: %s; smc: %s", s.BatteryError, s.SMCError)
+	}
+
+	s.Sleep = c.captureSleep()
+	s.LowPowerMode = c.captureLowPowerMode()
+	s.PowerSettings, _ = c.backend.GetPowerSettings()
+	return s, nil
+}
+
+func (c *Client) fetchBatteryReading() (*RawBatteryData, error) {
+	if f, ok := c.backend.(batteryReadingFetcher); ok {
+		return f.fetchBatteryReading()
+	}
+	return c.backend.FetchBatteryData(false)
+}
+
+func (c *Client) captureSleep() SnapshotSleep {
+	var sleep SnapshotSleep
+	var err error
+	sleep.GlobalSystemAllowed, sleep.GlobalDisplayAllowed, err = c.backend.GlobalSleepStatus()
+	if err != nil {
+		sleep.GlobalError = err.Error()
+	}
+	sleep.SystemAssertionID, _ = c.backend.ActiveAssertion(AssertionTypePreventSystemSleep)
+	sleep.DisplayAssertionID, _ = c.backend.ActiveAssertion(AssertionTypePreventDisplaySleep)
+	return sleep
+}
+
+func (c *Client) captureLowPowerMode() SnapshotLowPowerMode {
+	enabled, available, err := c.backend.GetLowPowerModeEnabled()
+	lpm := SnapshotLowPowerMode{Enabled: enabled, Available: available}
+	if err != nil {
+		lpm.Error = err.Error()
+	}
+	return lpm
+}
+
+// WriteSnapshot encodes s as indented JSON.
+func WriteSnapshot(w io.Writer, s *Snapshot) error {
+	if s.Version == 0 {
+		s.Version = SnapshotVersion
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(s); err != nil {
+		return fmt.Errorf("encode snapshot: %w", err)
+	}
+	return nil
+}
+
+// ReadSnapshot decodes a snapshot written by WriteSnapshot.
+func ReadSnapshot(r io.Reader) (*Snapshot, error) {
+	var s Snapshot
+	if err := json.NewDecoder(r).Decode(&s); err != nil {
+		return nil, fmt.Errorf("decode snapshot: %w", err)
+	}
+	if s.Version < 1 || s.Version > SnapshotVersion {
+		return nil, fmt.Errorf("unsupported snapshot version %d (supported: 1-%d)", s.Version, SnapshotVersion)
+	}
+	return &s, nil
+}
+
+// snapshotError turns a recorded error string back into an error.
+func snapshotError(msg string) error {
+	if msg == "" {
+		return nil
+	}
+	return errors.New(msg)
+}
@@ -0,0 +1,140 @@
+package powerkit
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/peterneutron/powerkit-go/internal/smc"
+)
+
+func fltValue(v float32) RawSMCValue {
+	b := make([]byte, 4)
+	binary.LittleEndian.PutUint32(b, math.Float32bits(v))
+	return RawSMCValue{DataType: "flt ", DataSize: 4, Data: b}
+}
+
+func unevaluatedSnapshot() *Snapshot {
+	return &Snapshot{
+		Version:  SnapshotVersion,
+		Firmware: FirmwareInfo{Major: FirmwareMajorVersionThreshold, Version: "iBoot-13822.81.10", Source: "ioreg_device_tree"},
+		Battery: &RawBatteryData{
+			CurrentCharge:     72,
+			IsConnected:       true,
+			IsCharging:        true,
+			DesignCapacity:    6000,
+			MaxCapacity:       5400,
+			NominalCapacity:   5500,
+			Voltage:           12500,
+			Amperage:          1000,
+			CellVoltages:      []int{4160, 4165, 4170},
+			SourceVoltage:     20000,
+			SourceAmperage:    20, // implausibly low: forces the SMC fallback
+			HasPowerTelemetry: true,
+		},
+		SMC: map[string]RawSMCValue{
+			smc.KeyAdapterVoltage:    fltValue(19.5),
+			smc.KeyAdapterCurrent:    fltValue(2),
+			smc.KeyIsChargingEnabled: {DataType: "ui32", DataSize: 4, Data: []byte{0x01, 0x00, 0x00, 0x00}},
+		},
+		Sleep:        SnapshotSleep{GlobalSystemAllowed: true, GlobalDisplayAllowed: false, DisplayAssertionID: 42},
+		LowPowerMode: SnapshotLowPowerMode{Enabled: true, Available: true},
+	}
+}
+
+func TestReplayReproducesTelemetryFallback(t *testing.T) {
+	client := New(WithBackend(NewReplayBackend(unevaluatedSnapshot())))
+
+	info, err := client.GetSystemInfo()
+	if err != nil {
+		t.Fatalf("GetSystemInfo returned error: %v", err)
+	}
+	sources := info.ToJSON().Sources.AdapterTelemetry
+	if sources.Source != "smc_fallback" || sources.Reason != "invalid_iokit" {
+		t.Fatalf("expected smc_fallback/invalid_iokit, got %+v", sources)
+	}
+	if info.IOKit.Adapter.InputVoltage != 19.5 || info.IOKit.Adapter.InputAmperage != 2 {
+		t.Fatalf("expected fallback input 19.5 V / 2 A, got %+v", info.IOKit.Adapter)
+	}
+	if info.IOKit.Calculations.HealthByMaxCapacity != 90 || info.IOKit.Calculations.SystemPower != 26.5 {
+		t.Fatalf("unexpected calculations: %+v", info.IOKit.Calculations)
+	}
+	if info.SMC == nil || info.SMC.State.IsChargingEnabled {
+		t.Fatalf("expected replayed CHTE to report charging disabled, got %+v", info.SMC)
+	}
+	if info.OS.AppDisplaySleepAllowed || !info.OS.LowPowerMode.Enabled {
+		t.Fatalf("expected replayed assertion and low power mode state, got %+v", info.OS)
+	}
+}
+
+func TestSnapshotCaptureRoundTrip(t *testing.T) {
+	source := New(WithBackend(NewReplayBackend(unevaluatedSnapshot())))
+	captured, err := source.CaptureSnapshot()
+	if err != nil {
+		t.Fatalf("CaptureSnapshot returned error: %v", err)
+	}
+	if captured.Battery.TelemetrySource != "" {
+		t.Fatalf("expected capture to keep the reading unevaluated, got %q", captured.Battery.TelemetrySource)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteSnapshot(&buf, captured); err != nil {
+		t.Fatalf("WriteSnapshot returned error: %v", err)
+	}
+	loaded, err := ReadSnapshot(&buf)
+	if err != nil {
+		t.Fatalf("ReadSnapshot returned error: %v", err)
+	}
+
+	info, err := New(WithBackend(NewReplayBackend(loaded))).GetSystemInfo(FetchOptions{QueryIOKit: true, ForceTelemetryFallback: true})
+	if err != nil {
+		t.Fatalf("GetSystemInfo returned error: %v", err)
+	}
+	if got := info.ToJSON().Sources.AdapterTelemetry.Reason; got != "forced" {
+		t.Fatalf("expected forced fallback on replay, got %q", got)
+	}
+	if loaded.Sleep.DisplayAssertionID != 42 || !bytes.Equal(loaded.SMC[smc.KeyAdapterVoltage].Data, fltValue(19.5).Data) {
+		t.Fatalf("snapshot did not round-trip: %+v", loaded)
+	}
+}
+
+func TestReadSnapshotRejectsUnknownVersion(t *testing.T) {
+	if _, err := ReadSnapshot(strings.NewReader(`{"version": 99}`)); err == nil {
+		t.Fatalf("expected error for unsupported version")
+	}
+	if _, err := ReadSnapshot(strings.NewReader(`{}`)); err == nil {
+		t.Fatalf("expected error for missing version")
+	}
+}
+
+func TestReplayStreamsFrames(t *testing.T) {
+	first := unevaluatedSnapshot()
+	second := unevaluatedSnapshot()
+	second.Battery = copyRawBatteryData(first.Battery)
+	second.Battery.CurrentCharge = 73
+
+	stream, err := New(WithBackend(NewReplayBackend(first, second))).StreamSystemEvents()
+	if err != nil {
+		t.Fatalf("StreamSystemEvents returned error: %v", err)
+	}
+
+	var charges []int
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case event, ok := <-stream:
+			if !ok {
+				if len(charges) != 2 || charges[0] != 72 || charges[1] != 73 {
+					t.Fatalf("expected charges [72 73], got %v", charges)
+				}
+				return
+			}
+			charges = append(charges, event.Info.IOKit.Battery.CurrentCharge)
+		case <-timeout:
+			t.Fatalf("timed out waiting for replayed frames, got %v", charges)
+		}
+	}
+}
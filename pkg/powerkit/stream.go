@@ -1,65 +1,49 @@
-//go:build darwin
-
 package powerkit
 
 import (
 	"errors"
-	"log"
 	"reflect"
-	"sync"
-
-	"github.com/peterneutron/powerkit-go/internal/iokit"
-	"github.com/peterneutron/powerkit-go/internal/powerd"
 )
 
 var (
-	errSystemEventStreamActive  = errors.New("powerkit system event stream already active")
-	errConflictingStreamHooks   = errors.New("powerkit system event stream already active with different hooks")
-	streamMu                    sync.Mutex
-	streamActive                bool
-	activeStreamHooks           StreamHooks
-	startMonitorFn              = iokit.StartMonitor
-	setBeforeSleepHookFn        = iokit.SetBeforeSleepHook
-	internalEventSource         = func() <-chan iokit.InternalEvent { return iokit.Events }
-	enqueueInitialBatteryUpdate = func() {
-		select {
-		case iokit.Events <- iokit.InternalEvent{Type: iokit.BatteryUpdate}:
-		default:
-		}
-	}
+	errSystemEventStreamActive = errors.New("powerkit system event stream already active")
+	errConflictingStreamHooks  = errors.New("powerkit system event stream already active with different hooks")
 )
 
-// StreamSystemEvents starts monitoring IOKit for all relevant power and
+// StreamSystemEvents starts monitoring the backend for all relevant power and
 // battery events. It returns a single, read-only channel that delivers a
 // unified SystemEvent for any change.
-func StreamSystemEvents() (<-chan SystemEvent, error) {
-	return StreamSystemEventsWithHooks(StreamHooks{})
+func (c *Client) StreamSystemEvents() (<-chan SystemEvent, error) {
+	return c.StreamSystemEventsWithHooks(StreamHooks{})
 }
 
-// StreamSystemEventsWithHooks starts the singleton system event stream and
-// installs synchronous lifecycle hooks such as BeforeSleep.
-func StreamSystemEventsWithHooks(hooks StreamHooks) (<-chan SystemEvent, error) {
-	streamMu.Lock()
-	defer streamMu.Unlock()
+// StreamSystemEventsWithHooks starts the client's singleton system event
+// stream and installs synchronous lifecycle hooks such as BeforeSleep.
+func (c *Client) StreamSystemEventsWithHooks(hooks StreamHooks) (<-chan SystemEvent, error) {
+	c.streamMu.Lock()
+	defer c.streamMu.Unlock()
 
-	if streamActive {
-		if !sameStreamHooks(activeStreamHooks, hooks) {
+	if c.streamActive {
+		if !sameStreamHooks(c.activeStreamHooks, hooks) {
 			return nil, errConflictingStreamHooks
 		}
 		return nil, errSystemEventStreamActive
 	}
 
-	setBeforeSleepHookFn(hooks.BeforeSleep)
-	streamActive = true
-	activeStreamHooks = hooks
+	source, err := c.backend.SubscribeEvents(hooks.BeforeSleep)
+	if err != nil {
+		return nil, err
+	}
+	c.streamActive = true
+	c.activeStreamHooks = hooks
 
 	systemEventChan := make(chan SystemEvent, 16)
-	go func(source <-chan iokit.InternalEvent) {
+	go func() {
 		defer close(systemEventChan)
-		defer releaseStreamRegistration()
+		defer c.releaseStreamRegistration()
 
-		for internalEvent := range source {
-			publicEvent, ok := translateInternalEvent(internalEvent)
+		for eventType := range source {
+			publicEvent, ok := c.translateEvent(eventType)
 			if !ok {
 				continue
 			}
@@ -74,20 +58,16 @@ func StreamSystemEventsWithHooks(hooks StreamHooks) (<-chan SystemEvent, error)
 
 			systemEventChan <- publicEvent
 		}
-	}(internalEventSource())
-
-	startMonitorFn()
-	enqueueInitialBatteryUpdate()
+	}()
 
 	return systemEventChan, nil
 }
 
-func releaseStreamRegistration() {
-	setBeforeSleepHookFn(nil)
-	streamMu.Lock()
-	defer streamMu.Unlock()
-	streamActive = false
-	activeStreamHooks = StreamHooks{}
+func (c *Client) releaseStreamRegistration() {
+	c.streamMu.Lock()
+	defer c.streamMu.Unlock()
+	c.streamActive = false
+	c.activeStreamHooks = StreamHooks{}
 }
 
 func sameStreamHooks(a, b StreamHooks) bool {
@@ -105,58 +85,31 @@ func sameFunc(a, b func()) bool {
 	}
 }
 
-func translateInternalEvent(internalEvent iokit.InternalEvent) (SystemEvent, bool) {
-	switch internalEvent.Type {
-	case iokit.BatteryUpdate:
-		info, err := buildBatteryUpdateInfo()
+func (c *Client) translateEvent(eventType EventType) (SystemEvent, bool) {
+	switch eventType {
+	case EventTypeBatteryUpdate:
+		info, err := c.buildBatteryUpdateInfo()
 		if err != nil {
-			log.Printf("Error fetching IOKit data in stream: %v", err)
+			c.logger.Printf("Error fetching IOKit data in stream: %v", err)
 			return SystemEvent{}, false
 		}
 		return SystemEvent{Type: EventTypeBatteryUpdate, Info: info}, true
-	case iokit.SystemWillSleep:
-		return SystemEvent{Type: EventTypeSystemWillSleep}, true
-	case iokit.SystemDidWake:
-		return SystemEvent{Type: EventTypeSystemDidWake}, true
+	case EventTypeSystemWillSleep, EventTypeSystemDidWake:
+		return SystemEvent{Type: eventType}, true
 	default:
-		log.Printf("Warning: Received unknown internal event type: %d", internalEvent.Type)
+		c.logger.Printf("Warning: Received unknown event type: %d", eventType)
 		return SystemEvent{}, false
 	}
 }
 
-func buildBatteryUpdateInfo() (*SystemInfo, error) {
-	iokitRawData, err := fetchIOKitData(false)
+func (c *Client) buildBatteryUpdateInfo() (*SystemInfo, error) {
+	iokitRawData, err := c.backend.FetchBatteryData(false)
 	if err != nil {
 		return nil, err
 	}
 
-	sysAllowedGlobal, dspAllowedGlobal, gErr := globalSleepStatusFn()
-	dspActiveApp := powerdIsActiveFn(powerd.PreventDisplaySleep)
-	sysActiveApp := powerdIsActiveFn(powerd.PreventSystemSleep)
-	dspAllowedApp := !dspActiveApp
-	sysAllowedApp := !sysActiveApp && !dspActiveApp
-	if gErr != nil {
-		dspAllowedGlobal = dspAllowedApp
-		sysAllowedGlobal = sysAllowedApp
-	}
-
-	lpmEnabled, lpmAvailable, _ := getLowPowerModeFn()
-
 	info := &SystemInfo{
-		OS: OSInfo{
-			Firmware:                  currentSMCConfig.Firmware,
-			FirmwareVersion:           currentFirmwareInfo.Version,
-			FirmwareSource:            currentFirmwareInfo.Source,
-			FirmwareMajor:             currentFirmwareInfo.Major,
-			FirmwareCompatStatus:      firmwareCompatStatus(currentFirmwareInfo.Major),
-			FirmwareProfileID:         currentSMCConfig.FirmwareProfileID,
-			FirmwareProfileVersion:    currentSMCConfig.FirmwareProfileVersion,
-			GlobalSystemSleepAllowed:  sysAllowedGlobal,
-			GlobalDisplaySleepAllowed: dspAllowedGlobal,
-			AppSystemSleepAllowed:     sysAllowedApp,
-			AppDisplaySleepAllowed:    dspAllowedApp,
-			LowPowerMode:              LowPowerModeInfo{Enabled: lpmEnabled, Available: lpmAvailable},
-		},
+		OS:    c.collectOSInfo(),
 		IOKit: newIOKitData(iokitRawData),
 		SMC:   nil,
 	}
@@ -165,8 +118,8 @@ func buildBatteryUpdateInfo() (*SystemInfo, error) {
 	info.iokitAvailable = true
 	info.smcQueried = false
 	info.smcAvailable = false
-	info.adapterTelemetrySource = string(iokitRawData.TelemetrySource)
-	info.adapterTelemetryReason = string(iokitRawData.TelemetryReason)
+	info.adapterTelemetrySource = iokitRawData.TelemetrySource
+	info.adapterTelemetryReason = iokitRawData.TelemetryReason
 	info.forceTelemetryFallback = iokitRawData.ForceFallback
 	calculateDerivedMetrics(info)
 
@@ -8,10 +8,9 @@ import (
 )
 
 func resetStreamStateForTest() {
-	streamMu.Lock()
-	streamActive = false
-	activeStreamHooks = StreamHooks{}
-	streamMu.Unlock()
+	systemEventsMu.Lock()
+	systemEventsActive = false
+	systemEventsMu.Unlock()
 }
 
 func TestStreamSystemEventsCompatibilityPath(t *testing.T) {
@@ -35,7 +34,7 @@ func TestStreamSystemEventsCompatibilityPath(t *testing.T) {
 	internalEventSource = func() <-chan iokit.InternalEvent { return source }
 	enqueueInitialBatteryUpdate = func() {}
 
-	eventChan, err := StreamSystemEvents()
+	eventChan, err := New(WithBackend(systemBackend{})).StreamSystemEvents()
 	if err != nil {
 		t.Fatalf("StreamSystemEvents returned error: %v", err)
 	}
@@ -55,6 +54,9 @@ func TestStreamSystemEventsCompatibilityPath(t *testing.T) {
 	}
 
 	close(source)
+	for event := range eventChan {
+		_ = event
+	}
 }
 
 func TestStreamSystemEventsWithHooksRejectsConflictingRegistration(t *testing.T) {
@@ -77,12 +79,13 @@ func TestStreamSystemEventsWithHooksRejectsConflictingRegistration(t *testing.T)
 	internalEventSource = func() <-chan iokit.InternalEvent { return source }
 	enqueueInitialBatteryUpdate = func() {}
 
-	stream, err := StreamSystemEventsWithHooks(StreamHooks{BeforeSleep: func() {}})
+	client := New(WithBackend(systemBackend{}))
+	stream, err := client.StreamSystemEventsWithHooks(StreamHooks{BeforeSleep: func() {}})
 	if err != nil {
 		t.Fatalf("first stream registration failed: %v", err)
 	}
 
-	if _, err := StreamSystemEventsWithHooks(StreamHooks{}); err != errConflictingStreamHooks {
+	if _, err := client.StreamSystemEventsWithHooks(StreamHooks{}); err != errConflictingStreamHooks {
 		t.Fatalf("expected conflicting hook registration error, got %v", err)
 	}
 
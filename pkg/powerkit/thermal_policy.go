@@ -0,0 +1,100 @@
+package powerkit
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// ThermalPolicy pauses charging while the battery is hot: charging stops
+// once IOKitBattery.Temperature exceeds Ceiling and resumes once it falls
+// below Resume, both in degrees Celsius. A pause lasts at least MinPause
+// and charging lasts at least MinCharge before the policy switches again,
+// so a temperature hovering at a threshold does not toggle charging on
+// every evaluation.
+//
+// In JSON the dwell times are Go duration strings, for example
+// {"ceiling": 40, "resume": 35, "min_pause": "10m", "min_charge": "5m"};
+// omitted dwell times are zero.
+type ThermalPolicy struct {
+	Ceiling   float64
+	Resume    float64
+	MinPause  time.Duration
+	MinCharge time.Duration
+}
+
+// thermalPolicyJSON is the JSON form of ThermalPolicy.
+type thermalPolicyJSON struct {
+	Ceiling   float64 `json:"ceiling"`
+	Resume    float64 `json:"resume"`
+	MinPause  string  `json:"min_pause,omitempty"`
+	MinCharge string  `json:"min_charge,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (p ThermalPolicy) MarshalJSON() ([]byte, error) {
+	return json.Marshal(thermalPolicyJSON{
+		Ceiling:   p.Ceiling,
+		Resume:    p.Resume,
+		MinPause:  p.MinPause.String(),
+		MinCharge: p.MinCharge.String(),
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (p *ThermalPolicy) UnmarshalJSON(data []byte) error {
+	var raw thermalPolicyJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	minPause, err := parseDwell("min_pause", raw.MinPause)
+	if err != nil {
+		return err
+	}
+	minCharge, err := parseDwell("min_charge", raw.MinCharge)
+	if err != nil {
+		return err
+	}
+	*p = ThermalPolicy{Ceiling: raw.Ceiling, Resume: raw.Resume, MinPause: minPause, MinCharge: minCharge}
+	return nil
+}
+
+// parseDwell reads a dwell time, treating an empty string as zero.
+func parseDwell(name, s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid thermal policy %s %q: want a duration such as \"10m\"", name, s)
+	}
+	return d, nil
+}
+
+// DefaultThermalPolicy pauses charging above 40 °C until the battery cools
+// below 35 °C, for at least ten minutes, and charges for at least five
+// minutes between pauses.
+func DefaultThermalPolicy() ThermalPolicy {
+	return ThermalPolicy{Ceiling: 40, Resume: 35, MinPause: 10 * time.Minute, MinCharge: 5 * time.Minute}
+}
+
+// Validate checks 0 < Resume < Ceiling and non-negative dwell times.
+func (p ThermalPolicy) Validate() error {
+	if p.Resume <= 0 || p.Resume >= p.Ceiling {
+		return fmt.Errorf("invalid thermal policy: need 0 < resume (%.1f) < ceiling (%.1f)", p.Resume, p.Ceiling)
+	}
+	if p.MinPause < 0 || p.MinCharge < 0 {
+		return fmt.Errorf("invalid thermal policy: dwell times must not be negative")
+	}
+	return nil
+}
+
+// paused applies the thresholds and dwell times to temp, given whether
+// charging is paused now and when that last changed.
+func (p ThermalPolicy) paused(temp float64, paused bool, since, now time.Time) bool {
+	dwell := now.Sub(since)
+	if paused {
+		return temp >= p.Resume || dwell < p.MinPause
+	}
+	return temp > p.Ceiling && dwell >= p.MinCharge
+}
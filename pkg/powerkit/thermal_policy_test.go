@@ -0,0 +1,100 @@
+package powerkit
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestThermalPolicyValidate(t *testing.T) {
+	if err := DefaultThermalPolicy().Validate(); err != nil {
+		t.Fatalf("default policy is invalid: %v", err)
+	}
+	for _, invalid := range []ThermalPolicy{
+		{Ceiling: 35, Resume: 35},
+		{Ceiling: 40, Resume: 0},
+		{Ceiling: 40, Resume: 35, MinPause: -time.Second},
+	} {
+		if err := invalid.Validate(); err == nil {
+			t.Fatalf("expected %+v to be invalid", invalid)
+		}
+	}
+}
+
+func TestThermalPolicyJSON(t *testing.T) {
+	data, err := json.Marshal(DefaultThermalPolicy())
+	if err != nil {
+		t.Fatalf("Marshal returned error: %v", err)
+	}
+	if want := `{"ceiling":40,"resume":35,"min_pause":"10m0s","min_charge":"5m0s"}`; string(data) != want {
+		t.Fatalf("Marshal = %s, want %s", data, want)
+	}
+
+	var policy ThermalPolicy
+	if err := json.Unmarshal([]byte(`{"ceiling":42,"resume":36,"min_pause":"15m"}`), &policy); err != nil {
+		t.Fatalf("Unmarshal returned error: %v", err)
+	}
+	if want := (ThermalPolicy{Ceiling: 42, Resume: 36, MinPause: 15 * time.Minute}); policy != want {
+		t.Fatalf("Unmarshal = %+v, want %+v", policy, want)
+	}
+	for _, invalid := range []string{`{"min_pause":600000000000}`, `{"min_charge":"five minutes"}`} {
+		if err := json.Unmarshal([]byte(invalid), &policy); err == nil {
+			t.Fatalf("expected %s to be rejected", invalid)
+		}
+	}
+}
+
+func TestChargeLimiterThermalPolicy(t *testing.T) {
+	rig := newLimiterRig(50)
+	temp := 30.0
+	rig.backend.fetchBattery = func(bool) (*RawBatteryData, error) {
+		return &RawBatteryData{CurrentCharge: rig.charge, IsConnected: true, Temperature: int(temp * 100)}, nil
+	}
+	limiter, err := NewChargeLimiter(New(WithBackend(rig.backend), WithRootCheck(false)), ChargeLimit{Upper: 80, Lower: 75})
+	if err != nil {
+		t.Fatalf("NewChargeLimiter returned error: %v", err)
+	}
+	policy := ThermalPolicy{Ceiling: 40, Resume: 35, MinPause: 10 * time.Minute, MinCharge: 5 * time.Minute}
+	if err := limiter.SetThermalPolicy(&policy); err != nil {
+		t.Fatalf("SetThermalPolicy returned error: %v", err)
+	}
+	start := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	var now time.Time
+	limiter.now = func() time.Time { return now }
+
+	steps := []struct {
+		name     string
+		after    time.Duration
+		temp     float64
+		charging bool
+	}{
+		{name: "cool battery charges", temp: 30, charging: true},
+		{name: "at the ceiling keeps charging", after: time.Minute, temp: 40, charging: true},
+		{name: "above the ceiling pauses", after: 2 * time.Minute, temp: 41, charging: false},
+		{name: "cooled below resume waits out the pause", after: 8 * time.Minute, temp: 34, charging: false},
+		{name: "between thresholds stays paused", after: 13 * time.Minute, temp: 37, charging: false},
+		{name: "below resume after the pause charges", after: 14 * time.Minute, temp: 34, charging: true},
+		{name: "hot again within the charge dwell keeps charging", after: 16 * time.Minute, temp: 42, charging: true},
+		{name: "hot after the charge dwell pauses", after: 19 * time.Minute, temp: 42, charging: false},
+	}
+	for _, step := range steps {
+		now, temp = start.Add(step.after), step.temp
+		if err := limiter.evaluate(); err != nil {
+			t.Fatalf("%s: evaluate returned error: %v", step.name, err)
+		}
+		status := limiter.Status()
+		if rig.chargingEnabled() != step.charging || status.ThermalPaused == step.charging || status.Temperature != step.temp {
+			t.Fatalf("%s: charging %v, status %+v", step.name, rig.chargingEnabled(), status)
+		}
+	}
+
+	if err := limiter.SetThermalPolicy(nil); err != nil {
+		t.Fatalf("SetThermalPolicy(nil) returned error: %v", err)
+	}
+	if err := limiter.evaluate(); err != nil {
+		t.Fatalf("evaluate returned error: %v", err)
+	}
+	if !rig.chargingEnabled() || limiter.ThermalPolicy() != nil {
+		t.Fatalf("expected charging resumed once the policy is cleared")
+	}
+}
@@ -0,0 +1,177 @@
+package powerkit
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultTopUpChargePower is the charging power, in watts, assumed for
+	// a top-up before any charging has been observed. It is deliberately
+	// low so the top-up starts early rather than late.
+	DefaultTopUpChargePower = 15.0
+	// TopUpMargin is added to every top-up estimate because charging slows
+	// as the battery approaches full.
+	TopUpMargin = 30 * time.Minute
+	// maxTimeToFull is the IOKit TimeToFull value meaning "still
+	// calculating".
+	maxTimeToFull = 65535
+)
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+var dayGroups = map[string][]time.Weekday{
+	"daily":    nil,
+	"weekdays": {time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+	"weekends": {time.Sunday, time.Saturday},
+}
+
+// TopUpSchedule names the times by which the battery should be full: Hour
+// and Minute in local time on each of Days, or every day when Days is
+// empty. It encodes as text such as "weekdays 08:00", "mon,thu 07:30" or
+// "daily 06:45".
+type TopUpSchedule struct {
+	Days   []time.Weekday
+	Hour   int
+	Minute int
+}
+
+// ParseTopUpSchedule parses "[days] HH:MM", where days is daily (the
+// default), weekdays, weekends or a comma-separated list of sun..sat.
+func ParseTopUpSchedule(s string) (TopUpSchedule, error) {
+	fields := strings.Fields(strings.ToLower(s))
+	if len(fields) == 0 || len(fields) > 2 {
+		return TopUpSchedule{}, fmt.Errorf("invalid top-up schedule %q: want \"[days] HH:MM\"", s)
+	}
+
+	var sched TopUpSchedule
+	clock := fields[len(fields)-1]
+	if _, err := fmt.Sscanf(clock, "%d:%d", &sched.Hour, &sched.Minute); err != nil || len(clock) != 5 {
+		return TopUpSchedule{}, fmt.Errorf("invalid top-up time %q: want HH:MM", clock)
+	}
+	if len(fields) == 2 {
+		days, err := parseDays(fields[0])
+		if err != nil {
+			return TopUpSchedule{}, err
+		}
+		sched.Days = days
+	}
+	return sched, sched.Validate()
+}
+
+func parseDays(s string) ([]time.Weekday, error) {
+	if days, ok := dayGroups[s]; ok {
+		return days, nil
+	}
+	var days []time.Weekday
+	for _, name := range strings.Split(s, ",") {
+		day, ok := weekdayNames[name]
+		if !ok {
+			return nil, fmt.Errorf("invalid top-up day %q", name)
+		}
+		if !slices.Contains(days, day) {
+			days = append(days, day)
+		}
+	}
+	slices.Sort(days)
+	return days, nil
+}
+
+// Validate checks the time of day.
+func (s TopUpSchedule) Validate() error {
+	if s.Hour < 0 || s.Hour > 23 || s.Minute < 0 || s.Minute > 59 {
+		return fmt.Errorf("invalid top-up time %02d:%02d", s.Hour, s.Minute)
+	}
+	return nil
+}
+
+// String formats the schedule as ParseTopUpSchedule reads it.
+func (s TopUpSchedule) String() string {
+	clock := fmt.Sprintf("%02d:%02d", s.Hour, s.Minute)
+	if len(s.Days) == 0 {
+		return "daily " + clock
+	}
+	days := sortedDays(s.Days)
+	for name, group := range dayGroups {
+		if group != nil && slices.Equal(group, days) {
+			return name + " " + clock
+		}
+	}
+	names := make([]string, 0, len(days))
+	for _, day := range days {
+		names = append(names, strings.ToLower(day.String()[:3]))
+	}
+	return strings.Join(names, ",") + " " + clock
+}
+
+func sortedDays(days []time.Weekday) []time.Weekday {
+	sorted := slices.Clone(days)
+	slices.Sort(sorted)
+	return sorted
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s TopUpSchedule) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *TopUpSchedule) UnmarshalText(text []byte) error {
+	parsed, err := ParseTopUpSchedule(string(text))
+	if err != nil {
+		return err
+	}
+	*s = parsed
+	return nil
+}
+
+// Next returns the first deadline strictly after t, in t's location.
+func (s TopUpSchedule) Next(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), s.Hour, s.Minute, 0, 0, t.Location())
+	for i := 0; i <= 7; i++ {
+		candidate := day.AddDate(0, 0, i)
+		if candidate.After(t) && (len(s.Days) == 0 || slices.Contains(s.Days, candidate.Weekday())) {
+			return candidate
+		}
+	}
+	return time.Time{}
+}
+
+// topUpDuration estimates how long charging from the current state to full
+// takes at power watts, plus TopUpMargin.
+func topUpDuration(b IOKitBattery, power float64) time.Duration {
+	remainingWh := remainingEnergy(b)
+	if remainingWh <= 0 {
+		return TopUpMargin
+	}
+	if power <= 0 {
+		power = DefaultTopUpChargePower
+	}
+	return time.Duration(remainingWh/power*float64(time.Hour)) + TopUpMargin
+}
+
+// remainingEnergy is the energy, in watt-hours, missing to a full battery.
+func remainingEnergy(b IOKitBattery) float64 {
+	return float64(b.MaxCapacity-b.CurrentCapacityRaw) * b.Voltage / 1000
+}
+
+// observedChargePower reads the charging power from a charging battery:
+// IOKitCalculations.BatteryPower, else the power implied by TimeToFull. It
+// returns zero when the battery is not charging.
+func observedChargePower(d *IOKitData) float64 {
+	if !d.State.IsCharging {
+		return 0
+	}
+	if d.Calculations.BatteryPower > 0 {
+		return d.Calculations.BatteryPower
+	}
+	if ttf := d.Battery.TimeToFull; ttf > 0 && ttf < maxTimeToFull {
+		return remainingEnergy(d.Battery) / (float64(ttf) / 60)
+	}
+	return 0
+}
@@ -0,0 +1,145 @@
+package powerkit
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestParseTopUpSchedule(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		err  bool
+	}{
+		{in: "08:00", want: "daily 08:00"},
+		{in: "Weekdays 07:30", want: "weekdays 07:30"},
+		{in: "sat,sun 10:15", want: "weekends 10:15"},
+		{in: "thu,mon,thu 06:05", want: "mon,thu 06:05"},
+		{in: "8:00", err: true},
+		{in: "daily 24:00", err: true},
+		{in: "someday 08:00", err: true},
+		{in: "weekdays 08:00 extra", err: true},
+	}
+	for _, tc := range tests {
+		sched, err := ParseTopUpSchedule(tc.in)
+		if tc.err {
+			if err == nil {
+				t.Fatalf("ParseTopUpSchedule(%q) = %v, want error", tc.in, sched)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("ParseTopUpSchedule(%q) returned error: %v", tc.in, err)
+		}
+		if got := sched.String(); got != tc.want {
+			t.Fatalf("ParseTopUpSchedule(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+
+	data, err := json.Marshal(struct{ S TopUpSchedule }{S: TopUpSchedule{Days: []time.Weekday{time.Friday}, Hour: 9}})
+	if err != nil || string(data) != `{"S":"fri 09:00"}` {
+		t.Fatalf("unexpected JSON %s (err %v)", data, err)
+	}
+}
+
+func TestTopUpScheduleNext(t *testing.T) {
+	weekdays := TopUpSchedule{Days: dayGroups["weekdays"], Hour: 8}
+	// 2026-10-16 is a Friday.
+	friday := time.Date(2026, 10, 16, 7, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		sched TopUpSchedule
+		after time.Time
+		want  time.Time
+	}{
+		{name: "later today", sched: weekdays, after: friday, want: friday.Add(time.Hour)},
+		{name: "deadline itself is past", sched: weekdays, after: friday.Add(time.Hour), want: time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)},
+		{name: "daily rolls to tomorrow", sched: TopUpSchedule{Hour: 6}, after: friday, want: time.Date(2026, 10, 17, 6, 0, 0, 0, time.UTC)},
+	}
+	for _, tc := range tests {
+		if got := tc.sched.Next(tc.after); !got.Equal(tc.want) {
+			t.Fatalf("%s: Next = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestTopUpEstimate(t *testing.T) {
+	// 1000 mAh at 12 V is 12 Wh short of full.
+	battery := IOKitBattery{MaxCapacity: 5000, CurrentCapacityRaw: 4000, Voltage: 12}
+	if got := topUpDuration(battery, 24); got != 30*time.Minute+TopUpMargin {
+		t.Fatalf("topUpDuration at 24 W = %v", got)
+	}
+	if got := topUpDuration(battery, 0); got != 48*time.Minute+TopUpMargin {
+		t.Fatalf("topUpDuration with no observed power = %v", got)
+	}
+
+	data := &IOKitData{Battery: battery}
+	data.State.IsCharging = true
+	data.Battery.TimeToFull = 40
+	if got := observedChargePower(data); got != 18 {
+		t.Fatalf("power from TimeToFull = %v, want 18", got)
+	}
+	data.Calculations.BatteryPower = 30
+	if got := observedChargePower(data); got != 30 {
+		t.Fatalf("power from BatteryPower = %v, want 30", got)
+	}
+	data.State.IsCharging = false
+	if got := observedChargePower(data); got != 0 {
+		t.Fatalf("power while not charging = %v, want 0", got)
+	}
+}
+
+func TestChargeLimiterTopUp(t *testing.T) {
+	rig := newLimiterRig(80)
+	rig.backend.fetchBattery = func(bool) (*RawBatteryData, error) {
+		return &RawBatteryData{
+			CurrentCharge: rig.charge, IsConnected: true,
+			MaxCapacity: 5000, CurrentCapacityRaw: rig.charge * 50, Voltage: 12000,
+		}, nil
+	}
+	limiter, err := NewChargeLimiter(New(WithBackend(rig.backend), WithRootCheck(false)), ChargeLimit{Upper: 80, Lower: 75})
+	if err != nil {
+		t.Fatalf("NewChargeLimiter returned error: %v", err)
+	}
+	if err := limiter.SetTopUp(&TopUpSchedule{Hour: 8}); err != nil {
+		t.Fatalf("SetTopUp returned error: %v", err)
+	}
+	var now time.Time
+	limiter.now = func() time.Time { return now }
+
+	// 12 Wh at the default 15 W takes 48 minutes, plus the margin: the
+	// top-up for 08:00 starts at 06:42.
+	steps := []struct {
+		name     string
+		at       string
+		charge   int
+		charging bool
+		topping  bool
+	}{
+		{name: "limit holds before the start", at: "06:41", charge: 80},
+		{name: "top-up starts", at: "06:42", charge: 80, charging: true, topping: true},
+		{name: "top-up latches as the estimate shrinks", at: "07:30", charge: 95, charging: true, topping: true},
+		{name: "full stops charging", at: "07:50", charge: 100, topping: true},
+		{name: "deadline restores the limit", at: "08:00", charge: 99},
+	}
+	for _, step := range steps {
+		clock, _ := time.Parse("15:04", step.at)
+		now = time.Date(2026, 10, 16, clock.Hour(), clock.Minute(), 0, 0, time.Local)
+		rig.charge = step.charge
+		if err := limiter.evaluate(); err != nil {
+			t.Fatalf("%s: evaluate returned error: %v", step.name, err)
+		}
+		status := limiter.Status()
+		if rig.chargingEnabled() != step.charging || status.ToppingUp != step.topping {
+			t.Fatalf("%s: charging = %v, topping up = %v; want %v, %v", step.name, rig.chargingEnabled(), status.ToppingUp, step.charging, step.topping)
+		}
+	}
+
+	if err := limiter.SetTopUp(nil); err != nil {
+		t.Fatalf("SetTopUp(nil) returned error: %v", err)
+	}
+	if limiter.TopUp() != nil {
+		t.Fatalf("expected the top-up cleared")
+	}
+}
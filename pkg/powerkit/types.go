@@ -1,5 +1,3 @@
-//go:build darwin
-
 package powerkit
 
 import "time"
@@ -60,6 +58,7 @@ type SystemInfo struct {
 	smcQueried             bool
 	iokitAvailable         bool
 	smcAvailable           bool
+	smcWritable            bool
 	adapterTelemetrySource string
 	adapterTelemetryReason string
 	forceTelemetryFallback bool
@@ -97,10 +96,23 @@ type OSInfo struct {
 	LowPowerMode LowPowerModeInfo `json:"LowPowerMode"`
 }
 
-// LowPowerModeInfo contains the LPM state and availability flag
+// LowPowerModeInfo contains the LPM state and availability flag.
+// Enabled/Available describe the active profile; Battery and AC describe
+// the per-source pmset profiles.
 type LowPowerModeInfo struct {
 	Enabled   bool `json:"Enabled"`
 	Available bool `json:"Available"`
+
+	Battery LowPowerModeSourceInfo `json:"Battery"`
+	AC      LowPowerModeSourceInfo `json:"AC"`
+}
+
+// LowPowerModeSourceInfo is the Low Power Mode setting of one power source
+// profile. Available is false when the profile or its lowpowermode key is
+// missing.
+type LowPowerModeSourceInfo struct {
+	Enabled   bool `json:"Enabled"`
+	Available bool `json:"Available"`
 }
 
 // --- IOKit-Specific Data Structures ---
@@ -211,11 +223,23 @@ type SMCCalculations struct {
 	SystemPower  float64 `json:"SystemPower"`
 }
 
-// RawSMCValue holds the raw, undecoded result of a custom SMC query.
-// It is the responsibility of the caller to decode the Data bytes
-// based on the DataType and DataSize.
+// SMCKeyInfo describes an SMC key without its value. Attributes is the raw
+// attribute byte reported by the SMC; the Client fills Flags from it.
+type SMCKeyInfo struct {
+	Key        string      `json:"Key"`
+	DataType   string      `json:"DataType"`
+	DataSize   int         `json:"DataSize"`
+	Attributes uint8       `json:"Attributes"`
+	Flags      SMCKeyFlags `json:"Flags"`
+}
+
+// RawSMCValue holds the raw result of a custom SMC query. GetRawSMCValues
+// fills Decoded when the DataType is known; otherwise the caller decodes
+// the Data bytes based on the DataType and DataSize.
 type RawSMCValue struct {
 	DataType string `json:"DataType"`
 	DataSize int    `json:"DataSize"`
 	Data     []byte `json:"Data"` // This will be base64-encoded in JSON for readability
+
+	Decoded *DecodedSMCValue `json:"Decoded,omitempty"`
 }
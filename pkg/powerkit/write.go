@@ -1,5 +1,3 @@
-//go:build darwin
-
 package powerkit
 
 import (
@@ -58,21 +56,26 @@ const (
 	LEDErrorPermOff MagsafeLEDState = 0x19
 )
 
+// opSetChargingState names SetChargingState in errors and audit records.
+const opSetChargingState = "set charging state"
+
 // SetAdapterState sets the desired adapter state (On, Off, or Toggle).
 // This function requires root privileges.
-func SetAdapterState(action AdapterAction) error {
-	if err := requireRoot("set adapter state"); err != nil {
+func (c *Client) SetAdapterState(action AdapterAction) error {
+	const op = "set adapter state"
+	if err := c.requireRoot(op); err != nil {
 		return err
 	}
 
-	key := currentSMCConfig.AdapterKey
+	cfg := c.config()
+	key := cfg.AdapterKey
 
 	// Internal helper without side effects.
 	setAdapter := func(enable bool) error {
 		if enable {
-			return smc.WriteData(key, currentSMCConfig.AdapterEnableBytes)
+			return c.writeSMC(op, key, cfg.AdapterEnableBytes)
 		}
-		return smc.WriteData(key, currentSMCConfig.AdapterDisableBytes)
+		return c.writeSMC(op, key, cfg.AdapterDisableBytes)
 	}
 
 	switch action {
@@ -81,7 +84,7 @@ func SetAdapterState(action AdapterAction) error {
 	case AdapterActionOff:
 		return setAdapter(false)
 	case AdapterActionToggle:
-		rawValues, err := GetRawSMCValues([]string{key})
+		rawValues, err := c.GetRawSMCValues([]string{key})
 		if err != nil {
 			return fmt.Errorf("could not read current adapter state: %w", err)
 		}
@@ -89,7 +92,7 @@ func SetAdapterState(action AdapterAction) error {
 		if !ok {
 			return fmt.Errorf("could not find key '%s' on this system", key)
 		}
-		isAdapterDisabled := bytes.Equal(adapterValue.Data, currentSMCConfig.AdapterDisableBytes)
+		isAdapterDisabled := bytes.Equal(adapterValue.Data, cfg.AdapterDisableBytes)
 		return setAdapter(isAdapterDisabled) // enable when disabled, disable when enabled
 	default:
 		return fmt.Errorf("invalid AdapterAction provided")
@@ -98,26 +101,27 @@ func SetAdapterState(action AdapterAction) error {
 
 // SetChargingState sets the desired charging state (On, Off, or Toggle).
 // This function requires root privileges.
-func SetChargingState(action ChargingAction) error {
-	if err := requireRoot("set charging state"); err != nil {
+func (c *Client) SetChargingState(action ChargingAction) error {
+	if err := c.requireRoot(opSetChargingState); err != nil {
 		return err
 	}
 
 	switch action {
 	case ChargingActionOn:
-		return setCharging(true)
+		return c.setCharging(true)
 
 	case ChargingActionOff:
-		return setCharging(false)
+		return c.setCharging(false)
 
 	case ChargingActionToggle:
 		// For toggle, we only need to read one key to determine the state.
-		keyToRead := currentSMCConfig.ChargingKeyModern
-		if currentSMCConfig.IsLegacyCharging {
-			keyToRead = currentSMCConfig.ChargingKeysLegacy[0] // BCLM is sufficient
+		cfg := c.config()
+		keyToRead := cfg.ChargingKeyModern
+		if cfg.IsLegacyCharging {
+			keyToRead = cfg.ChargingKeysLegacy[0] // BCLM is sufficient
 		}
 
-		rawValues, err := GetRawSMCValues([]string{keyToRead})
+		rawValues, err := c.GetRawSMCValues([]string{keyToRead})
 		if err != nil {
 			return fmt.Errorf("could not read current charging state: %w", err)
 		}
@@ -127,8 +131,8 @@ func SetChargingState(action ChargingAction) error {
 			return fmt.Errorf("could not find key '%s' on this system", keyToRead)
 		}
 
-		isChargingDisabled := bytes.Equal(chargerValue.Data, currentSMCConfig.ChargingDisableBytes)
-		return setCharging(isChargingDisabled) // enable when disabled, disable when enabled
+		isChargingDisabled := bytes.Equal(chargerValue.Data, cfg.ChargingDisableBytes)
+		return c.setCharging(isChargingDisabled) // enable when disabled, disable when enabled
 
 	default:
 		return fmt.Errorf("invalid ChargingAction provided")
@@ -137,11 +141,64 @@ func SetChargingState(action ChargingAction) error {
 
 // SetMagsafeLEDState writes the single-byte LED state to the SMC.
 // This uses the common 1-byte ACLC format.
-func SetMagsafeLEDState(state MagsafeLEDState) error {
-	if err := requireRoot("set magsafe LED state"); err != nil {
+func (c *Client) SetMagsafeLEDState(state MagsafeLEDState) error {
+	const op = "set magsafe LED state"
+	if err := c.requireRoot(op); err != nil {
+		return err
+	}
+	return c.writeSMCKeys(op, smcWrite{
+		key:    c.config().LEDKey,
+		data:   []byte{byte(state)},
+		accept: magsafeStateAccepted(state),
+	})
+}
+
+// magsafeStateAccepted verifies an ACLC write. The SMC reports the color it
+// resolved for LEDSystem, and some machines report green as 0x02.
+func magsafeStateAccepted(state MagsafeLEDState) func([]byte) bool {
+	return func(readBack []byte) bool {
+		if state == LEDSystem {
+			return true
+		}
+		if len(readBack) == 0 {
+			return false
+		}
+		return readBack[0] == byte(state) || (state == LEDGreen && readBack[0] == 0x02)
+	}
+}
+
+// WriteSMCValue writes value to an SMC key, encoding it for the data type
+// and size the key reports. Values the type cannot represent are rejected
+// before anything is written. This function requires root privileges.
+func (c *Client) WriteSMCValue(key string, value float64) error {
+	const op = "write SMC value"
+	if err := c.requireRoot(op); err != nil {
 		return err
 	}
-	return smc.WriteData(smc.KeyMagsafeLED, []byte{byte(state)})
+	return c.writeSMCValue(op, key, value, false)
+}
+
+// writeSMCValue is WriteSMCValue without the root check. validated marks a
+// value the caller range-checked; see smcWrite.
+func (c *Client) writeSMCValue(op, key string, value float64, validated bool) error {
+	if len(key) != 4 {
+		return fmt.Errorf("invalid SMC key '%s': keys are 4 characters", key)
+	}
+
+	rawValues, err := c.GetRawSMCValues([]string{key})
+	if err != nil {
+		return fmt.Errorf("could not read type of SMC key '%s': %w", key, err)
+	}
+	current, ok := rawValues[key]
+	if !ok {
+		return fmt.Errorf("%w: SMC key '%s' not found on this system", ErrNotSupported, key)
+	}
+
+	data, err := smc.EncodeValue(current.DataType, current.DataSize, value)
+	if err != nil {
+		return fmt.Errorf("cannot write %v to SMC key '%s': %w", value, key, err)
+	}
+	return c.writeSMCKeys(op, smcWrite{key: key, data: data, validated: validated})
 }
 
 // MagsafeStatus reports MagSafe LED capability and current state.
@@ -151,8 +208,8 @@ type MagsafeStatus struct {
 }
 
 // GetMagsafeStatus returns the current MagSafe LED status.
-func GetMagsafeStatus() (MagsafeStatus, error) {
-	state, available, err := GetMagsafeLEDState()
+func (c *Client) GetMagsafeStatus() (MagsafeStatus, error) {
+	state, available, err := c.GetMagsafeLEDState()
 	if err != nil {
 		return MagsafeStatus{}, err
 	}
@@ -166,13 +223,14 @@ func GetMagsafeStatus() (MagsafeStatus, error) {
 // - state: the raw state value (mapped to constants when known)
 // - available: false when the key exists but contains no data (or cannot be read)
 // - err: transport or read error; unknown state is not an error
-func GetMagsafeLEDState() (state MagsafeLEDState, available bool, err error) {
-	rawValues, err := GetRawSMCValues([]string{smc.KeyMagsafeLED})
+func (c *Client) GetMagsafeLEDState() (state MagsafeLEDState, available bool, err error) {
+	key := c.config().LEDKey
+	rawValues, err := c.GetRawSMCValues([]string{key})
 	if err != nil {
 		return LEDAmber, false, fmt.Errorf("could not read Magsafe LED state from SMC: %w", err)
 	}
 
-	ledValue, ok := rawValues[smc.KeyMagsafeLED]
+	ledValue, ok := rawValues[key]
 	if !ok {
 		// Key not present – treat as unavailable
 		return LEDAmber, false, nil
@@ -194,8 +252,8 @@ func GetMagsafeLEDState() (state MagsafeLEDState, available bool, err error) {
 }
 
 // IsMagsafeCharging checks if the Magsafe LED indicates a charging state (Amber).
-func IsMagsafeCharging() (bool, error) {
-	state, available, err := GetMagsafeLEDState()
+func (c *Client) IsMagsafeCharging() (bool, error) {
+	state, available, err := c.GetMagsafeLEDState()
 	if err != nil {
 		return false, fmt.Errorf("could not determine Magsafe charging state: %w", err)
 	}
@@ -206,7 +264,7 @@ func IsMagsafeCharging() (bool, error) {
 }
 
 // IsMagsafeAvailable returns true if the ACLC key is present and has data.
-func IsMagsafeAvailable() bool {
-	_, ok, _ := GetMagsafeLEDState()
+func (c *Client) IsMagsafeAvailable() bool {
+	_, ok, _ := c.GetMagsafeLEDState()
 	return ok
 }
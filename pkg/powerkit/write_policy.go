@@ -0,0 +1,160 @@
+package powerkit
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+)
+
+// AnyProfile is the WritePolicy profile entry that applies under every
+// firmware profile.
+const AnyProfile = "*"
+
+// KeyRule governs writes to one SMC key.
+type KeyRule struct {
+	// Values lists the byte values that may be written; empty allows any.
+	Values [][]byte
+	// Dangerous writes are denied unless the client is configured
+	// WithDangerousWrites(true) or a control API such as SetFanSpeed
+	// validated the value first.
+	Dangerous bool
+}
+
+// WritePolicy is an allowlist of SMC writes. Profiles maps a firmware
+// profile ID (or AnyProfile) to its writable keys; a key may be a glob
+// pattern such as "F[0-9]Md". A write is allowed when a rule for the active
+// profile or AnyProfile matches the key and value.
+type WritePolicy struct {
+	Profiles map[string]map[string]KeyRule
+}
+
+// DefaultWritePolicy allows exactly the keys and values the library's own
+// control APIs write under each built-in profile: charging, adapter,
+// MagSafe LED and fan control. Fan targets take any speed, so they are
+// dangerous: SetFanSpeed range-checks them, raw WriteSMCValue does not.
+// Use (*ProfileRegistry).WritePolicy to cover profiles loaded at runtime.
+func DefaultWritePolicy() *WritePolicy {
+	return NewProfileRegistry().WritePolicy()
+}
+
+func profileWriteRules(cfg smcControlConfig) map[string]KeyRule {
+	leds := make([][]byte, 0, 8)
+	for _, s := range []MagsafeLEDState{
+		LEDSystem, LEDOff, LEDGreen, LEDAmber, LEDErrorOnce, LEDErrorPermSlow, LEDErrorPermFast, LEDErrorPermOff,
+	} {
+		leds = append(leds, []byte{byte(s)})
+	}
+	charging := KeyRule{Values: [][]byte{cfg.ChargingEnableBytes, cfg.ChargingDisableBytes}}
+	rules := map[string]KeyRule{
+		cfg.AdapterKey:                      {Values: [][]byte{cfg.AdapterEnableBytes, cfg.AdapterDisableBytes}},
+		cfg.LEDKey:                          {Values: leds},
+		fanKeyPattern(cfg.FanModeKeyFormat): {Values: [][]byte{cfg.FanManualBytes, cfg.FanAutoBytes}},
+	}
+	for _, key := range cfg.controlKeys()[1:] {
+		rules[key] = charging
+	}
+	if cfg.FanUnlockKey != "" {
+		rules[cfg.FanUnlockKey] = KeyRule{Values: [][]byte{{0x00}, {0x01}}}
+	}
+	return rules
+}
+
+// fanKeyPattern turns a per-fan key format into a glob over fan indexes.
+func fanKeyPattern(format string) string {
+	return strings.Replace(format, "%d", "[0-9]", 1)
+}
+
+// Check returns an error wrapping ErrWriteDenied unless the policy allows
+// writing data to key under profileID. Dangerous rules match only when
+// allowDangerous is set.
+func (p *WritePolicy) Check(profileID, key string, data []byte, allowDangerous bool) error {
+	rule, ok := p.rule(profileID, key)
+	if !ok {
+		return fmt.Errorf("%w: SMC key '%s' is not in the write allowlist for profile %s", ErrWriteDenied, key, profileID)
+	}
+	if rule.Dangerous && !allowDangerous {
+		return fmt.Errorf("%w: SMC key '%s' is dangerous to write; enable dangerous writes to override", ErrWriteDenied, key)
+	}
+	if len(rule.Values) == 0 {
+		return nil
+	}
+	for _, v := range rule.Values {
+		if bytes.Equal(v, data) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: value % x is not allowed for SMC key '%s'", ErrWriteDenied, data, key)
+}
+
+// rule finds the rule for key, preferring the profile over AnyProfile, an
+// exact key over a pattern, and among overlapping patterns the most specific
+// one; see patternOrder.
+func (p *WritePolicy) rule(profileID, key string) (KeyRule, bool) {
+	for _, id := range []string{profileID, AnyProfile} {
+		rules := p.Profiles[id]
+		if rule, ok := rules[key]; ok {
+			return rule, true
+		}
+		patterns := slices.Collect(maps.Keys(rules))
+		slices.SortFunc(patterns, patternOrder)
+		for _, pattern := range patterns {
+			if ok, _ := path.Match(pattern, key); ok {
+				return rules[pattern], true
+			}
+		}
+	}
+	return KeyRule{}, false
+}
+
+// patternOrder sorts patterns with the longest literal prefix first, then
+// the longest pattern, then lexically, so the same rule always wins.
+func patternOrder(a, b string) int {
+	return cmp.Or(
+		cmp.Compare(literalPrefix(b), literalPrefix(a)),
+		cmp.Compare(len(b), len(a)),
+		strings.Compare(a, b),
+	)
+}
+
+// literalPrefix returns how many leading bytes of pattern match only
+// themselves.
+func literalPrefix(pattern string) int {
+	if i := strings.IndexAny(pattern, `*?[\`); i >= 0 {
+		return i
+	}
+	return len(pattern)
+}
+
+// WithWritePolicy checks every SMC write against policy before anything is
+// written; denied writes fail with ErrWriteDenied. Without a policy any key
+// may be written.
+func WithWritePolicy(policy *WritePolicy) Option {
+	return func(c *Client) {
+		c.writePolicy = policy
+	}
+}
+
+// WithDangerousWrites allows writes that the write policy marks dangerous.
+func WithDangerousWrites(enabled bool) Option {
+	return func(c *Client) {
+		c.allowDangerous = enabled
+	}
+}
+
+// checkWritePolicy checks every write against the client's policy.
+func (c *Client) checkWritePolicy(writes []smcWrite) error {
+	if c.writePolicy == nil {
+		return nil
+	}
+	cfg := c.config()
+	for _, w := range writes {
+		if err := c.writePolicy.Check(cfg.FirmwareProfileID, w.key, w.data, c.allowDangerous || w.validated); err != nil {
+			return err
+		}
+	}
+	return nil
+}
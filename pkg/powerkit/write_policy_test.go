@@ -0,0 +1,107 @@
+package powerkit
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWritePolicyCheck(t *testing.T) {
+	policy := DefaultWritePolicy()
+	policy.Profiles[AnyProfile]["TEST"] = KeyRule{Dangerous: true}
+
+	tests := []struct {
+		name      string
+		profile   string
+		key       string
+		data      []byte
+		dangerous bool
+		allowed   bool
+	}{
+		{name: "modern charging", profile: profileModernID, key: "CHTE", data: []byte{0x01, 0x00, 0x00, 0x00}, allowed: true},
+		{name: "modern charging bad value", profile: profileModernID, key: "CHTE", data: []byte{0x02, 0x00, 0x00, 0x00}},
+		{name: "legacy key on modern", profile: profileModernID, key: "BCLM", data: []byte{0x02}},
+		{name: "legacy charging", profile: profileLegacyID, key: "BCLM", data: []byte{0x02}, allowed: true},
+		{name: "magsafe led", profile: profileLegacyID, key: "ACLC", data: []byte{0x03}, allowed: true},
+		{name: "magsafe led bad value", profile: profileModernID, key: "ACLC", data: []byte{0x09}},
+		{name: "fan mode pattern", profile: profileModernID, key: "F1Md", data: []byte{0x01}, allowed: true},
+		{name: "raw fan target", profile: profileLegacyID, key: "F0Tg", data: []byte{0x00, 0x40, 0x9c, 0x45}},
+		{name: "raw fan target with override", profile: profileLegacyID, key: "F0Tg", data: []byte{0x00, 0x40, 0x9c, 0x45}, dangerous: true, allowed: true},
+		{name: "fan unlock on legacy", profile: profileLegacyID, key: "Ftst", data: []byte{0x01}},
+		{name: "unknown profile", profile: "custom", key: "CHTE", data: []byte{0x00, 0x00, 0x00, 0x00}},
+		{name: "dangerous without override", profile: profileModernID, key: "TEST", data: []byte{0x01}},
+		{name: "dangerous with override", profile: profileModernID, key: "TEST", data: []byte{0x01}, dangerous: true, allowed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Check(tt.profile, tt.key, tt.data, tt.dangerous)
+			if tt.allowed && err != nil {
+				t.Fatalf("expected write to be allowed, got %v", err)
+			}
+			if !tt.allowed && !errors.Is(err, ErrWriteDenied) {
+				t.Fatalf("expected ErrWriteDenied, got %v", err)
+			}
+		})
+	}
+}
+
+func TestWritePolicyPrefersSpecificPatterns(t *testing.T) {
+	policy := &WritePolicy{Profiles: map[string]map[string]KeyRule{
+		"custom": {
+			"F*":      {Dangerous: true},
+			"F[0-9]*": {},
+			"F0*":     {Values: [][]byte{{0x01}}},
+		},
+	}}
+
+	// Go randomizes map iteration, so repeat to catch an unstable choice.
+	for i := 0; i < 50; i++ {
+		if err := policy.Check("custom", "F0Md", []byte{0x01}, false); err != nil {
+			t.Fatalf("expected F0* to govern F0Md, got %v", err)
+		}
+		if err := policy.Check("custom", "F0Md", []byte{0x02}, false); !errors.Is(err, ErrWriteDenied) {
+			t.Fatalf("expected F0* to deny other values, got %v", err)
+		}
+		if err := policy.Check("custom", "F1Md", []byte{0x02}, false); err != nil {
+			t.Fatalf("expected F[0-9]* to govern F1Md over F*, got %v", err)
+		}
+		if err := policy.Check("custom", "FNum", []byte{0x02}, false); !errors.Is(err, ErrWriteDenied) {
+			t.Fatalf("expected only the dangerous F* to match FNum, got %v", err)
+		}
+	}
+}
+
+func TestWritePolicyDeniesBeforeWriting(t *testing.T) {
+	m := &memorySMC{values: map[string][]byte{
+		"BCLM": {0x64},
+		"CHTE": {0x00, 0x00, 0x00, 0x00},
+		"CHIE": {0x00},
+	}}
+	sink := &recordingSink{}
+	client := New(
+		WithBackend(m.backend(FirmwareInfo{Major: FirmwareMajorVersionThreshold})),
+		WithRootCheck(false),
+		WithWritePolicy(DefaultWritePolicy()),
+		WithAuditSink(sink),
+	)
+
+	if err := client.WriteSMCValue("BCLM", 80); !errors.Is(err, ErrWriteDenied) {
+		t.Fatalf("expected ErrWriteDenied, got %v", err)
+	}
+	if len(m.writes) != 0 {
+		t.Fatalf("expected no SMC writes, got %v", m.writes)
+	}
+	if len(sink.records) != 1 || sink.records[0].Result != AuditResultDenied {
+		t.Fatalf("expected one denied audit record, got %+v", sink.records)
+	}
+
+	if err := client.SetChargingState(ChargingActionOff); err != nil {
+		t.Fatalf("SetChargingState returned error: %v", err)
+	}
+	if err := client.SetAdapterState(AdapterActionOff); err != nil {
+		t.Fatalf("SetAdapterState returned error: %v", err)
+	}
+	if len(m.writes) != 2 {
+		t.Fatalf("expected control writes to pass the policy, got %v", m.writes)
+	}
+}
@@ -0,0 +1,42 @@
+package powerkit
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestWriteSMCValueEncodesForKeyType(t *testing.T) {
+	var written []byte
+	stub := &stubBackend{
+		fetchSMCRaw: func(keys []string) (map[string]RawSMCValue, error) {
+			if keys[0] != "BCLM" {
+				return map[string]RawSMCValue{}, nil
+			}
+			return map[string]RawSMCValue{"BCLM": {DataType: "ui8 ", DataSize: 1, Data: []byte{100}}}, nil
+		},
+		writeSMC: func(_ string, data []byte) error {
+			written = data
+			return nil
+		},
+	}
+	client := New(WithBackend(stub), WithRootCheck(false))
+
+	if err := client.WriteSMCValue("BCLM", 80); err != nil {
+		t.Fatalf("WriteSMCValue returned error: %v", err)
+	}
+	if !bytes.Equal(written, []byte{80}) {
+		t.Fatalf("expected ui8 encoding 0x50, got % x", written)
+	}
+
+	written = nil
+	if err := client.WriteSMCValue("BCLM", 300); err == nil {
+		t.Fatalf("expected out-of-range value to be rejected")
+	}
+	if written != nil {
+		t.Fatalf("rejected value must not be written, got % x", written)
+	}
+	if err := client.WriteSMCValue("ZZZZ", 1); !errors.Is(err, ErrNotSupported) {
+		t.Fatalf("expected ErrNotSupported for a missing key, got %v", err)
+	}
+}
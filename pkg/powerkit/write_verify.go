@@ -0,0 +1,125 @@
+package powerkit
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// smcWrite is one key of a write. accept, when set, decides whether the
+// read-back value confirms the write; otherwise it must equal data.
+// validated marks data a control API already range-checked, which the write
+// policy accepts even for dangerous keys.
+type smcWrite struct {
+	key       string
+	data      []byte
+	accept    func(readBack []byte) bool
+	validated bool
+}
+
+// writeSMC writes a single key, verifying it when the client is configured
+// WithWriteVerification. op names the public operation for the audit trail.
+func (c *Client) writeSMC(op, key string, data []byte) error {
+	return c.writeSMCKeys(op, smcWrite{key: key, data: data})
+}
+
+// writeSMCKeys writes the keys in order once the firmware profile resolved
+// and the write policy allows all of them. Without verification it stops at
+// the first failed write; with verification it also reads each key back and
+// rolls a multi-key write back on failure.
+func (c *Client) writeSMCKeys(op string, writes ...smcWrite) error {
+	if _, err := c.ResolveProfile(); err != nil {
+		return err
+	}
+	if err := c.checkWritePolicy(writes); err != nil {
+		for _, w := range writes {
+			c.recordAudit(AuditRecord{Operation: op, Key: w.key, New: hex.EncodeToString(w.data)}, err)
+		}
+		return err
+	}
+	if !c.verifyWrites {
+		for _, w := range writes {
+			if err := c.auditedWriteData(op, w.key, w.data); err != nil {
+				return fmt.Errorf("failed to write SMC key '%s': %w", w.key, err)
+			}
+		}
+		return nil
+	}
+	return c.writeSMCKeysVerified(op, writes)
+}
+
+func (c *Client) writeSMCKeysVerified(op string, writes []smcWrite) error {
+	var originals map[string]RawSMCValue
+	if len(writes) > 1 {
+		keys := make([]string, len(writes))
+		for i, w := range writes {
+			keys[i] = w.key
+		}
+		var err error
+		if originals, err = c.backend.FetchRawData(keys); err != nil {
+			return fmt.Errorf("could not read SMC keys before writing: %w", err)
+		}
+	}
+
+	for i, w := range writes {
+		err := c.auditedWriteData(op, w.key, w.data)
+		if err != nil {
+			err = fmt.Errorf("failed to write SMC key '%s': %w", w.key, err)
+		} else {
+			err = c.verifySMC(w)
+		}
+		if err != nil && originals != nil {
+			return c.rollbackSMC(op, writes[:i+1], originals, err)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verifySMC reads a written key back and checks it against the write.
+func (c *Client) verifySMC(w smcWrite) error {
+	values, err := c.backend.FetchRawData([]string{w.key})
+	if err != nil {
+		return fmt.Errorf("%w: could not read back SMC key '%s': %v", ErrWriteVerification, w.key, err)
+	}
+	got, ok := values[w.key]
+	if !ok {
+		return fmt.Errorf("%w: SMC key '%s' missing on read-back", ErrWriteVerification, w.key)
+	}
+	accept := w.accept
+	if accept == nil {
+		accept = func(b []byte) bool { return bytes.Equal(b, w.data) }
+	}
+	if !accept(got.Data) {
+		return fmt.Errorf("%w: SMC key '%s' reads back % x, expected % x", ErrWriteVerification, w.key, got.Data, w.data)
+	}
+	return nil
+}
+
+// rollbackSMC restores the attempted keys to their original values, newest
+// first, and reports the outcome alongside cause.
+func (c *Client) rollbackSMC(op string, attempted []smcWrite, originals map[string]RawSMCValue, cause error) error {
+	var failed []error
+	restored := make([]string, 0, len(attempted))
+	for i := len(attempted) - 1; i >= 0; i-- {
+		key := attempted[i].key
+		orig, ok := originals[key]
+		if !ok {
+			failed = append(failed, fmt.Errorf("no original value recorded for SMC key '%s'", key))
+			continue
+		}
+		if err := c.auditedWriteData(op+" rollback", key, orig.Data); err != nil {
+			failed = append(failed, fmt.Errorf("could not restore SMC key '%s': %w", key, err))
+			continue
+		}
+		restored = append(restored, key)
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%w; rollback incomplete: %w", cause, errors.Join(failed...))
+	}
+	return fmt.Errorf("%w; rolled back %s", cause, strings.Join(restored, ", "))
+}
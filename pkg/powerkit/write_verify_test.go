@@ -0,0 +1,95 @@
+package powerkit
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// memorySMC is a map-backed SMC. Writes to missing keys fail; stuck keys
+// accept writes but keep their value, as firmware that silently ignores a
+// key would.
+type memorySMC struct {
+	values map[string][]byte
+	stuck  map[string]bool
+	writes []string
+}
+
+func (m *memorySMC) backend(firmware FirmwareInfo) *stubBackend {
+	return &stubBackend{
+		firmware: firmware,
+		fetchSMCRaw: func(keys []string) (map[string]RawSMCValue, error) {
+			out := map[string]RawSMCValue{}
+			for _, key := range keys {
+				if data, ok := m.values[key]; ok {
+					out[key] = RawSMCValue{DataType: "ui8 ", DataSize: len(data), Data: append([]byte(nil), data...)}
+				}
+			}
+			return out, nil
+		},
+		writeSMC: func(key string, data []byte) error {
+			if _, ok := m.values[key]; !ok {
+				return errors.New("key not found")
+			}
+			m.writes = append(m.writes, key)
+			if !m.stuck[key] {
+				m.values[key] = append([]byte(nil), data...)
+			}
+			return nil
+		},
+	}
+}
+
+func TestVerifiedLegacyChargingRollsBack(t *testing.T) {
+	legacy := FirmwareInfo{Major: FirmwareMajorVersionThreshold - 1}
+	newSMC := func() *memorySMC {
+		return &memorySMC{
+			values: map[string][]byte{"BCLM": {0x00}, "BCDS": {0x00}},
+			stuck:  map[string]bool{"BCDS": true},
+		}
+	}
+
+	m := newSMC()
+	client := New(WithBackend(m.backend(legacy)), WithRootCheck(false), WithWriteVerification(true))
+	err := client.SetChargingState(ChargingActionOff)
+	if !errors.Is(err, ErrWriteVerification) {
+		t.Fatalf("expected ErrWriteVerification, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "rolled back") {
+		t.Fatalf("expected rollback to be reported, got %v", err)
+	}
+	if !bytes.Equal(m.values["BCLM"], []byte{0x00}) {
+		t.Fatalf("expected BCLM restored to 00, got % x", m.values["BCLM"])
+	}
+
+	m = newSMC()
+	client = New(WithBackend(m.backend(legacy)), WithRootCheck(false))
+	if err := client.SetChargingState(ChargingActionOff); err != nil {
+		t.Fatalf("unverified write should not read back, got %v", err)
+	}
+	if !bytes.Equal(m.values["BCLM"], []byte{0x02}) {
+		t.Fatalf("expected unverified BCLM write to stand, got % x", m.values["BCLM"])
+	}
+}
+
+func TestVerifiedSingleKeyWrites(t *testing.T) {
+	m := &memorySMC{
+		values: map[string][]byte{"CHIE": {0x00}, "ACLC": {0x04}},
+		stuck:  map[string]bool{"CHIE": true, "ACLC": true},
+	}
+	client := New(WithBackend(m.backend(FirmwareInfo{Major: FirmwareMajorVersionThreshold})), WithRootCheck(false), WithWriteVerification(true))
+
+	if err := client.SetAdapterState(AdapterActionOff); !errors.Is(err, ErrWriteVerification) {
+		t.Fatalf("expected ErrWriteVerification for stuck CHIE, got %v", err)
+	}
+	if len(m.writes) != 1 {
+		t.Fatalf("single-key writes must not roll back, got writes %v", m.writes)
+	}
+	if err := client.SetMagsafeLEDState(LEDSystem); err != nil {
+		t.Fatalf("LEDSystem reads back the resolved color and must verify, got %v", err)
+	}
+	if err := client.SetMagsafeLEDState(LEDGreen); !errors.Is(err, ErrWriteVerification) {
+		t.Fatalf("expected ErrWriteVerification for stuck ACLC, got %v", err)
+	}
+}
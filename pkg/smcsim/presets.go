@@ -0,0 +1,96 @@
+package smcsim
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/peterneutron/powerkit-go/internal/smc"
+	"github.com/peterneutron/powerkit-go/pkg/powerkit"
+)
+
+// sensorAttributes marks the preset sensor keys readable and atomic.
+const sensorAttributes = powerkit.SMCAttrRead | powerkit.SMCAttrAtomic
+
+// Firmware reported by the preset devices. They resolve to powerkit's modern
+// and legacy SMC control profiles respectively.
+var (
+	ModernFirmware = powerkit.FirmwareInfo{
+		Major:   powerkit.FirmwareMajorVersionThreshold,
+		Version: "iBoot-13822.81.10",
+		Source:  "smcsim",
+	}
+	LegacyFirmware = powerkit.FirmwareInfo{
+		Major:   11881,
+		Version: "iBoot-11881.140.96",
+		Source:  "smcsim",
+	}
+)
+
+// NewModern returns a device with the key table of firmware that uses CHTE
+// for charging, CHIE for the adapter and Ftst to unlock fan control, with
+// charging and the adapter enabled and the MagSafe LED under system control.
+func NewModern() *Device {
+	keys := append(sensorKeys(),
+		Key{Name: smc.KeyIsChargingEnabled, DataType: "ui32", Data: []byte{0x00, 0x00, 0x00, 0x00}},
+		Key{Name: smc.KeyIsAdapterEnabled, DataType: "hex_", Data: []byte{0x00}},
+		Key{Name: smc.KeyFanUnlock, DataType: "ui8 ", Data: []byte{0x00}},
+	)
+	d := New(keys...)
+	d.SetFirmware(ModernFirmware)
+	return d
+}
+
+// NewLegacy returns a device with the key table of firmware that uses
+// BCLM/BCDS for charging and CH0B for the adapter, with charging and the
+// adapter enabled and the MagSafe LED under system control.
+func NewLegacy() *Device {
+	keys := append(sensorKeys(),
+		Key{Name: smc.KeyIsChargingEnabledLegacyBCLM, DataType: "ui8 ", Data: []byte{0x00}},
+		Key{Name: smc.KeyIsChargingEnabledLegacyBCDS, DataType: "ui8 ", Data: []byte{0x00}},
+		Key{Name: smc.KeyIsAdapterEnabledLegacy, DataType: "hex_", Data: []byte{0x00}},
+	)
+	d := New(keys...)
+	d.SetFirmware(LegacyFirmware)
+	return d
+}
+
+// sensorKeys returns the keys shared by both presets: a 20 V / 2.5 A adapter
+// input, a 12.5 V battery charging at 1.2 A, the MagSafe LED and one fan
+// idling at 1200 rpm in automatic mode. Sensors are
+// read-only; control keys use the default readable and writable attributes.
+func sensorKeys() []Key {
+	return []Key{
+		{Name: smc.KeyAdapterVoltage, DataType: "flt ", Data: FltBytes(20.0), Attributes: sensorAttributes},
+		{Name: smc.KeyAdapterCurrent, DataType: "flt ", Data: FltBytes(2.5), Attributes: sensorAttributes},
+		{Name: smc.KeyBatteryVoltage, DataType: "si16", Data: Si16Bytes(12500), Attributes: sensorAttributes},
+		{Name: smc.KeyBatteryCurrent, DataType: "si16", Data: Si16Bytes(1200), Attributes: sensorAttributes},
+		{Name: smc.KeyMagsafeLED, DataType: "ui8 ", Data: []byte{0x00}},
+		{Name: smc.KeyFanCount, DataType: "ui8 ", Data: []byte{0x01}, Attributes: sensorAttributes},
+		{Name: "F0Ac", DataType: "flt ", Data: FltBytes(1200), Attributes: sensorAttributes},
+		{Name: "F0Mn", DataType: "flt ", Data: FltBytes(1200), Attributes: sensorAttributes},
+		{Name: "F0Mx", DataType: "flt ", Data: FltBytes(6000), Attributes: sensorAttributes},
+		{Name: "F0Tg", DataType: "flt ", Data: FltBytes(1200)},
+		{Name: "F0Md", DataType: "ui8 ", Data: []byte{0x00}},
+	}
+}
+
+// FltBytes encodes v as an SMC "flt " value.
+func FltBytes(v float32) []byte {
+	b := make([]byte, 4)
+	binary.LittleEndian.PutUint32(b, math.Float32bits(v))
+	return b
+}
+
+// Si16Bytes encodes v as an SMC "si16" value.
+func Si16Bytes(v int16) []byte {
+	b := make([]byte, 2)
+	binary.LittleEndian.PutUint16(b, uint16(v))
+	return b
+}
+
+// Ui32Bytes encodes v as an SMC "ui32" value.
+func Ui32Bytes(v uint32) []byte {
+	b := make([]byte, 4)
+	binary.LittleEndian.PutUint32(b, v)
+	return b
+}
@@ -0,0 +1,257 @@
+// Package smcsim provides an in-memory System Management Controller that
+// follows the read and write semantics of the hardware SMC. A Device plugs
+// into powerkit as the SMC of a CompositeBackend, so charge, adapter and
+// MagSafe control can be exercised on any platform.
+package smcsim
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/peterneutron/powerkit-go/internal/smc"
+	"github.com/peterneutron/powerkit-go/pkg/powerkit"
+)
+
+// maxDataSize is the size of the SMC's key data buffer.
+const maxDataSize = 32
+
+var (
+	// ErrKeyNotFound is returned when writing a key the device does not have,
+	// mirroring a failed key-info lookup on hardware.
+	ErrKeyNotFound = errors.New("SMC key not found")
+	// ErrBadArgument is returned when the written data size does not match
+	// the key's size, mirroring kIOReturnBadArgument.
+	ErrBadArgument = errors.New("SMC bad argument")
+	// ErrNotWritable is returned when writing a key without the write
+	// attribute.
+	ErrNotWritable = errors.New("SMC key not writable")
+)
+
+// defaultAttributes applies to keys created with zero Attributes.
+const defaultAttributes = powerkit.SMCAttrRead | powerkit.SMCAttrWrite
+
+// Key is one entry in a Device's key table.
+type Key struct {
+	// Name is the four-character key, for example "CHTE".
+	Name string
+	// DataType is the four-character type, for example "ui32" or "flt ".
+	DataType string
+	// Data holds the current value; its length is the key's fixed size.
+	Data []byte
+	// Attributes is the SMC attribute byte (powerkit.SMCAttr*). Zero means
+	// readable and writable.
+	Attributes uint8
+}
+
+// Device is an in-memory SMC. It is safe for concurrent use.
+type Device struct {
+	mu       sync.Mutex
+	keys     map[string]Key
+	firmware powerkit.FirmwareInfo
+}
+
+var (
+	_ powerkit.SMCDevice      = (*Device)(nil)
+	_ powerkit.SMCCatalog     = (*Device)(nil)
+	_ powerkit.FirmwareReader = (*Device)(nil)
+)
+
+// New returns a Device holding the given keys and reporting unknown
+// firmware. It panics if a key is malformed; use Set to add keys at runtime.
+func New(keys ...Key) *Device {
+	d := &Device{
+		keys:     make(map[string]Key, len(keys)),
+		firmware: powerkit.FirmwareInfo{Source: "unknown"},
+	}
+	for _, k := range keys {
+		if err := d.Set(k.Name, k.DataType, k.Data); err != nil {
+			panic(err)
+		}
+		if k.Attributes != 0 {
+			d.SetAttributes(k.Name, k.Attributes)
+		}
+	}
+	return d
+}
+
+// Set adds or replaces a key. The key and type must be four characters and
+// the data at most 32 bytes. A replaced key keeps its attributes.
+func (d *Device) Set(name, dataType string, data []byte) error {
+	switch {
+	case len(name) != 4:
+		return fmt.Errorf("invalid SMC key %q: must be 4 characters", name)
+	case len(dataType) != 4:
+		return fmt.Errorf("invalid data type %q for key %s: must be 4 characters", dataType, name)
+	case len(data) > maxDataSize:
+		return fmt.Errorf("invalid data size %d for key %s: maximum is %d", len(data), name, maxDataSize)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	attrs := defaultAttributes
+	if k, ok := d.keys[name]; ok {
+		attrs = k.Attributes
+	}
+	d.keys[name] = Key{Name: name, DataType: dataType, Data: append([]byte(nil), data...), Attributes: attrs}
+	return nil
+}
+
+// SetAttributes replaces a key's attribute byte. It reports whether the key
+// exists.
+func (d *Device) SetAttributes(name string, attrs uint8) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	k, ok := d.keys[name]
+	if !ok {
+		return false
+	}
+	k.Attributes = attrs
+	d.keys[name] = k
+	return true
+}
+
+// Key returns a copy of the named key.
+func (d *Device) Key(name string) (Key, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	k, ok := d.keys[name]
+	if !ok {
+		return Key{}, false
+	}
+	k.Data = append([]byte(nil), k.Data...)
+	return k, true
+}
+
+// Keys returns the names of all keys, sorted.
+func (d *Device) Keys() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	names := make([]string, 0, len(d.keys))
+	for name := range d.keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetFirmware sets the firmware reported to powerkit's profile resolver.
+func (d *Device) SetFirmware(info powerkit.FirmwareInfo) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.firmware = info
+}
+
+// FirmwareInfo implements powerkit.FirmwareReader.
+func (d *Device) FirmwareInfo() powerkit.FirmwareInfo {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.firmware
+}
+
+// Backend returns a powerkit backend whose SMC and firmware are this device.
+func (d *Device) Backend() *powerkit.CompositeBackend {
+	return &powerkit.CompositeBackend{SMC: d, Firmware: d}
+}
+
+// FetchRawData implements powerkit.SMCReader. Missing keys are omitted, and
+// the call fails only when none of the requested keys exist.
+func (d *Device) FetchRawData(keys []string) (map[string]powerkit.RawSMCValue, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	results := make(map[string]powerkit.RawSMCValue, len(keys))
+	for _, name := range keys {
+		k, ok := d.keys[name]
+		if !ok {
+			continue
+		}
+		results[name] = powerkit.RawSMCValue{
+			DataType: k.DataType,
+			DataSize: len(k.Data),
+			Data:     append([]byte(nil), k.Data...),
+		}
+	}
+
+	if len(results) == 0 {
+		return nil, fmt.Errorf("SMC raw read failed for all requested keys")
+	}
+	return results, nil
+}
+
+// ListSMCKeys implements powerkit.SMCCatalog, listing keys sorted by name.
+func (d *Device) ListSMCKeys() ([]powerkit.SMCKeyInfo, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	keys := make([]powerkit.SMCKeyInfo, 0, len(d.keys))
+	for _, k := range d.keys {
+		keys = append(keys, keyInfo(k))
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Key < keys[j].Key })
+	return keys, nil
+}
+
+// FetchKeyInfo implements powerkit.SMCCatalog. Missing keys are omitted, and
+// the call fails only when none of the requested keys exist.
+func (d *Device) FetchKeyInfo(keys []string) (map[string]powerkit.SMCKeyInfo, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	results := make(map[string]powerkit.SMCKeyInfo, len(keys))
+	for _, name := range keys {
+		if k, ok := d.keys[name]; ok {
+			results[name] = keyInfo(k)
+		}
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("SMC key info read failed for all requested keys")
+	}
+	return results, nil
+}
+
+func keyInfo(k Key) powerkit.SMCKeyInfo {
+	return powerkit.SMCKeyInfo{Key: k.Name, DataType: k.DataType, DataSize: len(k.Data), Attributes: k.Attributes}
+}
+
+// FetchData implements powerkit.SMCReader using the same decoders as the
+// hardware SMC.
+func (d *Device) FetchData(keys []string) (map[string]float64, error) {
+	rawResults, err := d.FetchRawData(keys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch raw SMC data: %w", err)
+	}
+
+	internal := make(map[string]smc.RawSMCValue, len(rawResults))
+	for key, val := range rawResults {
+		internal[key] = smc.RawSMCValue{DataType: val.DataType, DataSize: val.DataSize, Data: val.Data}
+	}
+	return smc.DecodeRawData(internal)
+}
+
+// WriteData implements powerkit.SMCWriter. As on hardware, the key must exist
+// and be writable, and the data must match the key's size exactly.
+func (d *Device) WriteData(key string, data []byte) error {
+	if len(data) == 0 {
+		return fmt.Errorf("cannot write empty data slice")
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	k, ok := d.keys[key]
+	if !ok {
+		return fmt.Errorf("SMC write failed for key '%s': %w", key, ErrKeyNotFound)
+	}
+	if k.Attributes&powerkit.SMCAttrWrite == 0 {
+		return fmt.Errorf("SMC write failed for key '%s': %w", key, ErrNotWritable)
+	}
+	if len(data) != len(k.Data) {
+		return fmt.Errorf("SMC write failed for key '%s': provided data size does not match key's expected size: %w", key, ErrBadArgument)
+	}
+
+	k.Data = append([]byte(nil), data...)
+	d.keys[key] = k
+	return nil
+}
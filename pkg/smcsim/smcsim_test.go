@@ -0,0 +1,260 @@
+package smcsim
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/peterneutron/powerkit-go/internal/smc"
+	"github.com/peterneutron/powerkit-go/pkg/powerkit"
+)
+
+func TestWriteDataValidatesSize(t *testing.T) {
+	d := NewModern()
+
+	if err := d.WriteData(smc.KeyIsChargingEnabled, []byte{0x01}); !errors.Is(err, ErrBadArgument) {
+		t.Fatalf("expected ErrBadArgument for short write, got %v", err)
+	}
+	if err := d.WriteData("ZZZZ", []byte{0x01}); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("expected ErrKeyNotFound, got %v", err)
+	}
+	if err := d.WriteData(smc.KeyIsChargingEnabled, nil); err == nil {
+		t.Fatalf("expected error for empty write")
+	}
+	if err := d.WriteData(smc.KeyIsChargingEnabled, Ui32Bytes(1)); err != nil {
+		t.Fatalf("WriteData returned error: %v", err)
+	}
+	if k, _ := d.Key(smc.KeyIsChargingEnabled); !bytes.Equal(k.Data, []byte{0x01, 0x00, 0x00, 0x00}) {
+		t.Fatalf("unexpected CHTE after write: % x", k.Data)
+	}
+}
+
+func TestFetchDataDecodesAndOmitsMissingKeys(t *testing.T) {
+	d := NewModern()
+
+	values, err := d.FetchData([]string{smc.KeyAdapterVoltage, smc.KeyBatteryCurrent, "ZZZZ"})
+	if err != nil {
+		t.Fatalf("FetchData returned error: %v", err)
+	}
+	if values[smc.KeyAdapterVoltage] != 20 || values[smc.KeyBatteryCurrent] != 1200 {
+		t.Fatalf("unexpected decoded values: %v", values)
+	}
+	if _, ok := values["ZZZZ"]; ok {
+		t.Fatalf("expected missing key to be omitted")
+	}
+	if _, err := d.FetchRawData([]string{"ZZZZ"}); err == nil {
+		t.Fatalf("expected error when no requested key exists")
+	}
+}
+
+func TestSetRejectsMalformedKeys(t *testing.T) {
+	d := New()
+	if err := d.Set("TOOLONG", "ui8 ", []byte{0}); err == nil {
+		t.Fatalf("expected error for long key name")
+	}
+	if err := d.Set("ABCD", "ui8", []byte{0}); err == nil {
+		t.Fatalf("expected error for short data type")
+	}
+	if err := d.Set("ABCD", "hex_", make([]byte, 33)); err == nil {
+		t.Fatalf("expected error for oversized data")
+	}
+}
+
+func TestModernProfileEndToEnd(t *testing.T) {
+	d := NewModern()
+	client := powerkit.New(powerkit.WithBackend(d.Backend()), powerkit.WithRootCheck(false))
+
+	if err := client.SetChargingState(powerkit.ChargingActionOff); err != nil {
+		t.Fatalf("SetChargingState returned error: %v", err)
+	}
+	if err := client.SetAdapterState(powerkit.AdapterActionToggle); err != nil {
+		t.Fatalf("SetAdapterState returned error: %v", err)
+	}
+	if k, _ := d.Key(smc.KeyIsAdapterEnabled); !bytes.Equal(k.Data, []byte{0x08}) {
+		t.Fatalf("expected CHIE disabled after toggle, got % x", k.Data)
+	}
+
+	info, err := client.GetSystemInfo(powerkit.FetchOptions{QuerySMC: true})
+	if err != nil {
+		t.Fatalf("GetSystemInfo returned error: %v", err)
+	}
+	if info.SMC.State.IsChargingEnabled || info.SMC.State.IsAdapterEnabled {
+		t.Fatalf("expected charging and adapter disabled, got %+v", info.SMC.State)
+	}
+	if info.SMC.Adapter.InputVoltage != 20 || info.SMC.Battery.Voltage != 12.5 {
+		t.Fatalf("unexpected SMC telemetry: %+v", info.SMC)
+	}
+
+	if err := client.SetMagsafeLEDState(powerkit.LEDAmber); err != nil {
+		t.Fatalf("SetMagsafeLEDState returned error: %v", err)
+	}
+	if charging, err := client.IsMagsafeCharging(); err != nil || !charging {
+		t.Fatalf("expected amber MagSafe LED, got charging=%v err=%v", charging, err)
+	}
+}
+
+func TestLegacyProfileEndToEnd(t *testing.T) {
+	d := NewLegacy()
+	client := powerkit.New(powerkit.WithBackend(d.Backend()), powerkit.WithRootCheck(false))
+
+	if err := client.SetChargingState(powerkit.ChargingActionOff); err != nil {
+		t.Fatalf("SetChargingState returned error: %v", err)
+	}
+	for _, key := range []string{smc.KeyIsChargingEnabledLegacyBCLM, smc.KeyIsChargingEnabledLegacyBCDS} {
+		if k, _ := d.Key(key); !bytes.Equal(k.Data, []byte{0x02}) {
+			t.Fatalf("expected %s disabled, got % x", key, k.Data)
+		}
+	}
+
+	if err := client.SetChargingState(powerkit.ChargingActionToggle); err != nil {
+		t.Fatalf("toggle returned error: %v", err)
+	}
+	if err := client.SetAdapterState(powerkit.AdapterActionOff); err != nil {
+		t.Fatalf("SetAdapterState returned error: %v", err)
+	}
+
+	info, err := client.GetSystemInfo(powerkit.FetchOptions{QuerySMC: true})
+	if err != nil {
+		t.Fatalf("GetSystemInfo returned error: %v", err)
+	}
+	if !info.SMC.State.IsChargingEnabled || info.SMC.State.IsAdapterEnabled {
+		t.Fatalf("expected charging enabled and adapter disabled, got %+v", info.SMC.State)
+	}
+}
+
+func TestListSMCKeysThroughBackend(t *testing.T) {
+	d := NewModern()
+	client := powerkit.New(powerkit.WithBackend(d.Backend()))
+
+	keys, err := client.ListSMCKeys()
+	if err != nil {
+		t.Fatalf("ListSMCKeys returned error: %v", err)
+	}
+	if len(keys) == 0 {
+		t.Fatalf("expected simulated keys to be listed")
+	}
+	for i, k := range keys {
+		if i > 0 && keys[i-1].Key >= k.Key {
+			t.Fatalf("expected keys sorted by name, got %s before %s", keys[i-1].Key, k.Key)
+		}
+		if k.Key == smc.KeyIsChargingEnabled && (k.DataType != "ui32" || k.DataSize != 4) {
+			t.Fatalf("unexpected CHTE entry: %+v", k)
+		}
+	}
+}
+
+func TestKeyAttributesGateWrites(t *testing.T) {
+	d := NewModern()
+	client := powerkit.New(powerkit.WithBackend(d.Backend()), powerkit.WithRootCheck(false))
+
+	infos, err := client.GetSMCKeyInfo([]string{smc.KeyIsChargingEnabled, smc.KeyBatteryVoltage})
+	if err != nil {
+		t.Fatalf("GetSMCKeyInfo returned error: %v", err)
+	}
+	if !infos[smc.KeyIsChargingEnabled].Flags.Writable || infos[smc.KeyBatteryVoltage].Flags.Writable {
+		t.Fatalf("expected CHTE writable and B0AV read-only, got %+v", infos)
+	}
+	if err := d.WriteData(smc.KeyBatteryVoltage, Si16Bytes(1)); !errors.Is(err, ErrNotWritable) {
+		t.Fatalf("expected ErrNotWritable for a sensor key, got %v", err)
+	}
+
+	info, err := client.GetSystemInfo(powerkit.FetchOptions{QuerySMC: true})
+	if err != nil || !info.ToJSON().Controls.Capabilities.CanWriteSMC {
+		t.Fatalf("expected can_write_smc with writable control keys, err=%v", err)
+	}
+
+	d.SetAttributes(smc.KeyIsChargingEnabled, powerkit.SMCAttrRead)
+	info, err = client.GetSystemInfo(powerkit.FetchOptions{QuerySMC: true})
+	if err != nil || info.ToJSON().Controls.Capabilities.CanWriteSMC {
+		t.Fatalf("expected can_write_smc false with a read-only CHTE, err=%v", err)
+	}
+	if err := client.SetChargingState(powerkit.ChargingActionOff); !errors.Is(err, ErrNotWritable) {
+		t.Fatalf("expected ErrNotWritable from a read-only CHTE, got %v", err)
+	}
+}
+
+func TestFanControlEndToEnd(t *testing.T) {
+	d := NewModern()
+	client := powerkit.New(powerkit.WithBackend(d.Backend()), powerkit.WithRootCheck(false))
+
+	fans, err := client.GetFans()
+	if err != nil {
+		t.Fatalf("GetFans returned error: %v", err)
+	}
+	want := powerkit.FanInfo{Index: 0, ActualRPM: 1200, MinRPM: 1200, MaxRPM: 6000, TargetRPM: 1200, Mode: powerkit.FanModeAuto}
+	if len(fans) != 1 || fans[0] != want {
+		t.Fatalf("expected %+v, got %+v", want, fans)
+	}
+
+	if err := client.SetFanSpeed(0, 9000); err == nil {
+		t.Fatalf("expected a speed above the maximum to be rejected")
+	}
+	if err := client.SetFanSpeed(1, 3000); err == nil {
+		t.Fatalf("expected an out-of-range fan index to be rejected")
+	}
+	if err := client.SetFanSpeed(0, 3000); err != nil {
+		t.Fatalf("SetFanSpeed returned error: %v", err)
+	}
+	if k, _ := d.Key(smc.KeyFanUnlock); !bytes.Equal(k.Data, []byte{0x01}) {
+		t.Fatalf("expected Ftst unlocked, got % x", k.Data)
+	}
+	fans, _ = client.GetFans()
+	if fans[0].Mode != powerkit.FanModeManual || fans[0].TargetRPM != 3000 {
+		t.Fatalf("expected fan forced to 3000 rpm, got %+v", fans[0])
+	}
+
+	if err := client.SetFanAuto(0); err != nil {
+		t.Fatalf("SetFanAuto returned error: %v", err)
+	}
+	fans, _ = client.GetFans()
+	if fans[0].Mode != powerkit.FanModeAuto {
+		t.Fatalf("expected automatic mode, got %+v", fans[0])
+	}
+	if k, _ := d.Key(smc.KeyFanUnlock); !bytes.Equal(k.Data, []byte{0x00}) {
+		t.Fatalf("expected Ftst locked again, got % x", k.Data)
+	}
+}
+
+func TestWritePolicyGatesRawFanTargets(t *testing.T) {
+	d := NewModern()
+	client := powerkit.New(powerkit.WithBackend(d.Backend()), powerkit.WithRootCheck(false),
+		powerkit.WithWritePolicy(powerkit.DefaultWritePolicy()))
+
+	if err := client.WriteSMCValue("F0Tg", 0); !errors.Is(err, powerkit.ErrWriteDenied) {
+		t.Fatalf("expected ErrWriteDenied for a raw fan target, got %v", err)
+	}
+	if err := client.SetFanSpeed(0, 3000); err != nil {
+		t.Fatalf("SetFanSpeed returned error: %v", err)
+	}
+	if fans, _ := client.GetFans(); fans[0].TargetRPM != 3000 {
+		t.Fatalf("expected the range-checked target written, got %+v", fans[0])
+	}
+}
+
+func TestFailedFanSpeedRelocksFanControl(t *testing.T) {
+	d := NewModern()
+	client := powerkit.New(powerkit.WithBackend(d.Backend()), powerkit.WithRootCheck(false))
+	d.SetAttributes("F0Tg", powerkit.SMCAttrRead)
+
+	if err := client.SetFanSpeed(0, 3000); !errors.Is(err, ErrNotWritable) {
+		t.Fatalf("expected ErrNotWritable from the target write, got %v", err)
+	}
+	if k, _ := d.Key(smc.KeyFanUnlock); !bytes.Equal(k.Data, []byte{0x00}) {
+		t.Fatalf("expected Ftst locked after the failed write, got % x", k.Data)
+	}
+	if k, _ := d.Key("F0Md"); !bytes.Equal(k.Data, []byte{0x00}) {
+		t.Fatalf("expected F0Md back in automatic mode, got % x", k.Data)
+	}
+}
+
+func TestLegacyFanControlNeedsNoUnlock(t *testing.T) {
+	d := NewLegacy()
+	client := powerkit.New(powerkit.WithBackend(d.Backend()), powerkit.WithRootCheck(false))
+
+	if err := client.SetFanSpeed(0, 2500); err != nil {
+		t.Fatalf("SetFanSpeed returned error: %v", err)
+	}
+	if k, _ := d.Key("F0Md"); !bytes.Equal(k.Data, []byte{0x01}) {
+		t.Fatalf("expected F0Md manual, got % x", k.Data)
+	}
+}